@@ -63,6 +63,12 @@ func main() {
 	// Create Gin router
 	r := gin.New()
 
+	// Only trust X-Forwarded-For from configured proxies, so clients cannot
+	// choose the IP used by the login limits
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Setup routes
 	routes.SetupRoutes(r, db)
 
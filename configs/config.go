@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -14,12 +15,19 @@ type Config struct {
 	Database DatabaseConfig
 	JWT      JWTConfig
 	Upload   UploadConfig
+	School   SchoolConfig
+	Ranking  RankingConfig
+	Mail     MailConfig
+	Login    LoginConfig
 }
 
-// ServerConfig holds server configuration
+// ServerConfig holds server configuration. TrustedProxies lists the reverse
+// proxies whose X-Forwarded-For header gives the client IP; when empty the
+// connecting address is used.
 type ServerConfig struct {
-	Port string
-	Mode string
+	Port           string
+	Mode           string
+	TrustedProxies []string
 }
 
 // DatabaseConfig holds database configuration
@@ -31,10 +39,12 @@ type DatabaseConfig struct {
 	Name     string
 }
 
-// JWTConfig holds JWT configuration
+// JWTConfig holds JWT configuration: short-lived access tokens and the
+// lifetime of the refresh tokens that renew them
 type JWTConfig struct {
-	Secret      string
-	ExpiryHours int
+	Secret            string
+	ExpiryMinutes     int
+	RefreshExpiryDays int
 }
 
 // UploadConfig holds file upload configuration
@@ -43,6 +53,49 @@ type UploadConfig struct {
 	MaxSize int64
 }
 
+// SchoolConfig holds the school identity printed on reports
+type SchoolConfig struct {
+	Name         string
+	NPSN         string
+	Address      string
+	City         string
+	Principal    string
+	PrincipalNIP string
+}
+
+// RankingConfig holds the weights of the average used for peringkat: pengetahuan
+// against keterampilan within a subject, and per subject group (A, B, C)
+type RankingConfig struct {
+	WeightPengetahuan  float64
+	WeightKeterampilan float64
+	WeightKelompok     map[string]float64
+}
+
+// MailConfig holds the outgoing mail configuration. Driver "smtp" sends through
+// an SMTP server; "file" writes each message to Dir for offline setups.
+// ResetURL is the frontend page a password reset link points to.
+type MailConfig struct {
+	Driver             string
+	Host               string
+	Port               string
+	Username           string
+	Password           string
+	From               string
+	Dir                string
+	ResetURL           string
+	ResetExpiryMinutes int
+}
+
+// LoginConfig holds the brute-force protection of the login: an account is
+// locked for LockoutMinutes after MaxFailures consecutive failures, and an IP
+// address is blocked after IPMaxFailures failures within IPWindowMinutes
+type LoginConfig struct {
+	MaxFailures     int
+	LockoutMinutes  int
+	IPMaxFailures   int
+	IPWindowMinutes int
+}
+
 var AppConfig *Config
 
 // LoadConfig loads configuration from environment variables
@@ -52,13 +105,20 @@ func LoadConfig() *Config {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	expiryHours, _ := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
+	expiryMinutes, _ := strconv.Atoi(getEnv("JWT_EXPIRY_MINUTES", "15"))
+	refreshExpiryDays, _ := strconv.Atoi(getEnv("JWT_REFRESH_EXPIRY_DAYS", "30"))
+	resetExpiryMinutes, _ := strconv.Atoi(getEnv("PASSWORD_RESET_EXPIRY_MINUTES", "60"))
+	loginMaxFailures, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES", "5"))
+	loginLockoutMinutes, _ := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MINUTES", "15"))
+	loginIPMaxFailures, _ := strconv.Atoi(getEnv("LOGIN_IP_MAX_FAILURES", "20"))
+	loginIPWindowMinutes, _ := strconv.Atoi(getEnv("LOGIN_IP_WINDOW_MINUTES", "15"))
 	maxFileSize, _ := strconv.ParseInt(getEnv("MAX_FILE_SIZE", "5242880"), 10, 64)
 
 	AppConfig = &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Mode: getEnv("SERVER_MODE", "development"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			Mode:           getEnv("SERVER_MODE", "development"),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -68,13 +128,48 @@ func LoadConfig() *Config {
 			Name:     getEnv("DB_NAME", "db_siswa_induk_api"),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "default-secret-change-this"),
-			ExpiryHours: expiryHours,
+			Secret:            getEnv("JWT_SECRET", "default-secret-change-this"),
+			ExpiryMinutes:     expiryMinutes,
+			RefreshExpiryDays: refreshExpiryDays,
 		},
 		Upload: UploadConfig{
 			Path:    getEnv("UPLOAD_PATH", "./uploads"),
 			MaxSize: maxFileSize,
 		},
+		School: SchoolConfig{
+			Name:         getEnv("SCHOOL_NAME", ""),
+			NPSN:         getEnv("SCHOOL_NPSN", ""),
+			Address:      getEnv("SCHOOL_ADDRESS", ""),
+			City:         getEnv("SCHOOL_CITY", ""),
+			Principal:    getEnv("SCHOOL_PRINCIPAL", ""),
+			PrincipalNIP: getEnv("SCHOOL_PRINCIPAL_NIP", ""),
+		},
+		Ranking: RankingConfig{
+			WeightPengetahuan:  getEnvFloat("RANKING_WEIGHT_PENGETAHUAN", 1),
+			WeightKeterampilan: getEnvFloat("RANKING_WEIGHT_KETERAMPILAN", 1),
+			WeightKelompok: map[string]float64{
+				"A": getEnvFloat("RANKING_WEIGHT_KELOMPOK_A", 1),
+				"B": getEnvFloat("RANKING_WEIGHT_KELOMPOK_B", 1),
+				"C": getEnvFloat("RANKING_WEIGHT_KELOMPOK_C", 1),
+			},
+		},
+		Mail: MailConfig{
+			Driver:             getEnv("MAIL_DRIVER", "file"),
+			Host:               getEnv("SMTP_HOST", ""),
+			Port:               getEnv("SMTP_PORT", "587"),
+			Username:           getEnv("SMTP_USERNAME", ""),
+			Password:           getEnv("SMTP_PASSWORD", ""),
+			From:               getEnv("MAIL_FROM", "no-reply@siswa.local"),
+			Dir:                getEnv("MAIL_DIR", "./storage/mail"),
+			ResetURL:           getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			ResetExpiryMinutes: resetExpiryMinutes,
+		},
+		Login: LoginConfig{
+			MaxFailures:     loginMaxFailures,
+			LockoutMinutes:  loginLockoutMinutes,
+			IPMaxFailures:   loginIPMaxFailures,
+			IPWindowMinutes: loginIPWindowMinutes,
+		},
 	}
 
 	return AppConfig
@@ -87,3 +182,23 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvFloat gets a numeric environment variable with fallback
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvList gets a comma-separated environment variable, skipping empty items
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,38 @@
+-- =============================================
+-- MIGRATION 002: Rombongan Belajar (Rombel)
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: rombel
+-- =============================================
+CREATE TABLE rombel (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    nama VARCHAR(50) NOT NULL COMMENT 'Contoh: XI TKJ 2',
+    tingkat ENUM('X', 'XI', 'XII') NOT NULL,
+    jurusan VARCHAR(100),
+    tahun_pelajaran VARCHAR(20) NOT NULL COMMENT '2024/2025',
+    wali_kelas_id BIGINT UNSIGNED NULL COMMENT 'User wali kelas',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (wali_kelas_id) REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE INDEX idx_rombel_nama_tahun (nama, tahun_pelajaran),
+    INDEX idx_rombel_tahun (tahun_pelajaran),
+    INDEX idx_rombel_wali (wali_kelas_id)
+) ENGINE=InnoDB;
+
+-- =============================================
+-- TABLE: anggota_rombel
+-- =============================================
+CREATE TABLE anggota_rombel (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    rombel_id BIGINT UNSIGNED NOT NULL,
+    siswa_id BIGINT UNSIGNED NOT NULL,
+    tahun_pelajaran VARCHAR(20) NOT NULL COMMENT 'Salinan dari rombel, satu rombel per siswa per tahun',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (rombel_id) REFERENCES rombel(id) ON DELETE CASCADE,
+    FOREIGN KEY (siswa_id) REFERENCES siswa(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_anggota_siswa_tahun (siswa_id, tahun_pelajaran),
+    INDEX idx_anggota_rombel (rombel_id)
+) ENGINE=InnoDB;
@@ -0,0 +1,36 @@
+-- =============================================
+-- MIGRATION 003: Tahun Pelajaran (Master Data)
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: tahun_pelajaran
+-- =============================================
+CREATE TABLE tahun_pelajaran (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    label VARCHAR(20) NOT NULL UNIQUE COMMENT '2024/2025',
+    tanggal_mulai DATE NOT NULL,
+    tanggal_selesai DATE NOT NULL,
+    is_active BOOLEAN DEFAULT FALSE COMMENT 'Hanya satu tahun pelajaran aktif',
+    semester_aktif TINYINT UNSIGNED DEFAULT 1 COMMENT '1 = Ganjil, 2 = Genap',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tahun_pelajaran_active (is_active)
+) ENGINE=InnoDB;
+
+-- =============================================
+-- SEED: tahun pelajaran dari data yang sudah ada
+-- =============================================
+INSERT IGNORE INTO tahun_pelajaran (label, tanggal_mulai, tanggal_selesai)
+SELECT DISTINCT
+    TRIM(tahun_pelajaran),
+    STR_TO_DATE(CONCAT(LEFT(TRIM(tahun_pelajaran), 4), '-07-01'), '%Y-%m-%d'),
+    STR_TO_DATE(CONCAT(RIGHT(TRIM(tahun_pelajaran), 4), '-06-30'), '%Y-%m-%d')
+FROM (
+    SELECT tahun_pelajaran FROM nilai_semester
+    UNION SELECT tahun_pelajaran FROM kepribadian
+    UNION SELECT tahun_pelajaran FROM beasiswa
+    UNION SELECT tahun_pelajaran FROM rombel
+) existing
+WHERE TRIM(tahun_pelajaran) REGEXP '^[0-9]{4}/[0-9]{4}$';
@@ -0,0 +1,25 @@
+-- =============================================
+-- MIGRATION 004: Kenaikan Kelas
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: kenaikan_kelas
+-- =============================================
+CREATE TABLE kenaikan_kelas (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    siswa_id BIGINT UNSIGNED NOT NULL,
+    tahun_pelajaran VARCHAR(20) NOT NULL COMMENT 'Tahun pelajaran yang dievaluasi',
+    rombel_id BIGINT UNSIGNED NULL COMMENT 'Rombel asal',
+    tingkat_asal ENUM('X', 'XI', 'XII') NOT NULL,
+    tingkat_tujuan ENUM('X', 'XI', 'XII') NOT NULL,
+    keputusan ENUM('Naik', 'Tinggal') NOT NULL,
+    catatan TEXT,
+    tanggal DATE NOT NULL COMMENT 'Tanggal keputusan rapat kenaikan kelas',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (siswa_id) REFERENCES siswa(id) ON DELETE CASCADE,
+    FOREIGN KEY (rombel_id) REFERENCES rombel(id) ON DELETE SET NULL,
+    UNIQUE INDEX idx_kenaikan_siswa_tahun (siswa_id, tahun_pelajaran),
+    INDEX idx_kenaikan_rombel (rombel_id)
+) ENGINE=InnoDB;
@@ -0,0 +1,24 @@
+-- =============================================
+-- MIGRATION 005: Kehadiran Harian
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: kehadiran_harian
+-- Rekap kehadiran (tabel kehadiran) dihitung ulang dari tabel ini
+-- =============================================
+CREATE TABLE kehadiran_harian (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    siswa_id BIGINT UNSIGNED NOT NULL,
+    tanggal DATE NOT NULL,
+    kelas ENUM('X', 'XI', 'XII') NOT NULL,
+    semester TINYINT UNSIGNED NOT NULL,
+    status ENUM('hadir', 'sakit', 'izin', 'alpa') NOT NULL,
+    keterangan TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (siswa_id) REFERENCES siswa(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_kehadiran_harian_siswa_tanggal (siswa_id, tanggal),
+    INDEX idx_kehadiran_harian_periode (siswa_id, kelas, semester)
+) ENGINE=InnoDB;
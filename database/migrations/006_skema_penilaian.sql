@@ -0,0 +1,23 @@
+-- =============================================
+-- MIGRATION 006: Skema Penilaian (KKM & Predikat)
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: skema_penilaian
+-- Predikat: nilai >= batas_a -> A, >= batas_b -> B, >= batas_c -> C, selain itu D
+-- =============================================
+CREATE TABLE skema_penilaian (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    tahun_pelajaran VARCHAR(20) NOT NULL,
+    mata_pelajaran_id BIGINT UNSIGNED NULL COMMENT 'NULL = skema default tahun pelajaran',
+    kkm INT UNSIGNED NOT NULL COMMENT 'Kriteria Ketuntasan Minimal',
+    batas_a INT UNSIGNED NOT NULL,
+    batas_b INT UNSIGNED NOT NULL,
+    batas_c INT UNSIGNED NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (mata_pelajaran_id) REFERENCES mata_pelajaran(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_skema_tahun_mapel (tahun_pelajaran, mata_pelajaran_id)
+) ENGINE=InnoDB;
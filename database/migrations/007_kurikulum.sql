@@ -0,0 +1,24 @@
+-- =============================================
+-- MIGRATION 007: Kurikulum per Jurusan
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: kurikulum
+-- Struktur mapel per jurusan, tingkat dan semester
+-- =============================================
+CREATE TABLE kurikulum (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    jurusan VARCHAR(100) NOT NULL COMMENT 'Sama dengan rombel.jurusan',
+    tingkat ENUM('X', 'XI', 'XII') NOT NULL,
+    semester TINYINT UNSIGNED NOT NULL,
+    mata_pelajaran_id BIGINT UNSIGNED NOT NULL,
+    urutan INT UNSIGNED DEFAULT 0 COMMENT 'Urutan tampil di rapor',
+    menit_per_minggu INT UNSIGNED DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (mata_pelajaran_id) REFERENCES mata_pelajaran(id) ON DELETE RESTRICT,
+    UNIQUE INDEX idx_kurikulum_unique (jurusan, tingkat, semester, mata_pelajaran_id),
+    INDEX idx_kurikulum_mapel (mata_pelajaran_id)
+) ENGINE=InnoDB;
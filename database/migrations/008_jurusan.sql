@@ -0,0 +1,109 @@
+-- =============================================
+-- MIGRATION 008: Bidang, Program & Kompetensi Keahlian
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: bidang_keahlian
+-- =============================================
+CREATE TABLE bidang_keahlian (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    kode VARCHAR(20) NOT NULL UNIQUE,
+    nama VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+
+-- =============================================
+-- TABLE: program_keahlian
+-- =============================================
+CREATE TABLE program_keahlian (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    bidang_keahlian_id BIGINT UNSIGNED NOT NULL,
+    kode VARCHAR(20) NOT NULL UNIQUE,
+    nama VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (bidang_keahlian_id) REFERENCES bidang_keahlian(id) ON DELETE RESTRICT,
+    INDEX idx_program_bidang (bidang_keahlian_id)
+) ENGINE=InnoDB;
+
+-- =============================================
+-- TABLE: kompetensi_keahlian
+-- Jurusan tempat siswa terdaftar
+-- =============================================
+CREATE TABLE kompetensi_keahlian (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    program_keahlian_id BIGINT UNSIGNED NOT NULL,
+    kode VARCHAR(20) NOT NULL UNIQUE,
+    nama VARCHAR(100) NOT NULL,
+    singkatan VARCHAR(20) COMMENT 'Contoh: TKJ, RPL',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (program_keahlian_id) REFERENCES program_keahlian(id) ON DELETE RESTRICT,
+    INDEX idx_kompetensi_program (program_keahlian_id)
+) ENGINE=InnoDB;
+
+-- =============================================
+-- ALTER: siswa.kompetensi_keahlian_id
+-- Jurusan siswa saat ini
+-- =============================================
+ALTER TABLE siswa
+    ADD COLUMN kompetensi_keahlian_id BIGINT UNSIGNED NULL AFTER foto_path,
+    ADD INDEX idx_siswa_kompetensi (kompetensi_keahlian_id),
+    ADD FOREIGN KEY (kompetensi_keahlian_id) REFERENCES kompetensi_keahlian(id) ON DELETE RESTRICT;
+
+-- =============================================
+-- TABLE: riwayat_jurusan
+-- Riwayat perpindahan jurusan, baris aktif tanpa tanggal_selesai
+-- =============================================
+CREATE TABLE riwayat_jurusan (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    siswa_id BIGINT UNSIGNED NOT NULL,
+    kompetensi_keahlian_id BIGINT UNSIGNED NOT NULL,
+    tanggal_mulai DATE NOT NULL,
+    tanggal_selesai DATE NULL,
+    keterangan TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (siswa_id) REFERENCES siswa(id) ON DELETE CASCADE,
+    FOREIGN KEY (kompetensi_keahlian_id) REFERENCES kompetensi_keahlian(id) ON DELETE RESTRICT,
+    INDEX idx_riwayat_jurusan_siswa (siswa_id)
+) ENGINE=InnoDB;
+
+-- =============================================
+-- ALTER: kurikulum dikunci ke kompetensi_keahlian
+-- Setiap teks jurusan kurikulum lama menjadi kompetensi keahlian di bawah
+-- bidang & program "Belum dipetakan" (kode MIGRASI). Rapikan nama, kode,
+-- dan programnya lewat /kompetensi-keahlian setelah migrasi.
+-- =============================================
+INSERT INTO bidang_keahlian (kode, nama)
+SELECT 'MIGRASI', 'Belum dipetakan' FROM DUAL
+WHERE EXISTS (SELECT 1 FROM kurikulum);
+
+INSERT INTO program_keahlian (bidang_keahlian_id, kode, nama)
+SELECT id, 'MIGRASI', 'Belum dipetakan' FROM bidang_keahlian WHERE kode = 'MIGRASI';
+
+INSERT INTO kompetensi_keahlian (program_keahlian_id, kode, nama)
+SELECT p.id, CONCAT('MIGRASI-', j.nomor), j.jurusan
+FROM program_keahlian p
+JOIN (
+    SELECT jurusan, ROW_NUMBER() OVER (ORDER BY jurusan) AS nomor
+    FROM (SELECT DISTINCT jurusan FROM kurikulum) d
+) j
+WHERE p.kode = 'MIGRASI';
+
+ALTER TABLE kurikulum
+    DROP INDEX idx_kurikulum_unique,
+    ADD COLUMN kompetensi_keahlian_id BIGINT UNSIGNED NULL AFTER id;
+
+UPDATE kurikulum k
+JOIN kompetensi_keahlian kk ON kk.nama = k.jurusan
+JOIN program_keahlian p ON p.id = kk.program_keahlian_id AND p.kode = 'MIGRASI'
+SET k.kompetensi_keahlian_id = kk.id;
+
+ALTER TABLE kurikulum
+    DROP COLUMN jurusan,
+    MODIFY COLUMN kompetensi_keahlian_id BIGINT UNSIGNED NOT NULL,
+    ADD FOREIGN KEY (kompetensi_keahlian_id) REFERENCES kompetensi_keahlian(id) ON DELETE RESTRICT,
+    ADD UNIQUE INDEX idx_kurikulum_unique (kompetensi_keahlian_id, tingkat, semester, mata_pelajaran_id);
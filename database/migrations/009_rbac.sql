@@ -0,0 +1,22 @@
+-- =============================================
+-- MIGRATION 009: Role-based access control
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: user_roles
+-- Peran admin; satu user bisa memiliki beberapa peran
+-- =============================================
+CREATE TABLE user_roles (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    role ENUM('super_admin', 'operator', 'wali_kelas', 'guru_mapel', 'kepala_sekolah') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_user_role (user_id, role)
+) ENGINE=InnoDB;
+
+-- Akun yang sudah ada sebelumnya punya akses penuh, jadikan super admin
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'super_admin' FROM users;
@@ -0,0 +1,33 @@
+-- =============================================
+-- MIGRATION 010: Akun portal siswa & orang tua
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- ALTER: user_roles.role
+-- Peran akun portal: siswa dan orang tua/wali
+-- =============================================
+ALTER TABLE user_roles
+    MODIFY role ENUM('super_admin', 'operator', 'wali_kelas', 'guru_mapel', 'kepala_sekolah', 'siswa', 'orang_tua') NOT NULL;
+
+-- =============================================
+-- TABLE: portal_links
+-- Siswa yang datanya boleh dibaca akun portal; akun orang tua
+-- ditautkan lewat data orang_tua atau wali setiap anak
+-- =============================================
+CREATE TABLE portal_links (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    siswa_id BIGINT UNSIGNED NOT NULL,
+    orang_tua_id BIGINT UNSIGNED NULL,
+    wali_id BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (siswa_id) REFERENCES siswa(id) ON DELETE CASCADE,
+    FOREIGN KEY (orang_tua_id) REFERENCES orang_tua(id) ON DELETE CASCADE,
+    FOREIGN KEY (wali_id) REFERENCES wali(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_portal_link (user_id, siswa_id),
+    INDEX idx_portal_link_orang_tua (orang_tua_id),
+    INDEX idx_portal_link_wali (wali_id)
+) ENGINE=InnoDB;
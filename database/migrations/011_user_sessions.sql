@@ -0,0 +1,27 @@
+-- =============================================
+-- MIGRATION 011: Sesi login & refresh token
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- =============================================
+-- TABLE: user_sessions
+-- Satu baris per sesi login. Refresh token disimpan sebagai hash SHA-256
+-- dan diganti setiap refresh; hash sebelumnya disimpan untuk mendeteksi
+-- refresh token yang dipakai ulang
+-- =============================================
+CREATE TABLE user_sessions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    refresh_token_hash CHAR(64) NOT NULL UNIQUE,
+    previous_token_hash CHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255),
+    ip_address VARCHAR(45),
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    last_used_at DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_sessions_user (user_id),
+    INDEX idx_user_sessions_previous (previous_token_hash)
+) ENGINE=InnoDB;
@@ -0,0 +1,26 @@
+-- =============================================
+-- MIGRATION 012: Ganti & reset password
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- Wajib ganti password (admin bawaan & setelah direset admin)
+ALTER TABLE users
+    ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE AFTER is_active,
+    ADD COLUMN password_changed_at DATETIME NULL AFTER must_change_password;
+
+-- =============================================
+-- TABLE: password_resets
+-- Token reset password yang dikirim lewat email. Disimpan sebagai hash
+-- SHA-256 dan hanya bisa dipakai sekali
+-- =============================================
+CREATE TABLE password_resets (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_password_resets_user (user_id)
+) ENGINE=InnoDB;
@@ -0,0 +1,37 @@
+-- =============================================
+-- MIGRATION 013: Autentikasi dua langkah (TOTP)
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- Secret TOTP (RFC 6238). Secret disimpan saat pendaftaran dimulai;
+-- totp_enabled aktif setelah kode pertama dikonfirmasi
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64) NULL AFTER password_changed_at,
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE AFTER totp_secret,
+    ADD COLUMN totp_last_counter BIGINT NOT NULL DEFAULT 0 AFTER totp_enabled;
+
+-- =============================================
+-- TABLE: user_recovery_codes
+-- Kode cadangan sekali pakai bila perangkat authenticator hilang,
+-- disimpan sebagai hash SHA-256
+-- =============================================
+CREATE TABLE user_recovery_codes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_recovery_codes_user (user_id)
+) ENGINE=InnoDB;
+
+-- =============================================
+-- TABLE: role_policies
+-- Kebijakan keamanan per peran, misalnya wajib 2FA
+-- =============================================
+CREATE TABLE role_policies (
+    role VARCHAR(20) PRIMARY KEY,
+    require_two_factor BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
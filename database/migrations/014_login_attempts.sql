@@ -0,0 +1,30 @@
+-- =============================================
+-- MIGRATION 014: Proteksi brute-force & riwayat login
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- Jumlah gagal login berturut-turut dan penguncian sementara akun
+ALTER TABLE users
+    ADD COLUMN failed_login_count INT NOT NULL DEFAULT 0 AFTER totp_last_counter,
+    ADD COLUMN last_failed_login_at DATETIME NULL AFTER failed_login_count,
+    ADD COLUMN locked_until DATETIME NULL AFTER last_failed_login_at;
+
+-- =============================================
+-- TABLE: login_attempts
+-- Riwayat login (berhasil maupun gagal). user_id kosong bila username
+-- tidak terdaftar; dipakai juga untuk membatasi gagal login per IP
+-- =============================================
+CREATE TABLE login_attempts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NULL,
+    username VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255),
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(30) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_login_attempts_user (user_id, created_at),
+    INDEX idx_login_attempts_ip (ip_address, created_at)
+) ENGINE=InnoDB;
@@ -0,0 +1,27 @@
+-- =============================================
+-- MIGRATION 015: Nomor urut pemeriksaan buku unik per tahun
+-- =============================================
+
+USE db_siswa_induk_api;
+
+-- Nomor urut ganda dalam satu tahun (bila ada) dipindah ke belakang urutan
+-- tahun itu; pemeriksaan dengan id terkecil mempertahankan nomornya
+UPDATE pemeriksaan_buku p
+JOIN (
+    SELECT id, maks + ROW_NUMBER() OVER (PARTITION BY tahun ORDER BY id) AS no_baru
+    FROM (
+        SELECT id,
+            YEAR(tanggal) AS tahun,
+            MAX(no_urut) OVER (PARTITION BY YEAR(tanggal)) AS maks,
+            ROW_NUMBER() OVER (PARTITION BY YEAR(tanggal), no_urut ORDER BY id) AS urutan
+        FROM pemeriksaan_buku
+    ) s
+    WHERE urutan > 1
+) d ON d.id = p.id
+SET p.no_urut = d.no_baru;
+
+-- Tahun pemeriksaan sebagai kolom turunan agar nomor urut bisa dijaga unik
+-- oleh database, tidak hanya oleh aplikasi
+ALTER TABLE pemeriksaan_buku
+    ADD COLUMN tahun SMALLINT UNSIGNED AS (YEAR(tanggal)) STORED AFTER tanggal,
+    ADD UNIQUE INDEX idx_pemeriksaan_tahun_no_urut (tahun, no_urut);
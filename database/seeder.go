@@ -13,40 +13,51 @@ func Seed(db *gorm.DB) {
 	seedUsers(db)
 }
 
+// seedUsers creates the default admin when there is no active super admin and
+// no admin user yet. Its password must be changed at the first login. An
+// existing admin user is never reactivated, given roles or changed.
 func seedUsers(db *gorm.DB) {
-	log.Println("Seeding/Updating default admin user...")
-	password, err := utils.HashPassword("admin123")
-	if err != nil {
-		log.Printf("Failed to hash password: %v", err)
+	var count int64
+	if err := db.Model(&models.User{}).
+		Where("is_active = ?", true).
+		Where("id IN (?)", db.Model(&models.UserRole{}).Select("user_id").Where("role = ?", models.RoleSuperAdmin)).
+		Count(&count).Error; err != nil {
+		log.Printf("Failed to check super admin users: %v", err)
+		return
+	}
+	if count > 0 {
 		return
 	}
 
 	var admin models.User
 	result := db.Where("username = ?", "admin").First(&admin)
-
-	if result.Error == gorm.ErrRecordNotFound {
-		// Create new
-		admin = models.User{
-			Username:     "admin",
-			Email:        "admin@siswa.local",
-			PasswordHash: password,
-			IsActive:     true,
-		}
-		if err := db.Create(&admin).Error; err != nil {
-			log.Printf("Failed to create admin user: %v", err)
-		} else {
-			log.Println("Default admin user created: admin / admin123")
-		}
-	} else if result.Error == nil {
-		// Update existing
-		admin.PasswordHash = password
-		admin.IsActive = true // Ensure active
-		if err := db.Save(&admin).Error; err != nil {
-			log.Printf("Failed to update admin user: %v", err)
-		} else {
-			log.Println("Default admin user updated: admin / admin123")
-		}
-	} else {
+	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
 		log.Printf("Failed to check admin user: %v", result.Error)
+		return
+	}
+
+	if result.Error == nil {
+		log.Println("No active super admin found: user admin already exists and is left unchanged")
+		return
+	}
+
+	password, err := utils.HashPassword("admin123")
+	if err != nil {
+		log.Printf("Failed to hash password: %v", err)
+		return
+	}
+
+	admin = models.User{
+		Username:           "admin",
+		Email:              "admin@siswa.local",
+		PasswordHash:       password,
+		IsActive:           true,
+		MustChangePassword: true,
+		Roles:              []models.UserRole{{Role: models.RoleSuperAdmin}},
+	}
+	if err := db.Create(&admin).Error; err != nil {
+		log.Printf("Failed to create admin user: %v", err)
+		return
 	}
+	log.Println("Default admin user created: admin / admin123 (password must be changed at first login)")
 }
@@ -1,18 +1,92 @@
 package requests
 
-import "time"
-
 // LoginRequest for user login
 type LoginRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50" example:"admin"`
 	Password string `json:"password" binding:"required,min=6" example:"admin123"`
 }
 
+// LoginTwoFactorRequest for the second login step of a user with two-factor
+// authentication. Code is the authenticator code or a recovery code.
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Code           string `json:"code" binding:"required" example:"123456"`
+}
+
+// TwoFactorCodeRequest for confirming an action with an authenticator code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// DisableTwoFactorRequest for turning off two-factor authentication. Code is
+// the authenticator code or a recovery code.
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required" example:"password123"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+// RefreshTokenRequest for exchanging a refresh token for a new token pair
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"Yx3k9..."`
+}
+
+// ChangePasswordRequest for changing the current user's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"admin123"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,nefield=CurrentPassword" example:"passwordBaru123"`
+}
+
+// ForgotPasswordRequest for requesting a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"admin@example.com"`
+}
+
+// ResetPasswordRequest for setting a new password with an emailed reset token
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required" example:"Yx3k9..."`
+	NewPassword string `json:"new_password" binding:"required,min=6" example:"passwordBaru123"`
+}
+
 // RegisterRequest for user registration
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=50" example:"newadmin"`
-	Email    string `json:"email" binding:"required,email" example:"admin@example.com"`
+	Username string   `json:"username" binding:"required,min=3,max=50" example:"newadmin"`
+	Email    string   `json:"email" binding:"required,email" example:"admin@example.com"`
+	Password string   `json:"password" binding:"required,min=6" example:"password123"`
+	Roles    []string `json:"roles" binding:"required,min=1,dive,oneof=super_admin operator wali_kelas guru_mapel kepala_sekolah" example:"operator"`
+}
+
+// UserFilterRequest for filtering the user list
+type UserFilterRequest struct {
+	Search string `form:"search"`
+	Role   string `form:"role" binding:"omitempty,oneof=super_admin operator wali_kelas guru_mapel kepala_sekolah siswa orang_tua"`
+}
+
+// SetRolePolicyRequest for setting whether a role requires two-factor authentication
+type SetRolePolicyRequest struct {
+	RequireTwoFactor *bool `json:"require_two_factor" binding:"required" example:"true"`
+}
+
+// CreatePortalAccountRequest for creating a student or parent portal account.
+// A student account needs siswa_id; a parent account needs the orang tua or
+// wali record of each child.
+type CreatePortalAccountRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50" example:"ortu.ahmad"`
+	Email    string `json:"email" binding:"required,email" example:"ortu.ahmad@example.com"`
 	Password string `json:"password" binding:"required,min=6" example:"password123"`
+	Tipe     string `json:"tipe" binding:"required,oneof=siswa orang_tua" example:"orang_tua"`
+	SetPortalLinksRequest
+}
+
+// SetPortalLinksRequest for replacing the students a portal account is linked to
+type SetPortalLinksRequest struct {
+	SiswaID     uint   `json:"siswa_id" example:"1"`
+	OrangTuaIDs []uint `json:"orang_tua_ids" example:"3"`
+	WaliIDs     []uint `json:"wali_ids"`
+}
+
+// SetUserRolesRequest for replacing the roles of a user
+type SetUserRolesRequest struct {
+	Roles []string `json:"roles" binding:"required,min=1,dive,oneof=super_admin operator wali_kelas guru_mapel kepala_sekolah" example:"wali_kelas,guru_mapel"`
 }
 
 // CreateSiswaRequest for creating a student
@@ -136,6 +210,35 @@ type CreatePrestasiRequest struct {
 	Tingkat    string `json:"tingkat" binding:"oneof=Sekolah Kecamatan Kota Provinsi Nasional Internasional" example:"Kota"`
 }
 
+// UpdateKepribadianRequest for updating personality assessment
+type UpdateKepribadianRequest struct {
+	Aspek          string `json:"aspek" binding:"max=100" example:"Disiplin/Ketertiban"`
+	Nilai          string `json:"nilai" binding:"omitempty,oneof=Baik Cukup Kurang" example:"Baik"`
+	TahunPelajaran string `json:"tahun_pelajaran" binding:"max=20" example:"2024/2025"`
+}
+
+// KepribadianFilterRequest for filtering personality assessments
+type KepribadianFilterRequest struct {
+	TahunPelajaran string `form:"tahun_pelajaran"`
+	Aspek          string `form:"aspek"`
+	Nilai          string `form:"nilai" binding:"omitempty,oneof=Baik Cukup Kurang"`
+}
+
+// UpdatePrestasiRequest for updating achievement
+type UpdatePrestasiRequest struct {
+	Bidang     string `json:"bidang" binding:"omitempty,oneof=Kesenian Olahraga Kemasyarakatan Pramuka 'Karya Tulis' Lainnya" example:"Olahraga"`
+	Keterangan string `json:"keterangan" example:"Juara 1 Lomba Lari"`
+	Tahun      uint   `json:"tahun" example:"2024"`
+	Tingkat    string `json:"tingkat" binding:"omitempty,oneof=Sekolah Kecamatan Kota Provinsi Nasional Internasional" example:"Kota"`
+}
+
+// PrestasiFilterRequest for filtering achievements
+type PrestasiFilterRequest struct {
+	Bidang  string `form:"bidang" binding:"omitempty,oneof=Kesenian Olahraga Kemasyarakatan Pramuka 'Karya Tulis' Lainnya"`
+	Tingkat string `form:"tingkat" binding:"omitempty,oneof=Sekolah Kecamatan Kota Provinsi Nasional Internasional"`
+	Tahun   uint   `form:"tahun"`
+}
+
 // CreateBeasiswaRequest for creating scholarship
 type CreateBeasiswaRequest struct {
 	TahunPelajaran string `json:"tahun_pelajaran" binding:"required,max=20" example:"2024/2025"`
@@ -143,12 +246,25 @@ type CreateBeasiswaRequest struct {
 	Keterangan     string `json:"keterangan" example:"Beasiswa prestasi akademik"`
 }
 
+// UpdateBeasiswaRequest for updating scholarship
+type UpdateBeasiswaRequest struct {
+	TahunPelajaran string `json:"tahun_pelajaran" binding:"max=20" example:"2024/2025"`
+	Pemberi        string `json:"pemberi" binding:"max=100" example:"Pemerintah"`
+	Keterangan     string `json:"keterangan" example:"Beasiswa prestasi akademik"`
+}
+
+// BeasiswaFilterRequest for filtering scholarships
+type BeasiswaFilterRequest struct {
+	TahunPelajaran string `form:"tahun_pelajaran"`
+	Pemberi        string `form:"pemberi"`
+}
+
 // CreateKehadiranRequest for creating attendance
 type CreateKehadiranRequest struct {
 	Kelas             string  `json:"kelas" binding:"required,oneof=X XI XII" example:"X"`
 	Semester          uint8   `json:"semester" binding:"required,min=1,max=2" example:"1"`
 	JumlahHadir       uint    `json:"jumlah_hadir" example:"90"`
-	PersentaseHadir   float64 `json:"persentase_hadir" example:"95.5"`
+	PersentaseHadir   float64 `json:"persentase_hadir" binding:"min=0,max=100" example:"95.5"`
 	JumlahSakit       uint    `json:"jumlah_sakit" example:"3"`
 	JumlahIzin        uint    `json:"jumlah_izin" example:"2"`
 	JumlahAlpa        uint    `json:"jumlah_alpa" example:"0"`
@@ -159,8 +275,8 @@ type CreateKehadiranRequest struct {
 type CreateNilaiSemesterRequest struct {
 	MataPelajaranID       uint   `json:"mata_pelajaran_id" binding:"required" example:"1"`
 	Kelas                 string `json:"kelas" binding:"required,oneof=X XI XII" example:"X"`
-	Semester              uint8  `json:"semester" binding:"required,min=1,max=2" example:"1"`
-	TahunPelajaran        string `json:"tahun_pelajaran" binding:"required,max=20" example:"2024/2025"`
+	Semester              uint8  `json:"semester" binding:"omitempty,min=1,max=2" example:"1"`
+	TahunPelajaran        string `json:"tahun_pelajaran" binding:"max=20" example:"2024/2025"`
 	NilaiPengetahuan      uint   `json:"nilai_pengetahuan" binding:"max=100" example:"85"`
 	PredikatPengetahuan   string `json:"predikat_pengetahuan" binding:"omitempty,oneof=A B C D" example:"B"`
 	DeskripsiPengetahuan  string `json:"deskripsi_pengetahuan" example:"Baik dalam memahami materi"`
@@ -196,12 +312,45 @@ type CreatePKLRequest struct {
 	Keterangan string `json:"keterangan" example:"Praktek sebagai teknisi jaringan"`
 }
 
+// UpdatePKLRequest for updating internship
+type UpdatePKLRequest struct {
+	NamaDUDI   string `json:"nama_dudi" binding:"max=200" example:"PT. Teknologi Indonesia"`
+	Lokasi     string `json:"lokasi" binding:"max=200" example:"Bandung"`
+	LamaBulan  uint   `json:"lama_bulan" example:"3"`
+	Keterangan string `json:"keterangan" example:"Praktek sebagai teknisi jaringan"`
+}
+
 // CreateEkstrakurikulerRequest for creating extracurricular
 type CreateEkstrakurikulerRequest struct {
 	NamaKegiatan string `json:"nama_kegiatan" binding:"required,max=100" example:"Kepramukaan"`
 	Keterangan   string `json:"keterangan" example:"Aktif"`
 }
 
+// UpdateEkstrakurikulerRequest for updating extracurricular
+type UpdateEkstrakurikulerRequest struct {
+	NamaKegiatan string `json:"nama_kegiatan" binding:"max=100" example:"Kepramukaan"`
+	Keterangan   string `json:"keterangan" example:"Aktif"`
+}
+
+// CreatePrestasiSemesterRequest for creating semester achievement
+type CreatePrestasiSemesterRequest struct {
+	JenisPrestasi string `json:"jenis_prestasi" binding:"required,max=200" example:"Juara 2 LKS Tingkat Kota"`
+	Keterangan    string `json:"keterangan" example:"Bidang IT Network Support"`
+}
+
+// UpdatePrestasiSemesterRequest for updating semester achievement
+type UpdatePrestasiSemesterRequest struct {
+	JenisPrestasi string `json:"jenis_prestasi" binding:"max=200" example:"Juara 2 LKS Tingkat Kota"`
+	Keterangan    string `json:"keterangan" example:"Bidang IT Network Support"`
+}
+
+// SetKetidakhadiranRequest for setting absence in semester notes
+type SetKetidakhadiranRequest struct {
+	KarenaSakit     uint `json:"karena_sakit" example:"2"`
+	DenganIzin      uint `json:"dengan_izin" example:"1"`
+	TanpaKeterangan uint `json:"tanpa_keterangan" example:"0"`
+}
+
 // CreateNilaiIjazahRequest for creating certificate grade
 type CreateNilaiIjazahRequest struct {
 	MataPelajaranID uint   `json:"mata_pelajaran_id" binding:"required" example:"1"`
@@ -223,11 +372,25 @@ type CreateMeninggalkanSekolahRequest struct {
 
 // CreatePemeriksaanRequest for creating book inspection
 type CreatePemeriksaanRequest struct {
-	NoUrut        uint      `json:"no_urut" binding:"required" example:"1"`
-	Tanggal       time.Time `json:"tanggal" binding:"required" example:"2024-01-15"`
-	NamaPemeriksa string    `json:"nama_pemeriksa" binding:"required,max=100" example:"Kepala Sekolah"`
-	Jabatan       string    `json:"jabatan" binding:"max=100" example:"Kepala Sekolah"`
-	Keterangan    string    `json:"keterangan" example:"Pemeriksaan rutin semester ganjil"`
+	Tanggal       string `json:"tanggal" binding:"required" example:"2024-01-15"`
+	NamaPemeriksa string `json:"nama_pemeriksa" binding:"required,max=100" example:"Kepala Sekolah"`
+	Jabatan       string `json:"jabatan" binding:"max=100" example:"Kepala Sekolah"`
+	Keterangan    string `json:"keterangan" example:"Pemeriksaan rutin semester ganjil"`
+}
+
+// UpdatePemeriksaanRequest for updating book inspection
+type UpdatePemeriksaanRequest struct {
+	Tanggal       string `json:"tanggal" example:"2024-01-15"`
+	NamaPemeriksa string `json:"nama_pemeriksa" binding:"max=100" example:"Kepala Sekolah"`
+	Jabatan       string `json:"jabatan" binding:"max=100" example:"Kepala Sekolah"`
+	Keterangan    string `json:"keterangan" example:"Pemeriksaan rutin semester ganjil"`
+}
+
+// PemeriksaanFilterRequest for filtering book inspections
+type PemeriksaanFilterRequest struct {
+	TanggalMulai   string `form:"tanggal_mulai" example:"2024-01-01"`
+	TanggalSelesai string `form:"tanggal_selesai" example:"2024-12-31"`
+	Tahun          int    `form:"tahun" binding:"omitempty,min=1900" example:"2024"`
 }
 
 // Pagination request params
@@ -313,3 +476,339 @@ type UpdateAlamatRequest struct {
 	JarakKeSekolah float64 `json:"jarak_ke_sekolah" example:"2.5"`
 	Transportasi   string  `json:"transportasi" binding:"max=50" example:"Motor"`
 }
+
+// SiswaFilterRequest for filtering the student list
+type SiswaFilterRequest struct {
+	RombelID             uint   `form:"rombel_id"`
+	Status               string `form:"status" binding:"omitempty,oneof=aktif tamat pindah putus semua"`
+	KompetensiKeahlianID uint   `form:"kompetensi_keahlian_id"`
+	ProgramKeahlianID    uint   `form:"program_keahlian_id"`
+	BidangKeahlianID     uint   `form:"bidang_keahlian_id"`
+}
+
+// CreateRombelRequest for creating a class group
+type CreateRombelRequest struct {
+	Nama           string `json:"nama" binding:"required,max=50" example:"XI TKJ 2"`
+	Tingkat        string `json:"tingkat" binding:"required,oneof=X XI XII" example:"XI"`
+	Jurusan        string `json:"jurusan" binding:"max=100" example:"Teknik Komputer dan Jaringan"`
+	TahunPelajaran string `json:"tahun_pelajaran" binding:"required,max=20" example:"2025/2026"`
+	WaliKelasID    *uint  `json:"wali_kelas_id" example:"2"`
+}
+
+// UpdateRombelRequest for updating a class group
+type UpdateRombelRequest struct {
+	Nama           string `json:"nama" binding:"omitempty,max=50" example:"XI TKJ 2"`
+	Tingkat        string `json:"tingkat" binding:"omitempty,oneof=X XI XII" example:"XI"`
+	Jurusan        string `json:"jurusan" binding:"max=100" example:"Teknik Komputer dan Jaringan"`
+	TahunPelajaran string `json:"tahun_pelajaran" binding:"max=20" example:"2025/2026"`
+	WaliKelasID    *uint  `json:"wali_kelas_id" example:"2"`
+}
+
+// RombelFilterRequest for filtering class groups
+type RombelFilterRequest struct {
+	Tingkat        string `form:"tingkat" binding:"omitempty,oneof=X XI XII"`
+	TahunPelajaran string `form:"tahun_pelajaran"`
+	WaliKelasID    uint   `form:"wali_kelas_id"`
+}
+
+// AddAnggotaRombelRequest for enrolling students in a class group
+type AddAnggotaRombelRequest struct {
+	SiswaIDs []uint `json:"siswa_ids" binding:"required,min=1" example:"1,2,3"`
+}
+
+// CreateTahunPelajaranRequest for creating an academic year
+type CreateTahunPelajaranRequest struct {
+	Label          string `json:"label" binding:"required,max=20" example:"2025/2026"`
+	TanggalMulai   string `json:"tanggal_mulai" binding:"required" example:"2025-07-14"`
+	TanggalSelesai string `json:"tanggal_selesai" binding:"required" example:"2026-06-26"`
+}
+
+// UpdateTahunPelajaranRequest for updating an academic year
+type UpdateTahunPelajaranRequest struct {
+	Label          string `json:"label" binding:"max=20" example:"2025/2026"`
+	TanggalMulai   string `json:"tanggal_mulai" example:"2025-07-14"`
+	TanggalSelesai string `json:"tanggal_selesai" example:"2026-06-26"`
+}
+
+// ActivateTahunPelajaranRequest for setting the active academic year and semester
+type ActivateTahunPelajaranRequest struct {
+	Semester uint8 `json:"semester" binding:"required,min=1,max=2" example:"1"`
+}
+
+// KenaikanKelasRequest for evaluating grade promotion of an academic year
+type KenaikanKelasRequest struct {
+	TahunPelajaran     string  `json:"tahun_pelajaran" binding:"required,max=20" example:"2024/2025"`
+	RombelID           uint    `json:"rombel_id" example:"0"`
+	MinPersentaseHadir float64 `json:"min_persentase_hadir" binding:"min=0,max=100" example:"75"`
+	NilaiMinimum       uint    `json:"nilai_minimum" binding:"max=100" example:"70"`
+}
+
+// KenaikanKelasOverrideRequest for manually deciding a student's promotion
+type KenaikanKelasOverrideRequest struct {
+	SiswaID   uint   `json:"siswa_id" binding:"required" example:"1"`
+	Keputusan string `json:"keputusan" binding:"required,oneof=Naik Tinggal" example:"Naik"`
+	Catatan   string `json:"catatan" example:"Naik berdasarkan rapat dewan guru"`
+}
+
+// ProsesKenaikanKelasRequest for committing grade promotion decisions
+type ProsesKenaikanKelasRequest struct {
+	KenaikanKelasRequest
+	Tanggal  string                         `json:"tanggal" binding:"required" example:"2025-06-20"`
+	Override []KenaikanKelasOverrideRequest `json:"override" binding:"dive"`
+}
+
+// KelulusanRequest for graduating a grade XII cohort
+type KelulusanRequest struct {
+	TahunPelajaran string                   `json:"tahun_pelajaran" binding:"required,max=20" example:"2026/2027"`
+	RombelID       uint                     `json:"rombel_id" example:"0"`
+	Tanggal        string                   `json:"tanggal" binding:"required" example:"2027-06-15"`
+	NoIjazah       []KelulusanIjazahRequest `json:"no_ijazah" binding:"dive"`
+	Kecuali        []uint                   `json:"kecuali" example:"7,9"`
+}
+
+// KelulusanIjazahRequest for a graduate's certificate number
+type KelulusanIjazahRequest struct {
+	SiswaID  uint   `json:"siswa_id" binding:"required" example:"1"`
+	NoIjazah string `json:"no_ijazah" binding:"required,max=50" example:"DN-01/IJ-2027"`
+}
+
+// BulkNilaiSikapRequest for submitting attitude grades of a whole class group
+type BulkNilaiSikapRequest struct {
+	Semester uint8                       `json:"semester" binding:"required,min=1,max=2" example:"1"`
+	Nilai    []BulkNilaiSikapItemRequest `json:"nilai" binding:"required,min=1,dive"`
+}
+
+// BulkNilaiSikapItemRequest for a student's attitude grade in a bulk submission
+type BulkNilaiSikapItemRequest struct {
+	SiswaID            uint   `json:"siswa_id" binding:"required" example:"1"`
+	DeskripsiSpiritual string `json:"deskripsi_spiritual" example:"Taat beribadah"`
+	DeskripsiSosial    string `json:"deskripsi_sosial" example:"Aktif dalam kegiatan sosial"`
+}
+
+// CreateKehadiranHarianRequest for recording a student's daily attendance
+type CreateKehadiranHarianRequest struct {
+	Tanggal    string `json:"tanggal" binding:"required" example:"2024-08-01"`
+	Status     string `json:"status" binding:"required,oneof=hadir sakit izin alpa" example:"hadir"`
+	Keterangan string `json:"keterangan" example:"Surat dokter"`
+	Kelas      string `json:"kelas" binding:"omitempty,oneof=X XI XII" example:"X"`
+	Semester   uint8  `json:"semester" binding:"omitempty,min=1,max=2" example:"1"`
+}
+
+// BulkKehadiranHarianRequest for recording the daily attendance of a whole class group
+type BulkKehadiranHarianRequest struct {
+	Tanggal   string                           `json:"tanggal" binding:"required" example:"2024-08-01"`
+	Semester  uint8                            `json:"semester" binding:"omitempty,min=1,max=2" example:"1"`
+	Kehadiran []BulkKehadiranHarianItemRequest `json:"kehadiran" binding:"required,min=1,dive"`
+}
+
+// BulkKehadiranHarianItemRequest for a student's daily attendance in a bulk submission
+type BulkKehadiranHarianItemRequest struct {
+	SiswaID    uint   `json:"siswa_id" binding:"required" example:"1"`
+	Status     string `json:"status" binding:"required,oneof=hadir sakit izin alpa" example:"sakit"`
+	Keterangan string `json:"keterangan" example:"Demam"`
+}
+
+// KehadiranHarianFilterRequest for filtering daily attendance
+type KehadiranHarianFilterRequest struct {
+	Kelas          string `form:"kelas" binding:"omitempty,oneof=X XI XII"`
+	Semester       uint8  `form:"semester" binding:"omitempty,min=1,max=2"`
+	Status         string `form:"status" binding:"omitempty,oneof=hadir sakit izin alpa"`
+	TanggalMulai   string `form:"tanggal_mulai" example:"2024-07-15"`
+	TanggalSelesai string `form:"tanggal_selesai" example:"2024-12-20"`
+}
+
+// CreateSkemaPenilaianRequest for creating grading scheme. Leave the batas fields
+// empty to derive equal A/B/C intervals above the KKM.
+type CreateSkemaPenilaianRequest struct {
+	TahunPelajaran  string `json:"tahun_pelajaran" binding:"required,max=20" example:"2024/2025"`
+	MataPelajaranID *uint  `json:"mata_pelajaran_id" example:"1"`
+	KKM             uint   `json:"kkm" binding:"required,min=1,max=100" example:"75"`
+	BatasA          uint   `json:"batas_a" binding:"max=100" example:"92"`
+	BatasB          uint   `json:"batas_b" binding:"max=100" example:"83"`
+	BatasC          uint   `json:"batas_c" binding:"max=100" example:"75"`
+}
+
+// UpdateSkemaPenilaianRequest for updating grading scheme
+type UpdateSkemaPenilaianRequest struct {
+	KKM    uint `json:"kkm" binding:"omitempty,min=1,max=100" example:"75"`
+	BatasA uint `json:"batas_a" binding:"max=100" example:"92"`
+	BatasB uint `json:"batas_b" binding:"max=100" example:"83"`
+	BatasC uint `json:"batas_c" binding:"max=100" example:"75"`
+}
+
+// SkemaPenilaianFilterRequest for filtering grading schemes
+type SkemaPenilaianFilterRequest struct {
+	TahunPelajaran  string `form:"tahun_pelajaran"`
+	MataPelajaranID uint   `form:"mata_pelajaran_id"`
+}
+
+// CreateMataPelajaranRequest for creating subject
+type CreateMataPelajaranRequest struct {
+	Kode        string `json:"kode" binding:"required,max=20" example:"RPL1"`
+	Nama        string `json:"nama" binding:"required,max=100" example:"Pemodelan Perangkat Lunak"`
+	Kelompok    string `json:"kelompok" binding:"required,oneof=A B C" example:"C"`
+	SubKelompok string `json:"sub_kelompok" binding:"max=50" example:"C3"`
+}
+
+// UpdateMataPelajaranRequest for updating subject
+type UpdateMataPelajaranRequest struct {
+	Kode        string `json:"kode" binding:"max=20" example:"RPL1"`
+	Nama        string `json:"nama" binding:"max=100" example:"Pemodelan Perangkat Lunak"`
+	Kelompok    string `json:"kelompok" binding:"omitempty,oneof=A B C" example:"C"`
+	SubKelompok string `json:"sub_kelompok" binding:"max=50" example:"C3"`
+}
+
+// MataPelajaranFilterRequest for filtering subjects
+type MataPelajaranFilterRequest struct {
+	Search   string `form:"search"`
+	Kelompok string `form:"kelompok" binding:"omitempty,oneof=A B C"`
+	Status   string `form:"status" binding:"omitempty,oneof=aktif nonaktif semua"`
+}
+
+// CreateKurikulumRequest for adding a subject to a curriculum
+type CreateKurikulumRequest struct {
+	KompetensiKeahlianID uint   `json:"kompetensi_keahlian_id" binding:"required" example:"1"`
+	Tingkat              string `json:"tingkat" binding:"required,oneof=X XI XII" example:"X"`
+	Semester             uint8  `json:"semester" binding:"required,min=1,max=2" example:"1"`
+	MataPelajaranID      uint   `json:"mata_pelajaran_id" binding:"required" example:"1"`
+	Urutan               uint   `json:"urutan" example:"1"`
+	MenitPerMinggu       uint   `json:"menit_per_minggu" example:"135"`
+}
+
+// UpdateKurikulumRequest for updating a curriculum entry
+type UpdateKurikulumRequest struct {
+	Urutan         uint `json:"urutan" example:"2"`
+	MenitPerMinggu uint `json:"menit_per_minggu" example:"180"`
+}
+
+// KurikulumFilterRequest for filtering curriculum entries
+type KurikulumFilterRequest struct {
+	KompetensiKeahlianID uint   `form:"kompetensi_keahlian_id"`
+	Tingkat              string `form:"tingkat" binding:"omitempty,oneof=X XI XII"`
+	Semester             uint8  `form:"semester" binding:"omitempty,min=1,max=2"`
+	MataPelajaranID      uint   `form:"mata_pelajaran_id"`
+}
+
+// CreateBidangKeahlianRequest for creating a bidang keahlian
+type CreateBidangKeahlianRequest struct {
+	Kode string `json:"kode" binding:"required,max=20" example:"TIK"`
+	Nama string `json:"nama" binding:"required,max=100" example:"Teknologi Informasi dan Komunikasi"`
+}
+
+// UpdateBidangKeahlianRequest for updating a bidang keahlian
+type UpdateBidangKeahlianRequest struct {
+	Kode string `json:"kode" binding:"max=20" example:"TIK"`
+	Nama string `json:"nama" binding:"max=100" example:"Teknologi Informasi dan Komunikasi"`
+}
+
+// CreateProgramKeahlianRequest for creating a program keahlian
+type CreateProgramKeahlianRequest struct {
+	BidangKeahlianID uint   `json:"bidang_keahlian_id" binding:"required" example:"1"`
+	Kode             string `json:"kode" binding:"required,max=20" example:"TKI"`
+	Nama             string `json:"nama" binding:"required,max=100" example:"Teknik Komputer dan Informatika"`
+}
+
+// UpdateProgramKeahlianRequest for updating a program keahlian
+type UpdateProgramKeahlianRequest struct {
+	BidangKeahlianID uint   `json:"bidang_keahlian_id" example:"1"`
+	Kode             string `json:"kode" binding:"max=20" example:"TKI"`
+	Nama             string `json:"nama" binding:"max=100" example:"Teknik Komputer dan Informatika"`
+}
+
+// CreateKompetensiKeahlianRequest for creating a kompetensi keahlian
+type CreateKompetensiKeahlianRequest struct {
+	ProgramKeahlianID uint   `json:"program_keahlian_id" binding:"required" example:"1"`
+	Kode              string `json:"kode" binding:"required,max=20" example:"TKJ"`
+	Nama              string `json:"nama" binding:"required,max=100" example:"Teknik Komputer dan Jaringan"`
+	Singkatan         string `json:"singkatan" binding:"max=20" example:"TKJ"`
+}
+
+// UpdateKompetensiKeahlianRequest for updating a kompetensi keahlian
+type UpdateKompetensiKeahlianRequest struct {
+	ProgramKeahlianID uint   `json:"program_keahlian_id" example:"1"`
+	Kode              string `json:"kode" binding:"max=20" example:"TKJ"`
+	Nama              string `json:"nama" binding:"max=100" example:"Teknik Komputer dan Jaringan"`
+	Singkatan         string `json:"singkatan" binding:"max=20" example:"TKJ"`
+}
+
+// ProgramKeahlianFilterRequest for filtering program keahlian
+type ProgramKeahlianFilterRequest struct {
+	BidangKeahlianID uint `form:"bidang_keahlian_id"`
+}
+
+// KompetensiKeahlianFilterRequest for filtering kompetensi keahlian
+type KompetensiKeahlianFilterRequest struct {
+	ProgramKeahlianID uint `form:"program_keahlian_id"`
+	BidangKeahlianID  uint `form:"bidang_keahlian_id"`
+}
+
+// SetJurusanSiswaRequest for assigning or moving a student to a kompetensi keahlian
+type SetJurusanSiswaRequest struct {
+	KompetensiKeahlianID uint   `json:"kompetensi_keahlian_id" binding:"required" example:"1"`
+	TanggalMulai         string `json:"tanggal_mulai" binding:"required" example:"2025-07-14"`
+	Keterangan           string `json:"keterangan" example:"Pindah jurusan atas permintaan orang tua"`
+}
+
+// RaporRequest for printing the report card of a student
+type RaporRequest struct {
+	Kelas          string `form:"kelas" binding:"required,oneof=X XI XII"`
+	Semester       uint8  `form:"semester" binding:"required,min=1,max=2"`
+	TahunPelajaran string `form:"tahun_pelajaran"`
+}
+
+// RaporRombelRequest for printing the report cards of a class group
+type RaporRombelRequest struct {
+	Semester uint8 `form:"semester" binding:"required,min=1,max=2"`
+}
+
+// BukuIndukRequest for exporting the buku induk of a student
+type BukuIndukRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=pdf html"`
+}
+
+// LeggerRequest for the grade matrix of a class group
+type LeggerRequest struct {
+	Semester uint8  `form:"semester" binding:"required,min=1,max=2"`
+	Format   string `form:"format" binding:"omitempty,oneof=json xlsx"`
+}
+
+// ImportSiswaRequest for the bulk student import
+type ImportSiswaRequest struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// ImportTemplateRequest for downloading the blank student import template
+type ImportTemplateRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=xlsx csv"`
+}
+
+// DapodikApplyRequest for applying the side chosen per field after comparing a Dapodik export
+type DapodikApplyRequest struct {
+	Siswa []DapodikApplySiswaRequest `json:"siswa" binding:"required,min=1,dive"`
+}
+
+// DapodikApplySiswaRequest for the field choices of one student
+type DapodikApplySiswaRequest struct {
+	NISN    string                  `json:"nisn" binding:"required,len=10" example:"0012345678"`
+	Pilihan []DapodikPilihanRequest `json:"pilihan" binding:"required,min=1,dive"`
+}
+
+// DapodikPilihanRequest for the chosen side of one differing field. NilaiDapodik is
+// taken over when Sumber is dapodik; with sistem our value is kept.
+type DapodikPilihanRequest struct {
+	Field        string `json:"field" binding:"required" example:"alamat_kelurahan"`
+	Sumber       string `json:"sumber" binding:"required,oneof=sistem dapodik" example:"dapodik"`
+	NilaiDapodik string `json:"nilai_dapodik" example:"Cibiru"`
+}
+
+// PeringkatRombelRequest for the ranking of a class group
+type PeringkatRombelRequest struct {
+	Semester uint8 `form:"semester" binding:"required,min=1,max=2"`
+}
+
+// PeringkatTingkatRequest for the ranking of a whole tingkat; the active academic year is used when empty
+type PeringkatTingkatRequest struct {
+	Tingkat        string `form:"tingkat" binding:"required,oneof=X XI XII"`
+	Semester       uint8  `form:"semester" binding:"required,min=1,max=2"`
+	TahunPelajaran string `form:"tahun_pelajaran"`
+}
@@ -2,19 +2,111 @@ package responses
 
 import "time"
 
-// LoginResponse for login result
+// LoginResponse for login and refresh result. ExpiresIn and RefreshExpiresIn
+// are in seconds. For a user with two-factor authentication the first login
+// step only returns TwoFactorRequired and a ChallengeToken for POST /auth/login/2fa.
+// TwoFactorSetupRequired means a role of the user requires two-factor
+// authentication that is not set up yet.
 type LoginResponse struct {
-	Token     string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	ExpiresIn int          `json:"expires_in" example:"86400"`
-	User      UserResponse `json:"user"`
+	Token                  string        `json:"token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresIn              int           `json:"expires_in,omitempty" example:"900"`
+	RefreshToken           string        `json:"refresh_token,omitempty" example:"Yx3k9..."`
+	RefreshExpiresIn       int           `json:"refresh_expires_in,omitempty" example:"2592000"`
+	User                   *UserResponse `json:"user,omitempty"`
+	TwoFactorRequired      bool          `json:"two_factor_required,omitempty" example:"false"`
+	ChallengeToken         string        `json:"challenge_token,omitempty"`
+	TwoFactorSetupRequired bool          `json:"two_factor_setup_required,omitempty" example:"false"`
 }
 
-// UserResponse for user data
+// TwoFactorSetupResponse for a pending two-factor enrolment. OTPAuthURI is
+// shown as a QR code for the authenticator app; Secret is for manual entry.
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OTPAuthURI string `json:"otpauth_uri" example:"otpauth://totp/API%20Siswa%20Induk:admin?secret=JBSWY3DPEHPK3PXP&issuer=API%20Siswa%20Induk"`
+}
+
+// RecoveryCodesResponse for newly generated recovery codes, shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k7qm2-xpa9r"`
+}
+
+// TwoFactorStatusResponse for the two-factor state of the current user
+type TwoFactorStatusResponse struct {
+	Enabled           bool  `json:"enabled" example:"true"`
+	Required          bool  `json:"required" example:"true"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left" example:"8"`
+}
+
+// TemporaryPasswordResponse for a password reset by an admin. The user must
+// change the temporary password at their next login.
+type TemporaryPasswordResponse struct {
+	TemporaryPassword string `json:"temporary_password" example:"k7Qm2xPa9RtZ"`
+}
+
+// RevokedSessionsResponse for the number of sessions ended by a logout
+type RevokedSessionsResponse struct {
+	Revoked int64 `json:"revoked" example:"2"`
+}
+
+// LoginAttemptResponse for an entry of the login history. Reason tells why a
+// failed attempt was rejected: unknown_user, wrong_password, deactivated,
+// wrong_two_factor_code or blocked.
+type LoginAttemptResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	Username  string    `json:"username" example:"admin"`
+	IPAddress string    `json:"ip_address" example:"192.168.1.10"`
+	UserAgent string    `json:"user_agent" example:"Mozilla/5.0"`
+	Success   bool      `json:"success" example:"false"`
+	Reason    string    `json:"reason,omitempty" example:"wrong_password"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserResponse for user data. While MustChangePassword is set every endpoint
+// but PUT /auth/password and logout answers 403. LockedUntil is set while the
+// account is locked after too many failed logins.
 type UserResponse struct {
-	ID       uint   `json:"id" example:"1"`
-	Username string `json:"username" example:"admin"`
-	Email    string `json:"email" example:"admin@example.com"`
-	IsActive bool   `json:"is_active" example:"true"`
+	ID                 uint                 `json:"id" example:"1"`
+	Username           string               `json:"username" example:"admin"`
+	Email              string               `json:"email" example:"admin@example.com"`
+	IsActive           bool                 `json:"is_active" example:"true"`
+	MustChangePassword bool                 `json:"must_change_password" example:"false"`
+	TwoFactorEnabled   bool                 `json:"two_factor_enabled" example:"false"`
+	LockedUntil        *time.Time           `json:"locked_until,omitempty"`
+	Roles              []string             `json:"roles,omitempty" example:"operator"`
+	Portal             []PortalLinkResponse `json:"portal,omitempty"`
+}
+
+// PortalLinkResponse for a student a portal account may read
+type PortalLinkResponse struct {
+	SiswaID    uint  `json:"siswa_id"`
+	OrangTuaID *uint `json:"orang_tua_id,omitempty"`
+	WaliID     *uint `json:"wali_id,omitempty"`
+}
+
+// PortalSiswaResponse for a student linked to a portal account. Hubungan is
+// "siswa" for the student's own account, otherwise ayah, ibu or wali.
+type PortalSiswaResponse struct {
+	ID          uint   `json:"id"`
+	NoInduk     string `json:"no_induk"`
+	NISN        string `json:"nisn"`
+	NamaLengkap string `json:"nama_lengkap"`
+	FotoPath    string `json:"foto_path"`
+	Hubungan    string `json:"hubungan" example:"ibu"`
+}
+
+// RoleResponse for a role, the permissions it grants and whether it requires
+// two-factor authentication
+type RoleResponse struct {
+	Role             string               `json:"role" example:"wali_kelas"`
+	RequireTwoFactor bool                 `json:"require_two_factor" example:"false"`
+	Permissions      []PermissionResponse `json:"permissions"`
+}
+
+// PermissionResponse for a permission of a role. Scope "wali_kelas" limits it to
+// the students and class groups of the user's own rombel.
+type PermissionResponse struct {
+	Permission string `json:"permission" example:"siswa:write"`
+	Scope      string `json:"scope" example:"wali_kelas"`
 }
 
 // SiswaListResponse for student list
@@ -25,6 +117,7 @@ type SiswaListResponse struct {
 	NamaLengkap  string    `json:"nama_lengkap" example:"Ahmad Syafiq"`
 	JenisKelamin string    `json:"jenis_kelamin" example:"L"`
 	Kelas        string    `json:"kelas" example:"X"`
+	Jurusan      string    `json:"jurusan" example:"TKJ"`
 	FotoPath     string    `json:"foto_path" example:"photos/123456.jpg"`
 	CreatedAt    time.Time `json:"created_at"`
 }
@@ -49,6 +142,7 @@ type SiswaDetailResponse struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 
 	// Related data
+	KompetensiKeahlian   *KompetensiKeahlianResponse  `json:"kompetensi_keahlian,omitempty"`
 	Alamat               *AlamatResponse              `json:"alamat,omitempty"`
 	OrangTua             []OrangTuaResponse           `json:"orang_tua,omitempty"`
 	Wali                 *WaliResponse                `json:"wali,omitempty"`
@@ -63,6 +157,7 @@ type SiswaDetailResponse struct {
 	CatatanSemester      []CatatanSemesterResponse    `json:"catatan_semester,omitempty"`
 	NilaiIjazah          []NilaiIjazahResponse        `json:"nilai_ijazah,omitempty"`
 	MeninggalkanSekolah  *MeninggalkanSekolahResponse `json:"meninggalkan_sekolah,omitempty"`
+	KenaikanKelas        []KenaikanKelasResponse      `json:"kenaikan_kelas,omitempty"`
 }
 
 // AlamatResponse for address
@@ -80,7 +175,7 @@ type AlamatResponse struct {
 	Transportasi   string  `json:"transportasi"`
 }
 
-// OrangTuaResponse for parent
+// OrangTuaResponse for parent. PenghasilanBulanan is left out for student portal accounts.
 type OrangTuaResponse struct {
 	ID                 uint       `json:"id"`
 	Tipe               string     `json:"tipe"`
@@ -90,13 +185,13 @@ type OrangTuaResponse struct {
 	Kewarganegaraan    string     `json:"kewarganegaraan"`
 	PendidikanTerakhir string     `json:"pendidikan_terakhir"`
 	Pekerjaan          string     `json:"pekerjaan"`
-	PenghasilanBulanan float64    `json:"penghasilan_bulanan"`
+	PenghasilanBulanan *float64   `json:"penghasilan_bulanan,omitempty"`
 	Alamat             string     `json:"alamat"`
 	NoTelepon          string     `json:"no_telepon"`
 	MasihHidup         bool       `json:"masih_hidup"`
 }
 
-// WaliResponse for guardian
+// WaliResponse for guardian. PenghasilanBulanan is left out for student portal accounts.
 type WaliResponse struct {
 	ID                  uint       `json:"id"`
 	Nama                string     `json:"nama"`
@@ -106,7 +201,7 @@ type WaliResponse struct {
 	Kewarganegaraan     string     `json:"kewarganegaraan"`
 	PendidikanTerakhir  string     `json:"pendidikan_terakhir"`
 	Pekerjaan           string     `json:"pekerjaan"`
-	PenghasilanBulanan  float64    `json:"penghasilan_bulanan"`
+	PenghasilanBulanan  *float64   `json:"penghasilan_bulanan,omitempty"`
 	Alamat              string     `json:"alamat"`
 	NoTelepon           string     `json:"no_telepon"`
 	HubunganDenganSiswa string     `json:"hubungan_dengan_siswa"`
@@ -148,29 +243,40 @@ type PendidikanResponse struct {
 	AlasanPindah    string     `json:"alasan_pindah"`
 }
 
+// SiswaRingkasResponse for student summary embedded in cross-student listings
+type SiswaRingkasResponse struct {
+	ID          uint   `json:"id"`
+	NoInduk     string `json:"no_induk"`
+	NISN        string `json:"nisn"`
+	NamaLengkap string `json:"nama_lengkap"`
+}
+
 // KepribadianResponse for personality
 type KepribadianResponse struct {
-	ID             uint   `json:"id"`
-	Aspek          string `json:"aspek"`
-	Nilai          string `json:"nilai"`
-	TahunPelajaran string `json:"tahun_pelajaran"`
+	ID             uint                  `json:"id"`
+	Aspek          string                `json:"aspek"`
+	Nilai          string                `json:"nilai"`
+	TahunPelajaran string                `json:"tahun_pelajaran"`
+	Siswa          *SiswaRingkasResponse `json:"siswa,omitempty"`
 }
 
 // PrestasiResponse for achievement
 type PrestasiResponse struct {
-	ID         uint   `json:"id"`
-	Bidang     string `json:"bidang"`
-	Keterangan string `json:"keterangan"`
-	Tahun      uint   `json:"tahun"`
-	Tingkat    string `json:"tingkat"`
+	ID         uint                  `json:"id"`
+	Bidang     string                `json:"bidang"`
+	Keterangan string                `json:"keterangan"`
+	Tahun      uint                  `json:"tahun"`
+	Tingkat    string                `json:"tingkat"`
+	Siswa      *SiswaRingkasResponse `json:"siswa,omitempty"`
 }
 
 // BeasiswaResponse for scholarship
 type BeasiswaResponse struct {
-	ID             uint   `json:"id"`
-	TahunPelajaran string `json:"tahun_pelajaran"`
-	Pemberi        string `json:"pemberi"`
-	Keterangan     string `json:"keterangan"`
+	ID             uint                  `json:"id"`
+	TahunPelajaran string                `json:"tahun_pelajaran"`
+	Pemberi        string                `json:"pemberi"`
+	Keterangan     string                `json:"keterangan"`
+	Siswa          *SiswaRingkasResponse `json:"siswa,omitempty"`
 }
 
 // KehadiranResponse for attendance
@@ -186,6 +292,17 @@ type KehadiranResponse struct {
 	JumlahHariEfektif uint    `json:"jumlah_hari_efektif"`
 }
 
+// KehadiranHarianResponse for daily attendance
+type KehadiranHarianResponse struct {
+	ID         uint      `json:"id"`
+	SiswaID    uint      `json:"siswa_id"`
+	Tanggal    time.Time `json:"tanggal"`
+	Kelas      string    `json:"kelas"`
+	Semester   uint8     `json:"semester"`
+	Status     string    `json:"status"`
+	Keterangan string    `json:"keterangan"`
+}
+
 // MataPelajaranResponse for subject
 type MataPelajaranResponse struct {
 	ID          uint   `json:"id"`
@@ -193,6 +310,7 @@ type MataPelajaranResponse struct {
 	Nama        string `json:"nama"`
 	Kelompok    string `json:"kelompok"`
 	SubKelompok string `json:"sub_kelompok"`
+	Aktif       bool   `json:"aktif"`
 }
 
 // NilaiSemesterResponse for semester grade
@@ -213,6 +331,7 @@ type NilaiSemesterResponse struct {
 // NilaiSikapResponse for attitude grade
 type NilaiSikapResponse struct {
 	ID                 uint   `json:"id"`
+	SiswaID            uint   `json:"siswa_id"`
 	Kelas              string `json:"kelas"`
 	Semester           uint8  `json:"semester"`
 	DeskripsiSpiritual string `json:"deskripsi_spiritual"`
@@ -291,3 +410,319 @@ type PemeriksaanResponse struct {
 	Jabatan       string    `json:"jabatan"`
 	Keterangan    string    `json:"keterangan"`
 }
+
+// RombelResponse for class group
+type RombelResponse struct {
+	ID             uint          `json:"id"`
+	Nama           string        `json:"nama"`
+	Tingkat        string        `json:"tingkat"`
+	Jurusan        string        `json:"jurusan"`
+	TahunPelajaran string        `json:"tahun_pelajaran"`
+	WaliKelas      *UserResponse `json:"wali_kelas,omitempty"`
+	JumlahAnggota  int64         `json:"jumlah_anggota"`
+}
+
+// TahunPelajaranResponse for academic year
+type TahunPelajaranResponse struct {
+	ID             uint      `json:"id"`
+	Label          string    `json:"label"`
+	TanggalMulai   time.Time `json:"tanggal_mulai"`
+	TanggalSelesai time.Time `json:"tanggal_selesai"`
+	IsActive       bool      `json:"is_active"`
+	SemesterAktif  uint8     `json:"semester_aktif"`
+}
+
+// KenaikanKelasPreviewResponse for a student's evaluated promotion decision
+type KenaikanKelasPreviewResponse struct {
+	SiswaID           uint     `json:"siswa_id"`
+	NoInduk           string   `json:"no_induk"`
+	NamaLengkap       string   `json:"nama_lengkap"`
+	RombelID          uint     `json:"rombel_id"`
+	Rombel            string   `json:"rombel"`
+	TingkatAsal       string   `json:"tingkat_asal"`
+	TingkatTujuan     string   `json:"tingkat_tujuan"`
+	Keputusan         string   `json:"keputusan"`
+	PersentaseHadir   *float64 `json:"persentase_hadir"`
+	JumlahTidakTuntas int64    `json:"jumlah_tidak_tuntas"`
+	Alasan            []string `json:"alasan,omitempty"`
+	Override          bool     `json:"override"`
+	Catatan           string   `json:"catatan,omitempty"`
+}
+
+// KenaikanKelasSummaryResponse for the result of a promotion evaluation
+type KenaikanKelasSummaryResponse struct {
+	TahunPelajaran string                         `json:"tahun_pelajaran"`
+	JumlahSiswa    int                            `json:"jumlah_siswa"`
+	JumlahNaik     int                            `json:"jumlah_naik"`
+	JumlahTinggal  int                            `json:"jumlah_tinggal"`
+	Siswa          []KenaikanKelasPreviewResponse `json:"siswa"`
+}
+
+// KenaikanKelasResponse for a recorded promotion decision
+type KenaikanKelasResponse struct {
+	ID             uint      `json:"id"`
+	TahunPelajaran string    `json:"tahun_pelajaran"`
+	TingkatAsal    string    `json:"tingkat_asal"`
+	TingkatTujuan  string    `json:"tingkat_tujuan"`
+	Keputusan      string    `json:"keputusan"`
+	Catatan        string    `json:"catatan"`
+	Tanggal        time.Time `json:"tanggal"`
+}
+
+// KelulusanResponse for the result of a cohort graduation
+type KelulusanResponse struct {
+	TahunPelajaran string                   `json:"tahun_pelajaran"`
+	Tanggal        time.Time                `json:"tanggal"`
+	JumlahLulus    int                      `json:"jumlah_lulus"`
+	Siswa          []KelulusanSiswaResponse `json:"siswa"`
+}
+
+// KelulusanSiswaResponse for a graduated student
+type KelulusanSiswaResponse struct {
+	SiswaID     uint   `json:"siswa_id"`
+	NoInduk     string `json:"no_induk"`
+	NamaLengkap string `json:"nama_lengkap"`
+	Rombel      string `json:"rombel"`
+	NoIjazah    string `json:"no_ijazah"`
+}
+
+// SkemaPenilaianResponse for grading scheme
+type SkemaPenilaianResponse struct {
+	ID             uint                   `json:"id"`
+	TahunPelajaran string                 `json:"tahun_pelajaran"`
+	MataPelajaran  *MataPelajaranResponse `json:"mata_pelajaran"`
+	KKM            uint                   `json:"kkm"`
+	BatasA         uint                   `json:"batas_a"`
+	BatasB         uint                   `json:"batas_b"`
+	BatasC         uint                   `json:"batas_c"`
+}
+
+// HitungUlangPredikatResponse for the result of recomputing predikat
+type HitungUlangPredikatResponse struct {
+	SkemaID          uint  `json:"skema_id"`
+	JumlahDiperbarui int64 `json:"jumlah_diperbarui"`
+}
+
+// KurikulumResponse for curriculum entry
+type KurikulumResponse struct {
+	ID                 uint                        `json:"id"`
+	KompetensiKeahlian *KompetensiKeahlianResponse `json:"kompetensi_keahlian"`
+	Tingkat            string                      `json:"tingkat"`
+	Semester           uint8                       `json:"semester"`
+	Urutan             uint                        `json:"urutan"`
+	MenitPerMinggu     uint                        `json:"menit_per_minggu"`
+	MataPelajaran      *MataPelajaranResponse      `json:"mata_pelajaran"`
+}
+
+// BidangKeahlianResponse for bidang keahlian, with its programs when listed as a tree
+type BidangKeahlianResponse struct {
+	ID              uint                      `json:"id"`
+	Kode            string                    `json:"kode"`
+	Nama            string                    `json:"nama"`
+	ProgramKeahlian []ProgramKeahlianResponse `json:"program_keahlian,omitempty"`
+}
+
+// ProgramKeahlianResponse for program keahlian
+type ProgramKeahlianResponse struct {
+	ID                 uint                         `json:"id"`
+	BidangKeahlianID   uint                         `json:"bidang_keahlian_id"`
+	Kode               string                       `json:"kode"`
+	Nama               string                       `json:"nama"`
+	KompetensiKeahlian []KompetensiKeahlianResponse `json:"kompetensi_keahlian,omitempty"`
+}
+
+// KompetensiKeahlianResponse for kompetensi keahlian
+type KompetensiKeahlianResponse struct {
+	ID                uint   `json:"id"`
+	Kode              string `json:"kode"`
+	Nama              string `json:"nama"`
+	Singkatan         string `json:"singkatan"`
+	ProgramKeahlianID uint   `json:"program_keahlian_id"`
+	ProgramKeahlian   string `json:"program_keahlian,omitempty"`
+	BidangKeahlianID  uint   `json:"bidang_keahlian_id,omitempty"`
+	BidangKeahlian    string `json:"bidang_keahlian,omitempty"`
+}
+
+// RiwayatJurusanResponse for a student's kompetensi keahlian history entry
+type RiwayatJurusanResponse struct {
+	ID                 uint                        `json:"id"`
+	KompetensiKeahlian *KompetensiKeahlianResponse `json:"kompetensi_keahlian"`
+	TanggalMulai       time.Time                   `json:"tanggal_mulai"`
+	TanggalSelesai     *time.Time                  `json:"tanggal_selesai"`
+	Keterangan         string                      `json:"keterangan"`
+}
+
+// RekapJurusanResponse for the number of students per kompetensi keahlian
+type RekapJurusanResponse struct {
+	KompetensiKeahlian *KompetensiKeahlianResponse `json:"kompetensi_keahlian"`
+	JumlahLakiLaki     int64                       `json:"jumlah_laki_laki"`
+	JumlahPerempuan    int64                       `json:"jumlah_perempuan"`
+	Jumlah             int64                       `json:"jumlah"`
+}
+
+// LeggerResponse for the grade matrix (legger) of a class group
+type LeggerResponse struct {
+	RombelID       uint                    `json:"rombel_id"`
+	Rombel         string                  `json:"rombel"`
+	Kelas          string                  `json:"kelas"`
+	Semester       uint8                   `json:"semester"`
+	TahunPelajaran string                  `json:"tahun_pelajaran"`
+	MataPelajaran  []MataPelajaranResponse `json:"mata_pelajaran"`
+	Siswa          []LeggerSiswaResponse   `json:"siswa"`
+}
+
+// LeggerSiswaResponse for one student row of a legger. Nilai follows the order of LeggerResponse.MataPelajaran.
+// Peringkat and PeringkatTingkat follow NilaiPeringkat, the weighted average used for ranking.
+type LeggerSiswaResponse struct {
+	SiswaID              uint                  `json:"siswa_id"`
+	NoInduk              string                `json:"no_induk"`
+	NISN                 string                `json:"nisn"`
+	NamaLengkap          string                `json:"nama_lengkap"`
+	Nilai                []LeggerNilaiResponse `json:"nilai"`
+	JumlahPengetahuan    uint                  `json:"jumlah_pengetahuan"`
+	JumlahKeterampilan   uint                  `json:"jumlah_keterampilan"`
+	Jumlah               uint                  `json:"jumlah"`
+	RataRataPengetahuan  float64               `json:"rata_rata_pengetahuan"`
+	RataRataKeterampilan float64               `json:"rata_rata_keterampilan"`
+	RataRata             float64               `json:"rata_rata"`
+	NilaiPeringkat       float64               `json:"nilai_peringkat"`
+	Peringkat            int                   `json:"peringkat"`
+	PeringkatTingkat     int                   `json:"peringkat_tingkat"`
+}
+
+// LeggerNilaiResponse for one subject cell of a legger, empty when the student has no grade
+type LeggerNilaiResponse struct {
+	MataPelajaranID   uint  `json:"mata_pelajaran_id"`
+	NilaiPengetahuan  *uint `json:"nilai_pengetahuan"`
+	NilaiKeterampilan *uint `json:"nilai_keterampilan"`
+}
+
+// ImportSiswaResponse for the row-by-row report of a bulk student import
+type ImportSiswaResponse struct {
+	DryRun     bool                       `json:"dry_run"`
+	TotalBaris int                        `json:"total_baris"`
+	BarisValid int                        `json:"baris_valid"`
+	BarisGagal int                        `json:"baris_gagal"`
+	Dibuat     int                        `json:"dibuat"`
+	Errors     []ImportSiswaErrorResponse `json:"errors"`
+}
+
+// ImportSiswaErrorResponse for the errors of one spreadsheet row; Baris is the row number in the file
+type ImportSiswaErrorResponse struct {
+	Baris   int      `json:"baris"`
+	NISN    string   `json:"nisn"`
+	NoInduk string   `json:"no_induk"`
+	Errors  []string `json:"errors"`
+}
+
+// DapodikCompareResponse for the comparison of a Dapodik export with our records.
+// Siswa only lists rows that differ or whose NISN is not found.
+type DapodikCompareResponse struct {
+	TotalBaris     int                    `json:"total_baris"`
+	Cocok          int                    `json:"cocok"`
+	Berbeda        int                    `json:"berbeda"`
+	TidakDitemukan int                    `json:"tidak_ditemukan"`
+	Siswa          []DapodikSiswaResponse `json:"siswa"`
+}
+
+// DapodikSiswaResponse for one row of a Dapodik export; Status is berbeda or tidak_ditemukan
+type DapodikSiswaResponse struct {
+	Baris     int                        `json:"baris"`
+	NISN      string                     `json:"nisn"`
+	Nama      string                     `json:"nama"`
+	SiswaID   *uint                      `json:"siswa_id"`
+	Status    string                     `json:"status"`
+	Perbedaan []DapodikPerbedaanResponse `json:"perbedaan"`
+}
+
+// DapodikPerbedaanResponse for one field that differs between our records and Dapodik
+type DapodikPerbedaanResponse struct {
+	Field           string `json:"field"`
+	Kolom           string `json:"kolom"`
+	NilaiSistem     string `json:"nilai_sistem"`
+	NilaiDapodik    string `json:"nilai_dapodik"`
+	DapatDiterapkan bool   `json:"dapat_diterapkan"`
+}
+
+// DapodikApplyResponse for the result of applying Dapodik field choices
+type DapodikApplyResponse struct {
+	SiswaDiperbarui     int `json:"siswa_diperbarui"`
+	DiambilDariDapodik  int `json:"diambil_dari_dapodik"`
+	DipertahankanSistem int `json:"dipertahankan_sistem"`
+}
+
+// PeringkatResponse for the ranking of a class group or a whole tingkat
+type PeringkatResponse struct {
+	RombelID       *uint                    `json:"rombel_id,omitempty"`
+	Rombel         string                   `json:"rombel,omitempty"`
+	Tingkat        string                   `json:"tingkat"`
+	Semester       uint8                    `json:"semester"`
+	TahunPelajaran string                   `json:"tahun_pelajaran"`
+	Bobot          BobotPeringkatResponse   `json:"bobot"`
+	Siswa          []PeringkatSiswaResponse `json:"siswa"`
+}
+
+// BobotPeringkatResponse for the weights used to compute the ranking average
+type BobotPeringkatResponse struct {
+	Pengetahuan  float64            `json:"pengetahuan"`
+	Keterampilan float64            `json:"keterampilan"`
+	Kelompok     map[string]float64 `json:"kelompok"`
+}
+
+// PeringkatSiswaResponse for the rank of one student. Ranks are 0 for students without grades;
+// JumlahKelas and JumlahTingkat count the ranked students.
+type PeringkatSiswaResponse struct {
+	SiswaID          uint    `json:"siswa_id"`
+	NoInduk          string  `json:"no_induk"`
+	NISN             string  `json:"nisn"`
+	NamaLengkap      string  `json:"nama_lengkap"`
+	RombelID         uint    `json:"rombel_id"`
+	Rombel           string  `json:"rombel"`
+	JumlahMapel      int     `json:"jumlah_mapel"`
+	RataRata         float64 `json:"rata_rata"`
+	PeringkatKelas   int     `json:"peringkat_kelas"`
+	JumlahKelas      int     `json:"jumlah_kelas"`
+	PeringkatTingkat int     `json:"peringkat_tingkat"`
+	JumlahTingkat    int     `json:"jumlah_tingkat"`
+}
+
+// StatistikResponse for the dashboard summary. Counts by tingkat use the class
+// groups of TahunPelajaran, the active academic year.
+type StatistikResponse struct {
+	TotalSiswaAktif int64                        `json:"total_siswa_aktif"`
+	TahunPelajaran  string                       `json:"tahun_pelajaran"`
+	PerTingkat      []StatistikJumlahResponse    `json:"per_tingkat"`
+	TanpaRombel     int64                        `json:"tanpa_rombel"`
+	PerJenisKelamin []StatistikJumlahResponse    `json:"per_jenis_kelamin"`
+	PerAgama        []StatistikJumlahResponse    `json:"per_agama"`
+	PerJurusan      []RekapJurusanResponse       `json:"per_jurusan"`
+	Penerimaan      []StatistikTahunResponse     `json:"penerimaan"`
+	Keluar          []StatistikTahunResponse     `json:"keluar"`
+	Kehadiran       []StatistikKehadiranResponse `json:"kehadiran"`
+	Prestasi        []StatistikJumlahResponse    `json:"prestasi"`
+	DihitungPada    time.Time                    `json:"dihitung_pada"`
+}
+
+// StatistikJumlahResponse for the count of one group
+type StatistikJumlahResponse struct {
+	Label  string `json:"label"`
+	Jumlah int64  `json:"jumlah"`
+}
+
+// StatistikTahunResponse for the count of one year, split per type
+type StatistikTahunResponse struct {
+	Tahun   int              `json:"tahun"`
+	Jumlah  int64            `json:"jumlah"`
+	PerTipe map[string]int64 `json:"per_tipe"`
+}
+
+// StatistikKehadiranResponse for the average attendance of one kelas and semester
+type StatistikKehadiranResponse struct {
+	Kelas                   string  `json:"kelas"`
+	Semester                uint8   `json:"semester"`
+	JumlahSiswa             int64   `json:"jumlah_siswa"`
+	RataRataPersentaseHadir float64 `json:"rata_rata_persentase_hadir"`
+	RataRataSakit           float64 `json:"rata_rata_sakit"`
+	RataRataIzin            float64 `json:"rata_rata_izin"`
+	RataRataAlpa            float64 `json:"rata_rata_alpa"`
+}
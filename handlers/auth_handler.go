@@ -1,6 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"github.com/kampunk/api-siswa/dtos/requests"
 	"github.com/kampunk/api-siswa/middlewares"
@@ -20,7 +25,7 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 
 // Login godoc
 // @Summary Login admin
-// @Description Authenticate admin user and get JWT token
+// @Description Authenticate a user and start a session. Repeated failures slow down and then temporarily lock the account, and too many failures from one IP address block it; both answer 429 with a Retry-After header. Returns a short-lived JWT access token and a refresh token for POST /auth/refresh. For a user with two-factor authentication only two_factor_required and a challenge_token for POST /auth/login/2fa are returned.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -28,6 +33,7 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 // @Success 200 {object} utils.Response{data=responses.LoginResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 429 {object} utils.Response
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req requests.LoginRequest
@@ -36,15 +42,119 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(req, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
-		utils.UnauthorizedResponse(c, err.Error())
+		loginErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Login successful", response)
+}
+
+// LoginTwoFactor godoc
+// @Summary Login second step
+// @Description Complete the login of a user with two-factor authentication using the challenge token of POST /auth/login (valid 5 minutes) and an authenticator or recovery code
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.LoginTwoFactorRequest true "Challenge token and code"
+// @Success 200 {object} utils.Response{data=responses.LoginResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req requests.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.authService.LoginTwoFactor(req, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		loginErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, "Login successful", response)
 }
 
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing an old one revokes its session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.Response{data=responses.LoginResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req requests.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.authService.Refresh(req)
+	if err != nil {
+		utils.UnauthorizedResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Token refreshed", response)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the session of the current access token. Its access and refresh tokens stop working immediately.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID, exists := middlewares.GetSessionIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	if err := h.authService.Logout(sessionID); err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Logout successful", nil)
+}
+
+// LogoutAll godoc
+// @Summary Logout from all devices
+// @Description Revoke every session of the current user, including the current one
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.Response{data=responses.RevokedSessionsResponse}
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	response, err := h.authService.LogoutAll(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "All sessions logged out", response)
+}
+
 // Register godoc
 // @Summary Register new admin
 // @Description Create a new admin user
@@ -96,3 +206,15 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Profile retrieved", response)
 }
+
+// loginErrorResponse answers a failed login with 401, or with 429 and a
+// Retry-After header while the login is blocked
+func loginErrorResponse(c *gin.Context, err error) {
+	var blocked *services.LoginBlockedError
+	if errors.As(err, &blocked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+		utils.ErrorResponse(c, http.StatusTooManyRequests, blocked.Message, nil)
+		return
+	}
+	utils.UnauthorizedResponse(c, err.Error())
+}
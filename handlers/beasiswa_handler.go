@@ -53,6 +53,80 @@ func (h *BeasiswaHandler) Add(c *gin.Context) {
 	utils.CreatedResponse(c, "Scholarship record added successfully", response)
 }
 
+// FindBySiswaID godoc
+// @Summary Get student scholarships
+// @Description Get paginated scholarships of a student
+// @Tags Beasiswa
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param tahun_pelajaran query string false "Academic year filter"
+// @Param pemberi query string false "Provider filter"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.BeasiswaResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/beasiswa [get]
+func (h *BeasiswaHandler) FindBySiswaID(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	h.list(c, uint(siswaID))
+}
+
+// FindAll godoc
+// @Summary Get all scholarships
+// @Description Get paginated scholarships across all students, e.g. all scholarships from a given provider
+// @Tags Beasiswa
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param tahun_pelajaran query string false "Academic year filter"
+// @Param pemberi query string false "Provider filter"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.BeasiswaResponse}
+// @Security BearerAuth
+// @Router /beasiswa [get]
+func (h *BeasiswaHandler) FindAll(c *gin.Context) {
+	h.list(c, 0)
+}
+
+// Update godoc
+// @Summary Update scholarship
+// @Description Update scholarship
+// @Tags Beasiswa
+// @Accept json
+// @Produce json
+// @Param id path int true "Scholarship Record ID"
+// @Param request body requests.UpdateBeasiswaRequest true "Scholarship data"
+// @Success 200 {object} utils.Response{data=responses.BeasiswaResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /beasiswa/{id} [put]
+func (h *BeasiswaHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid scholarship record ID", nil)
+		return
+	}
+
+	var req requests.UpdateBeasiswaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Scholarship updated successfully", response)
+}
+
 // Delete godoc
 // @Summary Delete scholarship
 // @Description Delete scholarship record
@@ -76,3 +150,26 @@ func (h *BeasiswaHandler) Delete(c *gin.Context) {
 
 	utils.NoContentResponse(c)
 }
+
+// list binds pagination and filters and writes a paginated scholarship listing
+func (h *BeasiswaHandler) list(c *gin.Context, siswaID uint) {
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	var filter requests.BeasiswaFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, pageInfo, err := h.service.FindAll(siswaID, pagination, filter)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Scholarships retrieved", response, pageInfo)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// BukuIndukHandler handles buku induk export endpoints
+type BukuIndukHandler struct {
+	service *services.BukuIndukService
+}
+
+func NewBukuIndukHandler(service *services.BukuIndukService) *BukuIndukHandler {
+	return &BukuIndukHandler{service: service}
+}
+
+// Get godoc
+// @Summary Export student buku induk
+// @Description Render the full buku induk record of a student, including the photo and the inspection log, as PDF or printable HTML
+// @Tags Buku Induk
+// @Produce application/pdf,text/html
+// @Param id path int true "Student ID"
+// @Param format query string false "Output format (pdf, html), default pdf"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/buku-induk [get]
+func (h *BukuIndukHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.BukuIndukRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	if req.Format == "html" {
+		data, filename, err := h.service.GenerateHTML(uint(id))
+		if err != nil {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+
+		c.Header("Content-Disposition", "inline; filename="+filename)
+		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+		return
+	}
+
+	data, filename, err := h.service.GeneratePDF(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", "inline; filename="+filename)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
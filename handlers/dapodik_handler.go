@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// DapodikHandler handles Dapodik export and reconciliation endpoints
+type DapodikHandler struct {
+	service *services.DapodikService
+}
+
+func NewDapodikHandler(service *services.DapodikService) *DapodikHandler {
+	return &DapodikHandler{service: service}
+}
+
+// Export godoc
+// @Summary Export students for Dapodik
+// @Description Download students in the Dapodik peserta didik spreadsheet layout, using the same search and filters as the student list
+// @Tags Dapodik
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param search query string false "Search by name, NISN, or registration number"
+// @Param rombel_id query int false "Class group filter"
+// @Param status query string false "Student status (aktif, tamat, pindah, putus, semua)" default(aktif)
+// @Param kompetensi_keahlian_id query int false "Kompetensi keahlian filter"
+// @Param program_keahlian_id query int false "Program keahlian filter"
+// @Param bidang_keahlian_id query int false "Bidang keahlian filter"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /dapodik/export [get]
+func (h *DapodikHandler) Export(c *gin.Context) {
+	var filter requests.SiswaFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	data, filename, err := h.service.Export(c.Query("search"), filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+}
+
+// Compare godoc
+// @Summary Compare a Dapodik export
+// @Description Match the rows of a Dapodik peserta didik export (XLSX or CSV) to students by NISN and list the fields that differ
+// @Tags Dapodik
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Dapodik peserta didik export"
+// @Success 200 {object} utils.Response{data=responses.DapodikCompareResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /dapodik/compare [post]
+func (h *DapodikHandler) Compare(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequestResponse(c, "No file uploaded", err.Error())
+		return
+	}
+
+	response, err := h.service.Compare(file)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Dapodik export compared", response)
+}
+
+// Apply godoc
+// @Summary Apply Dapodik field choices
+// @Description Take over the Dapodik value of every field where dapodik is chosen; fields where sistem is chosen keep our value. All students are saved in one transaction.
+// @Tags Dapodik
+// @Accept json
+// @Produce json
+// @Param request body requests.DapodikApplyRequest true "Field choices per student"
+// @Success 200 {object} utils.Response{data=responses.DapodikApplyResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /dapodik/apply [post]
+func (h *DapodikHandler) Apply(c *gin.Context) {
+	var req requests.DapodikApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Apply(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Dapodik choices applied", response)
+}
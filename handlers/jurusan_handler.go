@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// JurusanHandler handles bidang, program and kompetensi keahlian endpoints
+type JurusanHandler struct {
+	service *services.JurusanService
+}
+
+func NewJurusanHandler(service *services.JurusanService) *JurusanHandler {
+	return &JurusanHandler{service: service}
+}
+
+// CreateBidang godoc
+// @Summary Create bidang keahlian
+// @Description Create a new bidang keahlian
+// @Tags Jurusan
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateBidangKeahlianRequest true "Bidang keahlian data"
+// @Success 201 {object} utils.Response{data=responses.BidangKeahlianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /bidang-keahlian [post]
+func (h *JurusanHandler) CreateBidang(c *gin.Context) {
+	var req requests.CreateBidangKeahlianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.CreateBidang(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Bidang keahlian created successfully", response)
+}
+
+// FindAllBidang godoc
+// @Summary Get jurusan hierarchy
+// @Description Get all bidang keahlian with their program and kompetensi keahlian
+// @Tags Jurusan
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]responses.BidangKeahlianResponse}
+// @Security BearerAuth
+// @Router /bidang-keahlian [get]
+func (h *JurusanHandler) FindAllBidang(c *gin.Context) {
+	response, err := h.service.FindAllBidang()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Bidang keahlian retrieved", response)
+}
+
+// UpdateBidang godoc
+// @Summary Update bidang keahlian
+// @Description Update bidang keahlian data
+// @Tags Jurusan
+// @Accept json
+// @Produce json
+// @Param id path int true "Bidang keahlian ID"
+// @Param request body requests.UpdateBidangKeahlianRequest true "Bidang keahlian data"
+// @Success 200 {object} utils.Response{data=responses.BidangKeahlianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /bidang-keahlian/{id} [put]
+func (h *JurusanHandler) UpdateBidang(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid bidang keahlian ID", nil)
+		return
+	}
+
+	var req requests.UpdateBidangKeahlianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.UpdateBidang(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Bidang keahlian updated successfully", response)
+}
+
+// DeleteBidang godoc
+// @Summary Delete bidang keahlian
+// @Description Delete a bidang keahlian that has no program keahlian
+// @Tags Jurusan
+// @Param id path int true "Bidang keahlian ID"
+// @Success 204
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /bidang-keahlian/{id} [delete]
+func (h *JurusanHandler) DeleteBidang(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid bidang keahlian ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteBidang(uint(id)); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// CreateProgram godoc
+// @Summary Create program keahlian
+// @Description Create a new program keahlian within a bidang keahlian
+// @Tags Jurusan
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateProgramKeahlianRequest true "Program keahlian data"
+// @Success 201 {object} utils.Response{data=responses.ProgramKeahlianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /program-keahlian [post]
+func (h *JurusanHandler) CreateProgram(c *gin.Context) {
+	var req requests.CreateProgramKeahlianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.CreateProgram(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Program keahlian created successfully", response)
+}
+
+// FindAllProgram godoc
+// @Summary Get program keahlian
+// @Description Get program keahlian, optionally filtered by bidang keahlian
+// @Tags Jurusan
+// @Produce json
+// @Param bidang_keahlian_id query int false "Filter by bidang keahlian ID"
+// @Success 200 {object} utils.Response{data=[]responses.ProgramKeahlianResponse}
+// @Security BearerAuth
+// @Router /program-keahlian [get]
+func (h *JurusanHandler) FindAllProgram(c *gin.Context) {
+	var filter requests.ProgramKeahlianFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, err := h.service.FindAllProgram(filter.BidangKeahlianID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Program keahlian retrieved", response)
+}
+
+// UpdateProgram godoc
+// @Summary Update program keahlian
+// @Description Update program keahlian data or move it to another bidang keahlian
+// @Tags Jurusan
+// @Accept json
+// @Produce json
+// @Param id path int true "Program keahlian ID"
+// @Param request body requests.UpdateProgramKeahlianRequest true "Program keahlian data"
+// @Success 200 {object} utils.Response{data=responses.ProgramKeahlianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /program-keahlian/{id} [put]
+func (h *JurusanHandler) UpdateProgram(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid program keahlian ID", nil)
+		return
+	}
+
+	var req requests.UpdateProgramKeahlianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.UpdateProgram(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Program keahlian updated successfully", response)
+}
+
+// DeleteProgram godoc
+// @Summary Delete program keahlian
+// @Description Delete a program keahlian that has no kompetensi keahlian
+// @Tags Jurusan
+// @Param id path int true "Program keahlian ID"
+// @Success 204
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /program-keahlian/{id} [delete]
+func (h *JurusanHandler) DeleteProgram(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid program keahlian ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteProgram(uint(id)); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// CreateKompetensi godoc
+// @Summary Create kompetensi keahlian
+// @Description Create a new kompetensi keahlian within a program keahlian
+// @Tags Jurusan
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateKompetensiKeahlianRequest true "Kompetensi keahlian data"
+// @Success 201 {object} utils.Response{data=responses.KompetensiKeahlianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kompetensi-keahlian [post]
+func (h *JurusanHandler) CreateKompetensi(c *gin.Context) {
+	var req requests.CreateKompetensiKeahlianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.CreateKompetensi(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Kompetensi keahlian created successfully", response)
+}
+
+// FindAllKompetensi godoc
+// @Summary Get kompetensi keahlian
+// @Description Get kompetensi keahlian, optionally filtered by program or bidang keahlian
+// @Tags Jurusan
+// @Produce json
+// @Param program_keahlian_id query int false "Filter by program keahlian ID"
+// @Param bidang_keahlian_id query int false "Filter by bidang keahlian ID"
+// @Success 200 {object} utils.Response{data=[]responses.KompetensiKeahlianResponse}
+// @Security BearerAuth
+// @Router /kompetensi-keahlian [get]
+func (h *JurusanHandler) FindAllKompetensi(c *gin.Context) {
+	var filter requests.KompetensiKeahlianFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, err := h.service.FindAllKompetensi(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Kompetensi keahlian retrieved", response)
+}
+
+// FindKompetensiByID godoc
+// @Summary Get kompetensi keahlian by ID
+// @Description Get kompetensi keahlian details with its program and bidang keahlian
+// @Tags Jurusan
+// @Produce json
+// @Param id path int true "Kompetensi keahlian ID"
+// @Success 200 {object} utils.Response{data=responses.KompetensiKeahlianResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /kompetensi-keahlian/{id} [get]
+func (h *JurusanHandler) FindKompetensiByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid kompetensi keahlian ID", nil)
+		return
+	}
+
+	response, err := h.service.FindKompetensiByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Kompetensi keahlian retrieved", response)
+}
+
+// UpdateKompetensi godoc
+// @Summary Update kompetensi keahlian
+// @Description Update kompetensi keahlian data or move it to another program keahlian
+// @Tags Jurusan
+// @Accept json
+// @Produce json
+// @Param id path int true "Kompetensi keahlian ID"
+// @Param request body requests.UpdateKompetensiKeahlianRequest true "Kompetensi keahlian data"
+// @Success 200 {object} utils.Response{data=responses.KompetensiKeahlianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kompetensi-keahlian/{id} [put]
+func (h *JurusanHandler) UpdateKompetensi(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid kompetensi keahlian ID", nil)
+		return
+	}
+
+	var req requests.UpdateKompetensiKeahlianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.UpdateKompetensi(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Kompetensi keahlian updated successfully", response)
+}
+
+// DeleteKompetensi godoc
+// @Summary Delete kompetensi keahlian
+// @Description Delete a kompetensi keahlian that is not assigned to any student or curriculum
+// @Tags Jurusan
+// @Param id path int true "Kompetensi keahlian ID"
+// @Success 204
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kompetensi-keahlian/{id} [delete]
+func (h *JurusanHandler) DeleteKompetensi(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid kompetensi keahlian ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteKompetensi(uint(id)); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// SetJurusanSiswa godoc
+// @Summary Set student jurusan
+// @Description Assign a student to a kompetensi keahlian. When the student moves, the previous assignment is closed on tanggal_mulai and kept as history
+// @Tags Jurusan
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body requests.SetJurusanSiswaRequest true "Jurusan assignment"
+// @Success 200 {object} utils.Response{data=[]responses.RiwayatJurusanResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/jurusan [put]
+func (h *JurusanHandler) SetJurusanSiswa(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.SetJurusanSiswaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.SetJurusanSiswa(uint(siswaID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Student jurusan updated successfully", response)
+}
+
+// GetRiwayatJurusan godoc
+// @Summary Get student jurusan history
+// @Description Get the kompetensi keahlian history of a student, oldest first. The current one has no tanggal_selesai
+// @Tags Jurusan
+// @Produce json
+// @Param id path int true "Student ID"
+// @Success 200 {object} utils.Response{data=[]responses.RiwayatJurusanResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/jurusan [get]
+func (h *JurusanHandler) GetRiwayatJurusan(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	response, err := h.service.GetRiwayatJurusan(uint(siswaID))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Student jurusan history retrieved", response)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// KehadiranHandler handles attendance endpoints
+type KehadiranHandler struct {
+	service *services.KehadiranService
+}
+
+func NewKehadiranHandler(service *services.KehadiranService) *KehadiranHandler {
+	return &KehadiranHandler{service: service}
+}
+
+// Upsert godoc
+// @Summary Save semester attendance
+// @Description Create or replace a student's attendance summary for a semester. persentase_hadir is computed from jumlah_hadir and jumlah_hari_efektif when omitted. Semesters recorded through the daily ledger cannot be edited here
+// @Tags Kehadiran
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body requests.CreateKehadiranRequest true "Attendance summary"
+// @Success 200 {object} utils.Response{data=responses.KehadiranResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/kehadiran [post]
+func (h *KehadiranHandler) Upsert(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.CreateKehadiranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Upsert(uint(siswaID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Attendance saved successfully", response)
+}
+
+// RecordHarian godoc
+// @Summary Record daily attendance
+// @Description Record or correct a student's attendance on one day. kelas and semester default to the student's class group in the active academic year. The semester summary is recomputed automatically
+// @Tags Kehadiran
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body requests.CreateKehadiranHarianRequest true "Daily attendance"
+// @Success 200 {object} utils.Response{data=responses.KehadiranHarianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/kehadiran-harian [post]
+func (h *KehadiranHandler) RecordHarian(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.CreateKehadiranHarianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.RecordHarian(uint(siswaID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Daily attendance saved successfully", response)
+}
+
+// GetHarian godoc
+// @Summary Get daily attendance
+// @Description Get a student's daily attendance ledger with pagination, newest first
+// @Tags Kehadiran
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param kelas query string false "Filter by grade" Enums(X, XI, XII)
+// @Param semester query int false "Filter by semester" Enums(1, 2)
+// @Param status query string false "Filter by status" Enums(hadir, sakit, izin, alpa)
+// @Param tanggal_mulai query string false "Start date (YYYY-MM-DD)"
+// @Param tanggal_selesai query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.KehadiranHarianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/kehadiran-harian [get]
+func (h *KehadiranHandler) GetHarian(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	var filter requests.KehadiranHarianFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, pageInfo, err := h.service.GetHarian(uint(siswaID), pagination, filter)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Daily attendance retrieved", response, pageInfo)
+}
+
+// RecordHarianRombel godoc
+// @Summary Record daily attendance of a class group
+// @Description Record the attendance of class group members on one day. The grade is taken from the class group and the semester defaults to the active semester
+// @Tags Kehadiran
+// @Accept json
+// @Produce json
+// @Param id path int true "Class group ID"
+// @Param request body requests.BulkKehadiranHarianRequest true "Daily attendance of the class group"
+// @Success 200 {object} utils.Response{data=[]responses.KehadiranHarianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/kehadiran-harian [post]
+func (h *KehadiranHandler) RecordHarianRombel(c *gin.Context) {
+	rombelID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var req requests.BulkKehadiranHarianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.RecordHarianRombel(uint(rombelID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Daily attendance saved successfully", response)
+}
+
+// DeleteHarian godoc
+// @Summary Delete daily attendance
+// @Description Delete a daily attendance entry and recompute the semester summary
+// @Tags Kehadiran
+// @Param id path int true "Daily attendance ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /kehadiran-harian/{id} [delete]
+func (h *KehadiranHandler) DeleteHarian(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid daily attendance ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteHarian(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// KenaikanKelasHandler handles grade promotion endpoints
+type KenaikanKelasHandler struct {
+	service *services.KenaikanKelasService
+}
+
+func NewKenaikanKelasHandler(service *services.KenaikanKelasService) *KenaikanKelasHandler {
+	return &KenaikanKelasHandler{service: service}
+}
+
+// Preview godoc
+// @Summary Preview grade promotion
+// @Description Evaluate promotion (naik / tinggal kelas) of grade X and XI students of an academic year without saving. Rules are skipped when their threshold is 0
+// @Tags Kenaikan Kelas
+// @Accept json
+// @Produce json
+// @Param request body requests.KenaikanKelasRequest true "Promotion rules"
+// @Success 200 {object} utils.Response{data=responses.KenaikanKelasSummaryResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kenaikan-kelas/preview [post]
+func (h *KenaikanKelasHandler) Preview(c *gin.Context) {
+	var req requests.KenaikanKelasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Preview(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Promotion preview generated", response)
+}
+
+// Proses godoc
+// @Summary Process grade promotion
+// @Description Evaluate promotion of an academic year, apply per-student overrides and save all decisions in one transaction
+// @Tags Kenaikan Kelas
+// @Accept json
+// @Produce json
+// @Param request body requests.ProsesKenaikanKelasRequest true "Promotion rules and overrides"
+// @Success 201 {object} utils.Response{data=responses.KenaikanKelasSummaryResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kenaikan-kelas [post]
+func (h *KenaikanKelasHandler) Proses(c *gin.Context) {
+	var req requests.ProsesKenaikanKelasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Proses(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Promotion processed successfully", response)
+}
+
+// GetBySiswaID godoc
+// @Summary Get student promotion history
+// @Description Get all promotion decisions of a student
+// @Tags Kenaikan Kelas
+// @Produce json
+// @Param id path int true "Student ID"
+// @Success 200 {object} utils.Response{data=[]responses.KenaikanKelasResponse}
+// @Security BearerAuth
+// @Router /siswa/{id}/kenaikan-kelas [get]
+func (h *KenaikanKelasHandler) GetBySiswaID(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	response, err := h.service.GetBySiswaID(uint(siswaID))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Promotion history retrieved", response)
+}
@@ -53,6 +53,82 @@ func (h *KepribadianHandler) Add(c *gin.Context) {
 	utils.CreatedResponse(c, "Personality record added successfully", response)
 }
 
+// FindBySiswaID godoc
+// @Summary Get student personality records
+// @Description Get paginated personality records of a student
+// @Tags Kepribadian
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param tahun_pelajaran query string false "Academic year filter"
+// @Param aspek query string false "Aspect filter"
+// @Param nilai query string false "Score filter (Baik, Cukup, Kurang)"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.KepribadianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/kepribadian [get]
+func (h *KepribadianHandler) FindBySiswaID(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	h.list(c, uint(siswaID))
+}
+
+// FindAll godoc
+// @Summary Get all personality records
+// @Description Get paginated personality records across all students
+// @Tags Kepribadian
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param tahun_pelajaran query string false "Academic year filter"
+// @Param aspek query string false "Aspect filter"
+// @Param nilai query string false "Score filter (Baik, Cukup, Kurang)"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.KepribadianResponse}
+// @Security BearerAuth
+// @Router /kepribadian [get]
+func (h *KepribadianHandler) FindAll(c *gin.Context) {
+	h.list(c, 0)
+}
+
+// Update godoc
+// @Summary Update personality record
+// @Description Update personality record
+// @Tags Kepribadian
+// @Accept json
+// @Produce json
+// @Param id path int true "Personality Record ID"
+// @Param request body requests.UpdateKepribadianRequest true "Personality record data"
+// @Success 200 {object} utils.Response{data=responses.KepribadianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kepribadian/{id} [put]
+func (h *KepribadianHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid personality record ID", nil)
+		return
+	}
+
+	var req requests.UpdateKepribadianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Personality record updated successfully", response)
+}
+
 // Delete godoc
 // @Summary Delete personality record
 // @Description Delete personality record
@@ -76,3 +152,26 @@ func (h *KepribadianHandler) Delete(c *gin.Context) {
 
 	utils.NoContentResponse(c)
 }
+
+// list binds pagination and filters and writes a paginated personality record listing
+func (h *KepribadianHandler) list(c *gin.Context, siswaID uint) {
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	var filter requests.KepribadianFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, pageInfo, err := h.service.FindAll(siswaID, pagination, filter)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Personality records retrieved", response, pageInfo)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// KurikulumHandler handles curriculum endpoints
+type KurikulumHandler struct {
+	service *services.KurikulumService
+}
+
+func NewKurikulumHandler(service *services.KurikulumService) *KurikulumHandler {
+	return &KurikulumHandler{service: service}
+}
+
+// Create godoc
+// @Summary Add subject to curriculum
+// @Description Add a subject to the curriculum of a kompetensi keahlian, tingkat and semester
+// @Tags Kurikulum
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateKurikulumRequest true "Curriculum entry data"
+// @Success 201 {object} utils.Response{data=responses.KurikulumResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kurikulum [post]
+func (h *KurikulumHandler) Create(c *gin.Context) {
+	var req requests.CreateKurikulumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Create(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Curriculum entry created successfully", response)
+}
+
+// FindAll godoc
+// @Summary Get curriculum
+// @Description Get curriculum entries ordered by kompetensi keahlian, tingkat, semester and urutan
+// @Tags Kurikulum
+// @Produce json
+// @Param kompetensi_keahlian_id query int false "Filter by kompetensi keahlian ID"
+// @Param tingkat query string false "Filter by tingkat (X, XI, XII)"
+// @Param semester query int false "Filter by semester (1, 2)"
+// @Param mata_pelajaran_id query int false "Filter by subject ID"
+// @Success 200 {object} utils.Response{data=[]responses.KurikulumResponse}
+// @Security BearerAuth
+// @Router /kurikulum [get]
+func (h *KurikulumHandler) FindAll(c *gin.Context) {
+	var filter requests.KurikulumFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, err := h.service.FindAll(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Curriculum retrieved", response)
+}
+
+// FindByID godoc
+// @Summary Get curriculum entry by ID
+// @Description Get curriculum entry details
+// @Tags Kurikulum
+// @Produce json
+// @Param id path int true "Curriculum entry ID"
+// @Success 200 {object} utils.Response{data=responses.KurikulumResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /kurikulum/{id} [get]
+func (h *KurikulumHandler) FindByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid curriculum entry ID", nil)
+		return
+	}
+
+	response, err := h.service.FindByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Curriculum entry retrieved", response)
+}
+
+// Update godoc
+// @Summary Update curriculum entry
+// @Description Update the ordering and minutes per week of a curriculum entry
+// @Tags Kurikulum
+// @Accept json
+// @Produce json
+// @Param id path int true "Curriculum entry ID"
+// @Param request body requests.UpdateKurikulumRequest true "Curriculum entry data"
+// @Success 200 {object} utils.Response{data=responses.KurikulumResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kurikulum/{id} [put]
+func (h *KurikulumHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid curriculum entry ID", nil)
+		return
+	}
+
+	var req requests.UpdateKurikulumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Curriculum entry updated successfully", response)
+}
+
+// Delete godoc
+// @Summary Remove subject from curriculum
+// @Description Delete a curriculum entry. Existing grades are not affected
+// @Tags Kurikulum
+// @Param id path int true "Curriculum entry ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /kurikulum/{id} [delete]
+func (h *KurikulumHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid curriculum entry ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// LeggerHandler handles class legger endpoints
+type LeggerHandler struct {
+	service *services.LeggerService
+}
+
+func NewLeggerHandler(service *services.LeggerService) *LeggerHandler {
+	return &LeggerHandler{service: service}
+}
+
+// Get godoc
+// @Summary Get class group legger
+// @Description Get the grade matrix of a class group for a semester of its academic year: one row per student with pengetahuan/keterampilan per subject, totals, averages and rank. Use format=xlsx to download a spreadsheet.
+// @Tags Rombel
+// @Produce json,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path int true "Class Group ID"
+// @Param semester query int true "Semester (1, 2)"
+// @Param format query string false "Output format (json, xlsx), default json"
+// @Success 200 {object} utils.Response{data=responses.LeggerResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/legger [get]
+func (h *LeggerHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var req requests.LeggerRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	if req.Format == "xlsx" {
+		data, filename, err := h.service.GenerateXLSX(uint(id), req.Semester)
+		if err != nil {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+
+		c.Header("Content-Disposition", "attachment; filename="+filename)
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+		return
+	}
+
+	response, err := h.service.Get(uint(id), req.Semester)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Legger retrieved", response)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/middlewares"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// LoginAttemptHandler handles login history and account unlock endpoints
+type LoginAttemptHandler struct {
+	service *services.LoginAttemptService
+}
+
+func NewLoginAttemptHandler(service *services.LoginAttemptService) *LoginAttemptHandler {
+	return &LoginAttemptHandler{service: service}
+}
+
+// MyHistory godoc
+// @Summary Get my login history
+// @Description Get the successful and failed logins of the current user, newest first
+// @Tags Auth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.LoginAttemptResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/login-history [get]
+func (h *LoginAttemptHandler) MyHistory(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	h.history(c, userID)
+}
+
+// History godoc
+// @Summary Get user login history
+// @Description Get the successful and failed logins of a user, newest first, with IP address and user agent
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.LoginAttemptResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/login-history [get]
+func (h *LoginAttemptHandler) History(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	h.history(c, uint(id))
+}
+
+// Unlock godoc
+// @Summary Unlock user account
+// @Description Clear the failed logins of a user and lift a temporary lockout before it expires
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/unlock [post]
+func (h *LoginAttemptHandler) Unlock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	if err := h.service.Unlock(uint(id)); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "User account unlocked", nil)
+}
+
+// history binds pagination and writes a paginated login history
+func (h *LoginAttemptHandler) history(c *gin.Context, userID uint) {
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	// Set defaults
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+	if pagination.PageSize < 1 {
+		pagination.PageSize = 20
+	}
+
+	response, pageInfo, err := h.service.History(userID, pagination.Page, pagination.PageSize)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Login history retrieved", response, pageInfo)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// MataPelajaranHandler handles subject endpoints
+type MataPelajaranHandler struct {
+	service *services.MataPelajaranService
+}
+
+func NewMataPelajaranHandler(service *services.MataPelajaranService) *MataPelajaranHandler {
+	return &MataPelajaranHandler{service: service}
+}
+
+// Create godoc
+// @Summary Create subject
+// @Description Create a new active subject
+// @Tags Mata Pelajaran
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateMataPelajaranRequest true "Subject data"
+// @Success 201 {object} utils.Response{data=responses.MataPelajaranResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /mata-pelajaran [post]
+func (h *MataPelajaranHandler) Create(c *gin.Context) {
+	var req requests.CreateMataPelajaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Create(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Subject created successfully", response)
+}
+
+// FindAll godoc
+// @Summary Get subjects
+// @Description Get subjects. Only active subjects are returned unless status is nonaktif or semua
+// @Tags Mata Pelajaran
+// @Produce json
+// @Param search query string false "Search by code or name"
+// @Param kelompok query string false "Filter by group (A, B, C)"
+// @Param status query string false "aktif (default), nonaktif or semua"
+// @Success 200 {object} utils.Response{data=[]responses.MataPelajaranResponse}
+// @Security BearerAuth
+// @Router /mata-pelajaran [get]
+func (h *MataPelajaranHandler) FindAll(c *gin.Context) {
+	var filter requests.MataPelajaranFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, err := h.service.FindAll(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Subjects retrieved", response)
+}
+
+// FindByID godoc
+// @Summary Get subject by ID
+// @Description Get subject details
+// @Tags Mata Pelajaran
+// @Produce json
+// @Param id path int true "Subject ID"
+// @Success 200 {object} utils.Response{data=responses.MataPelajaranResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /mata-pelajaran/{id} [get]
+func (h *MataPelajaranHandler) FindByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid subject ID", nil)
+		return
+	}
+
+	response, err := h.service.FindByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Subject retrieved", response)
+}
+
+// Update godoc
+// @Summary Update subject
+// @Description Update subject data
+// @Tags Mata Pelajaran
+// @Accept json
+// @Produce json
+// @Param id path int true "Subject ID"
+// @Param request body requests.UpdateMataPelajaranRequest true "Subject data"
+// @Success 200 {object} utils.Response{data=responses.MataPelajaranResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /mata-pelajaran/{id} [put]
+func (h *MataPelajaranHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid subject ID", nil)
+		return
+	}
+
+	var req requests.UpdateMataPelajaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Subject updated successfully", response)
+}
+
+// Activate godoc
+// @Summary Activate subject
+// @Description Mark a subject as active so it appears in the default listing again
+// @Tags Mata Pelajaran
+// @Produce json
+// @Param id path int true "Subject ID"
+// @Success 200 {object} utils.Response{data=responses.MataPelajaranResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /mata-pelajaran/{id}/aktifkan [post]
+func (h *MataPelajaranHandler) Activate(c *gin.Context) {
+	h.setAktif(c, true, "Subject activated successfully")
+}
+
+// Deactivate godoc
+// @Summary Deactivate subject
+// @Description Mark a subject as inactive. Its existing grades are kept
+// @Tags Mata Pelajaran
+// @Produce json
+// @Param id path int true "Subject ID"
+// @Success 200 {object} utils.Response{data=responses.MataPelajaranResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /mata-pelajaran/{id}/nonaktifkan [post]
+func (h *MataPelajaranHandler) Deactivate(c *gin.Context) {
+	h.setAktif(c, false, "Subject deactivated successfully")
+}
+
+func (h *MataPelajaranHandler) setAktif(c *gin.Context, aktif bool, message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid subject ID", nil)
+		return
+	}
+
+	response, err := h.service.SetAktif(uint(id), aktif)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, message, response)
+}
+
+// Delete godoc
+// @Summary Delete subject
+// @Description Delete a subject that has no grades and is not part of any curriculum
+// @Tags Mata Pelajaran
+// @Param id path int true "Subject ID"
+// @Success 204
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /mata-pelajaran/{id} [delete]
+func (h *MataPelajaranHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid subject ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
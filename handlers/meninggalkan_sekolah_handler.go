@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// MeninggalkanSekolahHandler handles leaving school endpoints
+type MeninggalkanSekolahHandler struct {
+	service *services.MeninggalkanSekolahService
+}
+
+func NewMeninggalkanSekolahHandler(service *services.MeninggalkanSekolahService) *MeninggalkanSekolahHandler {
+	return &MeninggalkanSekolahHandler{service: service}
+}
+
+// Create godoc
+// @Summary Record leaving school
+// @Description Record that a student graduated (tamat), transferred (pindah) or dropped out (putus)
+// @Tags Meninggalkan Sekolah
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body requests.CreateMeninggalkanSekolahRequest true "Leaving school data"
+// @Success 201 {object} utils.Response{data=responses.MeninggalkanSekolahResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/meninggalkan-sekolah [post]
+func (h *MeninggalkanSekolahHandler) Create(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.CreateMeninggalkanSekolahRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Create(uint(siswaID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Leaving school record created successfully", response)
+}
+
+// Get godoc
+// @Summary Get leaving school record
+// @Description Get the leaving school record of a student
+// @Tags Meninggalkan Sekolah
+// @Produce json
+// @Param id path int true "Student ID"
+// @Success 200 {object} utils.Response{data=responses.MeninggalkanSekolahResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/meninggalkan-sekolah [get]
+func (h *MeninggalkanSekolahHandler) Get(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	response, err := h.service.GetBySiswaID(uint(siswaID))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Leaving school record retrieved", response)
+}
+
+// Update godoc
+// @Summary Update leaving school record
+// @Description Update the leaving school record of a student
+// @Tags Meninggalkan Sekolah
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body requests.CreateMeninggalkanSekolahRequest true "Leaving school data"
+// @Success 200 {object} utils.Response{data=responses.MeninggalkanSekolahResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/meninggalkan-sekolah [put]
+func (h *MeninggalkanSekolahHandler) Update(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.CreateMeninggalkanSekolahRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(siswaID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Leaving school record updated successfully", response)
+}
+
+// Delete godoc
+// @Summary Delete leaving school record
+// @Description Delete the leaving school record so the student is active again
+// @Tags Meninggalkan Sekolah
+// @Param id path int true "Student ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/meninggalkan-sekolah [delete]
+func (h *MeninggalkanSekolahHandler) Delete(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(siswaID)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// Luluskan godoc
+// @Summary Graduate cohort
+// @Description Mark every active grade XII student of an academic year (optionally one class group) as graduated
+// @Tags Meninggalkan Sekolah
+// @Accept json
+// @Produce json
+// @Param request body requests.KelulusanRequest true "Graduation data"
+// @Success 201 {object} utils.Response{data=responses.KelulusanResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /kelulusan [post]
+func (h *MeninggalkanSekolahHandler) Luluskan(c *gin.Context) {
+	var req requests.KelulusanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Luluskan(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Cohort graduated successfully", response)
+}
@@ -19,27 +19,9 @@ func NewNilaiHandler(nilaiService *services.NilaiService) *NilaiHandler {
 	return &NilaiHandler{nilaiService: nilaiService}
 }
 
-// GetMataPelajaran godoc
-// @Summary Get all subjects
-// @Description Get list of all active subjects
-// @Tags Mata Pelajaran
-// @Produce json
-// @Success 200 {object} utils.Response{data=[]responses.MataPelajaranResponse}
-// @Security BearerAuth
-// @Router /mata-pelajaran [get]
-func (h *NilaiHandler) GetMataPelajaran(c *gin.Context) {
-	response, err := h.nilaiService.GetAllMataPelajaran()
-	if err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
-		return
-	}
-
-	utils.SuccessResponse(c, "Subjects retrieved", response)
-}
-
 // CreateNilaiSemester godoc
 // @Summary Create semester grade
-// @Description Create a semester grade for a student
+// @Description Create a semester grade for a student. Academic year and semester default to the active ones when omitted
 // @Tags Nilai
 // @Accept json
 // @Produce json
@@ -263,12 +245,13 @@ func (h *NilaiHandler) GetNilaiIjazah(c *gin.Context) {
 
 // CreateCatatanSemester godoc
 // @Summary Create semester notes
-// @Description Create semester notes for a student
+// @Description Create semester notes for a student. Notes are unique per kelas/semester; re-submitting returns the existing notes
 // @Tags Catatan Semester
 // @Accept json
 // @Produce json
 // @Param siswa_id path int true "Student ID"
 // @Param request body requests.CreateCatatanSemesterRequest true "Semester notes data"
+// @Success 200 {object} utils.Response{data=responses.CatatanSemesterResponse} "Notes for this semester already exist"
 // @Success 201 {object} utils.Response{data=responses.CatatanSemesterResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
@@ -287,12 +270,16 @@ func (h *NilaiHandler) CreateCatatanSemester(c *gin.Context) {
 		return
 	}
 
-	response, err := h.nilaiService.CreateCatatanSemester(uint(siswaID), req)
+	response, created, err := h.nilaiService.CreateCatatanSemester(uint(siswaID), req)
 	if err != nil {
 		utils.BadRequestResponse(c, err.Error(), nil)
 		return
 	}
 
+	if !created {
+		utils.SuccessResponse(c, "Semester notes already exist", response)
+		return
+	}
 	utils.CreatedResponse(c, "Semester notes created successfully", response)
 }
 
@@ -356,3 +343,303 @@ func (h *NilaiHandler) AddPKL(c *gin.Context) {
 
 	utils.CreatedResponse(c, "PKL added successfully", response)
 }
+
+// UpdatePKL godoc
+// @Summary Update PKL
+// @Description Update an internship record of semester notes
+// @Tags Catatan Semester
+// @Accept json
+// @Produce json
+// @Param id path int true "PKL ID"
+// @Param request body requests.UpdatePKLRequest true "PKL data"
+// @Success 200 {object} utils.Response{data=responses.PKLResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /pkl/{id} [put]
+func (h *NilaiHandler) UpdatePKL(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid PKL ID", nil)
+		return
+	}
+
+	var req requests.UpdatePKLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.nilaiService.UpdatePKL(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "PKL updated successfully", response)
+}
+
+// DeletePKL godoc
+// @Summary Delete PKL
+// @Description Delete an internship record of semester notes
+// @Tags Catatan Semester
+// @Param id path int true "PKL ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /pkl/{id} [delete]
+func (h *NilaiHandler) DeletePKL(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid PKL ID", nil)
+		return
+	}
+
+	if err := h.nilaiService.DeletePKL(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// AddEkstrakurikuler godoc
+// @Summary Add extracurricular to semester notes
+// @Description Add extracurricular activity to semester notes
+// @Tags Catatan Semester
+// @Accept json
+// @Produce json
+// @Param catatan_id path int true "Semester Notes ID"
+// @Param request body requests.CreateEkstrakurikulerRequest true "Extracurricular data"
+// @Success 201 {object} utils.Response{data=responses.EkstrakurikulerResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /catatan-semester/{catatan_id}/ekstrakurikuler [post]
+func (h *NilaiHandler) AddEkstrakurikuler(c *gin.Context) {
+	catatanID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid semester notes ID", nil)
+		return
+	}
+
+	var req requests.CreateEkstrakurikulerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.nilaiService.AddEkstrakurikuler(uint(catatanID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Extracurricular added successfully", response)
+}
+
+// UpdateEkstrakurikuler godoc
+// @Summary Update extracurricular
+// @Description Update an extracurricular activity of semester notes
+// @Tags Catatan Semester
+// @Accept json
+// @Produce json
+// @Param id path int true "Extracurricular ID"
+// @Param request body requests.UpdateEkstrakurikulerRequest true "Extracurricular data"
+// @Success 200 {object} utils.Response{data=responses.EkstrakurikulerResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /ekstrakurikuler/{id} [put]
+func (h *NilaiHandler) UpdateEkstrakurikuler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid extracurricular ID", nil)
+		return
+	}
+
+	var req requests.UpdateEkstrakurikulerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.nilaiService.UpdateEkstrakurikuler(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Extracurricular updated successfully", response)
+}
+
+// DeleteEkstrakurikuler godoc
+// @Summary Delete extracurricular
+// @Description Delete an extracurricular activity of semester notes
+// @Tags Catatan Semester
+// @Param id path int true "Extracurricular ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /ekstrakurikuler/{id} [delete]
+func (h *NilaiHandler) DeleteEkstrakurikuler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid extracurricular ID", nil)
+		return
+	}
+
+	if err := h.nilaiService.DeleteEkstrakurikuler(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// AddPrestasiSemester godoc
+// @Summary Add achievement to semester notes
+// @Description Add achievement record to semester notes
+// @Tags Catatan Semester
+// @Accept json
+// @Produce json
+// @Param catatan_id path int true "Semester Notes ID"
+// @Param request body requests.CreatePrestasiSemesterRequest true "Achievement data"
+// @Success 201 {object} utils.Response{data=responses.PrestasiSemesterResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /catatan-semester/{catatan_id}/prestasi [post]
+func (h *NilaiHandler) AddPrestasiSemester(c *gin.Context) {
+	catatanID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid semester notes ID", nil)
+		return
+	}
+
+	var req requests.CreatePrestasiSemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.nilaiService.AddPrestasiSemester(uint(catatanID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Semester achievement added successfully", response)
+}
+
+// UpdatePrestasiSemester godoc
+// @Summary Update semester achievement
+// @Description Update an achievement record of semester notes
+// @Tags Catatan Semester
+// @Accept json
+// @Produce json
+// @Param id path int true "Semester achievement ID"
+// @Param request body requests.UpdatePrestasiSemesterRequest true "Achievement data"
+// @Success 200 {object} utils.Response{data=responses.PrestasiSemesterResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /prestasi-semester/{id} [put]
+func (h *NilaiHandler) UpdatePrestasiSemester(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid semester achievement ID", nil)
+		return
+	}
+
+	var req requests.UpdatePrestasiSemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.nilaiService.UpdatePrestasiSemester(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Semester achievement updated successfully", response)
+}
+
+// DeletePrestasiSemester godoc
+// @Summary Delete semester achievement
+// @Description Delete an achievement record of semester notes
+// @Tags Catatan Semester
+// @Param id path int true "Semester achievement ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /prestasi-semester/{id} [delete]
+func (h *NilaiHandler) DeletePrestasiSemester(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid semester achievement ID", nil)
+		return
+	}
+
+	if err := h.nilaiService.DeletePrestasiSemester(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// SetKetidakhadiran godoc
+// @Summary Set absence in semester notes
+// @Description Create or replace the absence counts of semester notes. Not allowed when the semester has an attendance summary, which keeps these counts in sync
+// @Tags Catatan Semester
+// @Accept json
+// @Produce json
+// @Param catatan_id path int true "Semester Notes ID"
+// @Param request body requests.SetKetidakhadiranRequest true "Absence data"
+// @Success 200 {object} utils.Response{data=responses.KetidakhadiranResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /catatan-semester/{catatan_id}/ketidakhadiran [put]
+func (h *NilaiHandler) SetKetidakhadiran(c *gin.Context) {
+	catatanID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid semester notes ID", nil)
+		return
+	}
+
+	var req requests.SetKetidakhadiranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.nilaiService.SetKetidakhadiran(uint(catatanID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Absence saved successfully", response)
+}
+
+// DeleteKetidakhadiran godoc
+// @Summary Delete absence from semester notes
+// @Description Remove the absence counts of semester notes
+// @Tags Catatan Semester
+// @Param catatan_id path int true "Semester Notes ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /catatan-semester/{catatan_id}/ketidakhadiran [delete]
+func (h *NilaiHandler) DeleteKetidakhadiran(c *gin.Context) {
+	catatanID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid semester notes ID", nil)
+		return
+	}
+
+	if err := h.nilaiService.DeleteKetidakhadiran(uint(catatanID)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// NilaiSikapHandler handles attitude grade endpoints
+type NilaiSikapHandler struct {
+	service *services.NilaiSikapService
+}
+
+func NewNilaiSikapHandler(service *services.NilaiSikapService) *NilaiSikapHandler {
+	return &NilaiSikapHandler{service: service}
+}
+
+// CreateOrUpdate godoc
+// @Summary Create or update attitude grade
+// @Description Create or update a student's spiritual and social attitude descriptions for a semester
+// @Tags Nilai Sikap
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body requests.CreateNilaiSikapRequest true "Attitude grade data"
+// @Success 200 {object} utils.Response{data=responses.NilaiSikapResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/nilai-sikap [post]
+func (h *NilaiSikapHandler) CreateOrUpdate(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.CreateNilaiSikapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.CreateOrUpdate(uint(siswaID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Attitude grade saved successfully", response)
+}
+
+// GetBySiswaID godoc
+// @Summary Get attitude grades
+// @Description Get all attitude grades of a student
+// @Tags Nilai Sikap
+// @Produce json
+// @Param id path int true "Student ID"
+// @Success 200 {object} utils.Response{data=[]responses.NilaiSikapResponse}
+// @Security BearerAuth
+// @Router /siswa/{id}/nilai-sikap [get]
+func (h *NilaiSikapHandler) GetBySiswaID(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	response, err := h.service.GetBySiswaID(uint(siswaID))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Attitude grades retrieved", response)
+}
+
+// GetBySemester godoc
+// @Summary Get attitude grade by semester
+// @Description Get a student's attitude grade for a grade and semester
+// @Tags Nilai Sikap
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param kelas path string true "Grade (X, XI, XII)"
+// @Param semester path int true "Semester (1 or 2)"
+// @Success 200 {object} utils.Response{data=responses.NilaiSikapResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/nilai-sikap/{kelas}/{semester} [get]
+func (h *NilaiSikapHandler) GetBySemester(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	kelas := c.Param("kelas")
+	if kelas != "X" && kelas != "XI" && kelas != "XII" {
+		utils.BadRequestResponse(c, "Invalid grade, use X, XI or XII", nil)
+		return
+	}
+
+	semester, err := strconv.ParseUint(c.Param("semester"), 10, 8)
+	if err != nil || semester < 1 || semester > 2 {
+		utils.BadRequestResponse(c, "Invalid semester, use 1 or 2", nil)
+		return
+	}
+
+	response, err := h.service.GetBySemester(uint(siswaID), kelas, uint8(semester))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Attitude grade retrieved", response)
+}
+
+// BulkCreateOrUpdate godoc
+// @Summary Bulk save attitude grades
+// @Description Create or update attitude grades for students of a class group in one request. The grade is taken from the class group
+// @Tags Nilai Sikap
+// @Accept json
+// @Produce json
+// @Param id path int true "Class Group ID"
+// @Param request body requests.BulkNilaiSikapRequest true "Attitude grades"
+// @Success 200 {object} utils.Response{data=[]responses.NilaiSikapResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/nilai-sikap [post]
+func (h *NilaiSikapHandler) BulkCreateOrUpdate(c *gin.Context) {
+	rombelID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var req requests.BulkNilaiSikapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.BulkCreateOrUpdate(uint(rombelID), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Attitude grades saved successfully", response)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/middlewares"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// PasswordHandler handles password change and reset endpoints
+type PasswordHandler struct {
+	service *services.PasswordService
+}
+
+func NewPasswordHandler(service *services.PasswordService) *PasswordHandler {
+	return &PasswordHandler{service: service}
+}
+
+// Change godoc
+// @Summary Change password
+// @Description Change the current user's password. The current password is required. Other sessions of the user are logged out; the current one stays valid.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/password [put]
+func (h *PasswordHandler) Change(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	sessionID, _ := middlewares.GetSessionIDFromContext(c)
+
+	var req requests.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.service.Change(userID, sessionID, req); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Password changed", nil)
+}
+
+// Forgot godoc
+// @Summary Request password reset
+// @Description Email a password reset link to the account with this email. The answer is the same whether or not the email is registered.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/forgot-password [post]
+func (h *PasswordHandler) Forgot(c *gin.Context) {
+	var req requests.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.service.Forgot(req); err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// Reset godoc
+// @Summary Reset password
+// @Description Set a new password with the token from a password reset email. The token works once, and every session of the user is logged out.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/reset-password [post]
+func (h *PasswordHandler) Reset(c *gin.Context) {
+	var req requests.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.service.Reset(req); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Password has been reset", nil)
+}
+
+// AdminReset godoc
+// @Summary Reset user password
+// @Description Replace a user's password with a generated temporary password, shown once in the response. The user must change it at the next login, and every session of the user is logged out.
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response{data=responses.TemporaryPasswordResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/reset-password [post]
+func (h *PasswordHandler) AdminReset(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	response, err := h.service.AdminReset(uint(id))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "User password reset", response)
+}
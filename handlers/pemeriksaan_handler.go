@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// PemeriksaanHandler handles book inspection endpoints
+type PemeriksaanHandler struct {
+	service *services.PemeriksaanService
+}
+
+func NewPemeriksaanHandler(service *services.PemeriksaanService) *PemeriksaanHandler {
+	return &PemeriksaanHandler{service: service}
+}
+
+// Create godoc
+// @Summary Create book inspection
+// @Description Record an inspection of the buku induk. The sequence number is assigned per year automatically
+// @Tags Pemeriksaan Buku
+// @Accept json
+// @Produce json
+// @Param request body requests.CreatePemeriksaanRequest true "Inspection data"
+// @Success 201 {object} utils.Response{data=responses.PemeriksaanResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /pemeriksaan-buku [post]
+func (h *PemeriksaanHandler) Create(c *gin.Context) {
+	var req requests.CreatePemeriksaanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Create(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Book inspection created successfully", response)
+}
+
+// FindAll godoc
+// @Summary Get all book inspections
+// @Description Get paginated list of book inspections, newest first
+// @Tags Pemeriksaan Buku
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param tanggal_mulai query string false "Start date (YYYY-MM-DD)"
+// @Param tanggal_selesai query string false "End date (YYYY-MM-DD)"
+// @Param tahun query int false "Year filter"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.PemeriksaanResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /pemeriksaan-buku [get]
+func (h *PemeriksaanHandler) FindAll(c *gin.Context) {
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	var filter requests.PemeriksaanFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, pageInfo, err := h.service.FindAll(pagination, filter)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Book inspections retrieved", response, pageInfo)
+}
+
+// FindByID godoc
+// @Summary Get book inspection by ID
+// @Description Get book inspection detail by ID
+// @Tags Pemeriksaan Buku
+// @Produce json
+// @Param id path int true "Inspection ID"
+// @Success 200 {object} utils.Response{data=responses.PemeriksaanResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /pemeriksaan-buku/{id} [get]
+func (h *PemeriksaanHandler) FindByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid inspection ID", nil)
+		return
+	}
+
+	response, err := h.service.FindByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Book inspection retrieved", response)
+}
+
+// Update godoc
+// @Summary Update book inspection
+// @Description Update book inspection data
+// @Tags Pemeriksaan Buku
+// @Accept json
+// @Produce json
+// @Param id path int true "Inspection ID"
+// @Param request body requests.UpdatePemeriksaanRequest true "Inspection data"
+// @Success 200 {object} utils.Response{data=responses.PemeriksaanResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /pemeriksaan-buku/{id} [put]
+func (h *PemeriksaanHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid inspection ID", nil)
+		return
+	}
+
+	var req requests.UpdatePemeriksaanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Book inspection updated successfully", response)
+}
+
+// Delete godoc
+// @Summary Delete book inspection
+// @Description Delete a book inspection
+// @Tags Pemeriksaan Buku
+// @Param id path int true "Inspection ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /pemeriksaan-buku/{id} [delete]
+func (h *PemeriksaanHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid inspection ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// PeringkatHandler handles student ranking endpoints
+type PeringkatHandler struct {
+	service *services.PeringkatService
+}
+
+func NewPeringkatHandler(service *services.PeringkatService) *PeringkatHandler {
+	return &PeringkatHandler{service: service}
+}
+
+// GetRombel godoc
+// @Summary Get class group ranking
+// @Description Rank the members of a class group by their weighted semester average, with their rank in the whole tingkat. Equal averages share a rank.
+// @Tags Peringkat
+// @Produce json
+// @Param id path int true "Class Group ID"
+// @Param semester query int true "Semester (1, 2)"
+// @Success 200 {object} utils.Response{data=responses.PeringkatResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/peringkat [get]
+func (h *PeringkatHandler) GetRombel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var req requests.PeringkatRombelRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Rombel(uint(id), req.Semester)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Class group ranking retrieved", response)
+}
+
+// GetTingkat godoc
+// @Summary Get tingkat ranking
+// @Description Rank all students of a tingkat across class groups by their weighted semester average. Equal averages share a rank.
+// @Tags Peringkat
+// @Produce json
+// @Param tingkat query string true "Tingkat (X, XI, XII)"
+// @Param semester query int true "Semester (1, 2)"
+// @Param tahun_pelajaran query string false "Academic year, defaults to the active year"
+// @Success 200 {object} utils.Response{data=responses.PeringkatResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /peringkat [get]
+func (h *PeringkatHandler) GetTingkat(c *gin.Context) {
+	var req requests.PeringkatTingkatRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Tingkat(req.Tingkat, req.Semester, req.TahunPelajaran)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Tingkat ranking retrieved", response)
+}
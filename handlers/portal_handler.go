@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/middlewares"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// PortalHandler handles student and parent portal accounts and their endpoints
+type PortalHandler struct {
+	service *services.PortalService
+}
+
+func NewPortalHandler(service *services.PortalService) *PortalHandler {
+	return &PortalHandler{service: service}
+}
+
+// CreateAccount godoc
+// @Summary Create portal account
+// @Description Create a student account linked by siswa_id, or a parent account linked to each child through its orang_tua_ids or wali_ids records. Portal accounts log in through /auth/login and can only use the /portal routes.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body requests.CreatePortalAccountRequest true "Portal account"
+// @Success 201 {object} utils.Response{data=responses.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/portal [post]
+func (h *PortalHandler) CreateAccount(c *gin.Context) {
+	var req requests.CreatePortalAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.CreateAccount(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Portal account created successfully", response)
+}
+
+// SetLinks godoc
+// @Summary Set portal account students
+// @Description Replace the students a portal account is linked to
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body requests.SetPortalLinksRequest true "Linked students"
+// @Success 200 {object} utils.Response{data=responses.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/portal [put]
+func (h *PortalHandler) SetLinks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	var req requests.SetPortalLinksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.SetLinks(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Portal account students updated", response)
+}
+
+// FindSiswa godoc
+// @Summary List portal students
+// @Description List the students linked to the logged in student or parent account
+// @Tags Portal
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]responses.PortalSiswaResponse}
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /portal/siswa [get]
+func (h *PortalHandler) FindSiswa(c *gin.Context) {
+	userID, _ := middlewares.GetUserIDFromContext(c)
+
+	response, err := h.service.Siswa(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Students retrieved", response)
+}
+
+// GetSiswa godoc
+// @Summary Get portal student profile
+// @Description Get the profile of a linked student. Student accounts do not see the monthly income of their parents and guardian.
+// @Tags Portal
+// @Produce json
+// @Param id path int true "Student ID"
+// @Success 200 {object} utils.Response{data=responses.SiswaDetailResponse}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /portal/siswa/{id} [get]
+func (h *PortalHandler) GetSiswa(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	response, err := h.service.Profil(uint(id), middlewares.GetRolesFromContext(c))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Student retrieved", response)
+}
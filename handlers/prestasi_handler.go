@@ -53,6 +53,82 @@ func (h *PrestasiHandler) Add(c *gin.Context) {
 	utils.CreatedResponse(c, "Achievement record added successfully", response)
 }
 
+// FindBySiswaID godoc
+// @Summary Get student achievements
+// @Description Get paginated achievements of a student
+// @Tags Prestasi
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param bidang query string false "Field filter"
+// @Param tingkat query string false "Level filter (Sekolah, Kecamatan, Kota, Provinsi, Nasional, Internasional)"
+// @Param tahun query int false "Year filter"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.PrestasiResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/prestasi [get]
+func (h *PrestasiHandler) FindBySiswaID(c *gin.Context) {
+	siswaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	h.list(c, uint(siswaID))
+}
+
+// FindAll godoc
+// @Summary Get all achievements
+// @Description Get paginated achievements across all students, e.g. all national-level achievements in a year
+// @Tags Prestasi
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param bidang query string false "Field filter"
+// @Param tingkat query string false "Level filter (Sekolah, Kecamatan, Kota, Provinsi, Nasional, Internasional)"
+// @Param tahun query int false "Year filter"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.PrestasiResponse}
+// @Security BearerAuth
+// @Router /prestasi [get]
+func (h *PrestasiHandler) FindAll(c *gin.Context) {
+	h.list(c, 0)
+}
+
+// Update godoc
+// @Summary Update achievement
+// @Description Update achievement
+// @Tags Prestasi
+// @Accept json
+// @Produce json
+// @Param id path int true "Achievement Record ID"
+// @Param request body requests.UpdatePrestasiRequest true "Achievement data"
+// @Success 200 {object} utils.Response{data=responses.PrestasiResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /prestasi/{id} [put]
+func (h *PrestasiHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid achievement record ID", nil)
+		return
+	}
+
+	var req requests.UpdatePrestasiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Achievement updated successfully", response)
+}
+
 // Delete godoc
 // @Summary Delete achievement
 // @Description Delete achievement record
@@ -76,3 +152,26 @@ func (h *PrestasiHandler) Delete(c *gin.Context) {
 
 	utils.NoContentResponse(c)
 }
+
+// list binds pagination and filters and writes a paginated achievement listing
+func (h *PrestasiHandler) list(c *gin.Context, siswaID uint) {
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	var filter requests.PrestasiFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, pageInfo, err := h.service.FindAll(siswaID, pagination, filter)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Achievements retrieved", response, pageInfo)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// RaporHandler handles report card (rapor) printing endpoints
+type RaporHandler struct {
+	service *services.RaporService
+}
+
+func NewRaporHandler(service *services.RaporService) *RaporHandler {
+	return &RaporHandler{service: service}
+}
+
+// GetSiswa godoc
+// @Summary Print student report card
+// @Description Render the report card (rapor) of a student for a kelas and semester as PDF
+// @Tags Rapor
+// @Produce application/pdf
+// @Param id path int true "Student ID"
+// @Param kelas query string true "Kelas (X, XI, XII)"
+// @Param semester query int true "Semester (1, 2)"
+// @Param tahun_pelajaran query string false "Academic year, defaults to the latest year with grades"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/{id}/rapor [get]
+func (h *RaporHandler) GetSiswa(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	var req requests.RaporRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	data, filename, err := h.service.GenerateSiswa(uint(id), req)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", "inline; filename="+filename)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// GetRombel godoc
+// @Summary Print class group report cards
+// @Description Render the report cards of all members of a class group as one PDF, one student per page set
+// @Tags Rapor
+// @Produce application/pdf
+// @Param id path int true "Class Group ID"
+// @Param semester query int true "Semester (1, 2)"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/rapor [get]
+func (h *RaporHandler) GetRombel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var req requests.RaporRombelRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	data, filename, err := h.service.GenerateRombel(uint(id), req)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", "inline; filename="+filename)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// RombelHandler handles class group endpoints
+type RombelHandler struct {
+	service *services.RombelService
+}
+
+func NewRombelHandler(service *services.RombelService) *RombelHandler {
+	return &RombelHandler{service: service}
+}
+
+// Create godoc
+// @Summary Create class group
+// @Description Create a new class group (rombongan belajar) for an academic year
+// @Tags Rombel
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateRombelRequest true "Class group data"
+// @Success 201 {object} utils.Response{data=responses.RombelResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel [post]
+func (h *RombelHandler) Create(c *gin.Context) {
+	var req requests.CreateRombelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Create(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Class group created successfully", response)
+}
+
+// FindAll godoc
+// @Summary Get all class groups
+// @Description Get paginated list of class groups
+// @Tags Rombel
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param search query string false "Search by name or major"
+// @Param tingkat query string false "Grade filter (X, XI, XII)"
+// @Param tahun_pelajaran query string false "Academic year filter"
+// @Param wali_kelas_id query int false "Homeroom teacher filter"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.RombelResponse}
+// @Security BearerAuth
+// @Router /rombel [get]
+func (h *RombelHandler) FindAll(c *gin.Context) {
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	var filter requests.RombelFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, pageInfo, err := h.service.FindAll(pagination, filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Class groups retrieved", response, pageInfo)
+}
+
+// FindByID godoc
+// @Summary Get class group by ID
+// @Description Get class group detail by ID
+// @Tags Rombel
+// @Produce json
+// @Param id path int true "Class Group ID"
+// @Success 200 {object} utils.Response{data=responses.RombelResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id} [get]
+func (h *RombelHandler) FindByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	response, err := h.service.FindByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Class group retrieved", response)
+}
+
+// Update godoc
+// @Summary Update class group
+// @Description Update class group data
+// @Tags Rombel
+// @Accept json
+// @Produce json
+// @Param id path int true "Class Group ID"
+// @Param request body requests.UpdateRombelRequest true "Class group data"
+// @Success 200 {object} utils.Response{data=responses.RombelResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id} [put]
+func (h *RombelHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var req requests.UpdateRombelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Class group updated successfully", response)
+}
+
+// Delete godoc
+// @Summary Delete class group
+// @Description Delete a class group and its enrollments
+// @Tags Rombel
+// @Param id path int true "Class Group ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id} [delete]
+func (h *RombelHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// GetAnggota godoc
+// @Summary Get class group members
+// @Description Get paginated list of students enrolled in a class group
+// @Tags Rombel
+// @Produce json
+// @Param id path int true "Class Group ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param search query string false "Search by name, NISN, or registration number"
+// @Success 200 {object} utils.PaginatedResponse{data=[]responses.SiswaListResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/anggota [get]
+func (h *RombelHandler) GetAnggota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var pagination requests.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.BadRequestResponse(c, "Invalid pagination parameters", err.Error())
+		return
+	}
+
+	response, pageInfo, err := h.service.GetAnggota(uint(id), pagination)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Class group members retrieved", response, pageInfo)
+}
+
+// AddAnggota godoc
+// @Summary Add class group members
+// @Description Enroll students in a class group for its academic year
+// @Tags Rombel
+// @Accept json
+// @Produce json
+// @Param id path int true "Class Group ID"
+// @Param request body requests.AddAnggotaRombelRequest true "Student IDs"
+// @Success 201 {object} utils.Response{data=responses.RombelResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/anggota [post]
+func (h *RombelHandler) AddAnggota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	var req requests.AddAnggotaRombelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.AddAnggota(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Students added to class group successfully", response)
+}
+
+// RemoveAnggota godoc
+// @Summary Remove class group member
+// @Description Remove a student from a class group
+// @Tags Rombel
+// @Param id path int true "Class Group ID"
+// @Param siswa_id path int true "Student ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /rombel/{id}/anggota/{siswa_id} [delete]
+func (h *RombelHandler) RemoveAnggota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid class group ID", nil)
+		return
+	}
+
+	siswaID, err := strconv.ParseUint(c.Param("siswa_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid student ID", nil)
+		return
+	}
+
+	if err := h.service.RemoveAnggota(uint(id), uint(siswaID)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -82,6 +83,11 @@ func (h *SiswaHandler) FindByID(c *gin.Context) {
 // @Param search query string false "Search by name, NISN, or registration number"
 // @Param sort_by query string false "Sort field"
 // @Param sort_dir query string false "Sort direction (asc/desc)"
+// @Param rombel_id query int false "Class group filter"
+// @Param status query string false "Student status (aktif, tamat, pindah, putus, semua)" default(aktif)
+// @Param kompetensi_keahlian_id query int false "Kompetensi keahlian filter"
+// @Param program_keahlian_id query int false "Program keahlian filter"
+// @Param bidang_keahlian_id query int false "Bidang keahlian filter"
 // @Success 200 {object} utils.PaginatedResponse{data=[]responses.SiswaListResponse}
 // @Security BearerAuth
 // @Router /siswa [get]
@@ -92,7 +98,13 @@ func (h *SiswaHandler) FindAll(c *gin.Context) {
 		return
 	}
 
-	response, pagination, err := h.siswaService.FindAll(req)
+	var filter requests.SiswaFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, pagination, err := h.siswaService.FindAll(req, filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(c, err.Error())
 		return
@@ -101,6 +113,36 @@ func (h *SiswaHandler) FindAll(c *gin.Context) {
 	utils.PaginatedSuccessResponse(c, "Students retrieved", response, pagination)
 }
 
+// RekapJurusan godoc
+// @Summary Count students per kompetensi keahlian
+// @Description Get the number of students per kompetensi keahlian and gender, using the same search and filters as the student list
+// @Tags Siswa
+// @Produce json
+// @Param search query string false "Search by name, NISN, or registration number"
+// @Param rombel_id query int false "Class group filter"
+// @Param status query string false "Student status (aktif, tamat, pindah, putus, semua)" default(aktif)
+// @Param kompetensi_keahlian_id query int false "Kompetensi keahlian filter"
+// @Param program_keahlian_id query int false "Program keahlian filter"
+// @Param bidang_keahlian_id query int false "Bidang keahlian filter"
+// @Success 200 {object} utils.Response{data=[]responses.RekapJurusanResponse}
+// @Security BearerAuth
+// @Router /siswa/rekap-jurusan [get]
+func (h *SiswaHandler) RekapJurusan(c *gin.Context) {
+	var filter requests.SiswaFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, err := h.siswaService.RekapJurusan(c.Query("search"), filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Student counts per kompetensi keahlian retrieved", response)
+}
+
 // Update godoc
 // @Summary Update student
 // @Description Update student information
@@ -195,3 +237,77 @@ func (h *SiswaHandler) UploadFoto(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Photo uploaded successfully", gin.H{"foto_path": fotoPath})
 }
+
+// Import godoc
+// @Summary Import students
+// @Description Create students with address, parents and guardian from a CSV or XLSX file based on the import template. Every row is validated like a single create and the errors are reported per row. With dry_run nothing is saved; otherwise students are only saved when every row is valid.
+// @Tags Siswa
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Import file (CSV or XLSX)"
+// @Param dry_run query bool false "Only validate the file"
+// @Success 200 {object} utils.Response{data=responses.ImportSiswaResponse}
+// @Success 201 {object} utils.Response{data=responses.ImportSiswaResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/import [post]
+func (h *SiswaHandler) Import(c *gin.Context) {
+	var req requests.ImportSiswaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequestResponse(c, "No file uploaded", err.Error())
+		return
+	}
+
+	report, err := h.siswaService.Import(file, req.DryRun)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	switch {
+	case report.DryRun:
+		utils.SuccessResponse(c, "Import file validated", report)
+	case report.BarisGagal > 0:
+		utils.BadRequestResponse(c, "Import failed, no student was saved", report)
+	default:
+		utils.CreatedResponse(c, "Students imported successfully", report)
+	}
+}
+
+// ImportTemplate godoc
+// @Summary Download student import template
+// @Description Download the blank template for the student import with its column guide
+// @Tags Siswa
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param format query string false "Output format (xlsx, csv)" default(xlsx)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /siswa/import/template [get]
+func (h *SiswaHandler) ImportTemplate(c *gin.Context) {
+	var req requests.ImportTemplateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	data, filename, err := h.siswaService.ImportTemplate(req.Format)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	if req.Format == "csv" {
+		contentType = "text/csv"
+	}
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// SkemaPenilaianHandler handles grading scheme endpoints
+type SkemaPenilaianHandler struct {
+	service *services.SkemaPenilaianService
+}
+
+func NewSkemaPenilaianHandler(service *services.SkemaPenilaianService) *SkemaPenilaianHandler {
+	return &SkemaPenilaianHandler{service: service}
+}
+
+// Create godoc
+// @Summary Create grading scheme
+// @Description Define the KKM and predikat boundaries of an academic year, either as the year's default or for one subject. Boundaries are derived from the KKM when omitted
+// @Tags Skema Penilaian
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateSkemaPenilaianRequest true "Grading scheme data"
+// @Success 201 {object} utils.Response{data=responses.SkemaPenilaianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /skema-penilaian [post]
+func (h *SkemaPenilaianHandler) Create(c *gin.Context) {
+	var req requests.CreateSkemaPenilaianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Create(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Grading scheme created successfully", response)
+}
+
+// FindAll godoc
+// @Summary Get grading schemes
+// @Description Get grading schemes, optionally filtered by academic year and subject
+// @Tags Skema Penilaian
+// @Produce json
+// @Param tahun_pelajaran query string false "Filter by academic year"
+// @Param mata_pelajaran_id query int false "Filter by subject ID"
+// @Success 200 {object} utils.Response{data=[]responses.SkemaPenilaianResponse}
+// @Security BearerAuth
+// @Router /skema-penilaian [get]
+func (h *SkemaPenilaianHandler) FindAll(c *gin.Context) {
+	var filter requests.SkemaPenilaianFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, err := h.service.FindAll(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Grading schemes retrieved", response)
+}
+
+// FindByID godoc
+// @Summary Get grading scheme by ID
+// @Description Get grading scheme details
+// @Tags Skema Penilaian
+// @Produce json
+// @Param id path int true "Grading scheme ID"
+// @Success 200 {object} utils.Response{data=responses.SkemaPenilaianResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /skema-penilaian/{id} [get]
+func (h *SkemaPenilaianHandler) FindByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid grading scheme ID", nil)
+		return
+	}
+
+	response, err := h.service.FindByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Grading scheme retrieved", response)
+}
+
+// Update godoc
+// @Summary Update grading scheme
+// @Description Update the KKM and predikat boundaries of a grading scheme. Existing grades are not changed; run hitung-ulang to recompute their predikat
+// @Tags Skema Penilaian
+// @Accept json
+// @Produce json
+// @Param id path int true "Grading scheme ID"
+// @Param request body requests.UpdateSkemaPenilaianRequest true "Grading scheme data"
+// @Success 200 {object} utils.Response{data=responses.SkemaPenilaianResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /skema-penilaian/{id} [put]
+func (h *SkemaPenilaianHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid grading scheme ID", nil)
+		return
+	}
+
+	var req requests.UpdateSkemaPenilaianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Grading scheme updated successfully", response)
+}
+
+// Delete godoc
+// @Summary Delete grading scheme
+// @Description Delete a grading scheme
+// @Tags Skema Penilaian
+// @Param id path int true "Grading scheme ID"
+// @Success 204
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /skema-penilaian/{id} [delete]
+func (h *SkemaPenilaianHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid grading scheme ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// HitungUlang godoc
+// @Summary Recompute predikat
+// @Description Recompute the predikat of all semester grades covered by a grading scheme in its academic year
+// @Tags Skema Penilaian
+// @Produce json
+// @Param id path int true "Grading scheme ID"
+// @Success 200 {object} utils.Response{data=responses.HitungUlangPredikatResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /skema-penilaian/{id}/hitung-ulang [post]
+func (h *SkemaPenilaianHandler) HitungUlang(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid grading scheme ID", nil)
+		return
+	}
+
+	response, err := h.service.HitungUlang(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Predikat recomputed successfully", response)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// StatistikHandler handles the dashboard statistics endpoint
+type StatistikHandler struct {
+	service *services.StatistikService
+}
+
+func NewStatistikHandler(service *services.StatistikService) *StatistikHandler {
+	return &StatistikHandler{service: service}
+}
+
+// Get godoc
+// @Summary Get dashboard statistics
+// @Description Summary counts for the dashboard: active students per tingkat (class groups of the active academic year), gender, religion and kompetensi keahlian, admissions and leavers per year, average attendance per kelas and semester, and achievements per level. Results are cached for one minute.
+// @Tags Statistik
+// @Produce json
+// @Success 200 {object} utils.Response{data=responses.StatistikResponse}
+// @Failure 500 {object} utils.Response
+// @Security BearerAuth
+// @Router /statistik [get]
+func (h *StatistikHandler) Get(c *gin.Context) {
+	response, err := h.service.Get()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Statistics retrieved", response)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// TahunPelajaranHandler handles academic year endpoints
+type TahunPelajaranHandler struct {
+	service *services.TahunPelajaranService
+}
+
+func NewTahunPelajaranHandler(service *services.TahunPelajaranService) *TahunPelajaranHandler {
+	return &TahunPelajaranHandler{service: service}
+}
+
+// Create godoc
+// @Summary Create academic year
+// @Description Register a new academic year
+// @Tags Tahun Pelajaran
+// @Accept json
+// @Produce json
+// @Param request body requests.CreateTahunPelajaranRequest true "Academic year data"
+// @Success 201 {object} utils.Response{data=responses.TahunPelajaranResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /tahun-pelajaran [post]
+func (h *TahunPelajaranHandler) Create(c *gin.Context) {
+	var req requests.CreateTahunPelajaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Create(req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.CreatedResponse(c, "Academic year created successfully", response)
+}
+
+// FindAll godoc
+// @Summary Get all academic years
+// @Description Get list of academic years, newest first
+// @Tags Tahun Pelajaran
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]responses.TahunPelajaranResponse}
+// @Security BearerAuth
+// @Router /tahun-pelajaran [get]
+func (h *TahunPelajaranHandler) FindAll(c *gin.Context) {
+	response, err := h.service.FindAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Academic years retrieved", response)
+}
+
+// FindActive godoc
+// @Summary Get active academic year
+// @Description Get the currently active academic year and semester
+// @Tags Tahun Pelajaran
+// @Produce json
+// @Success 200 {object} utils.Response{data=responses.TahunPelajaranResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /tahun-pelajaran/aktif [get]
+func (h *TahunPelajaranHandler) FindActive(c *gin.Context) {
+	response, err := h.service.FindActive()
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Active academic year retrieved", response)
+}
+
+// FindByID godoc
+// @Summary Get academic year by ID
+// @Description Get academic year detail by ID
+// @Tags Tahun Pelajaran
+// @Produce json
+// @Param id path int true "Academic Year ID"
+// @Success 200 {object} utils.Response{data=responses.TahunPelajaranResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /tahun-pelajaran/{id} [get]
+func (h *TahunPelajaranHandler) FindByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid academic year ID", nil)
+		return
+	}
+
+	response, err := h.service.FindByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Academic year retrieved", response)
+}
+
+// Update godoc
+// @Summary Update academic year
+// @Description Update academic year data. The label can only be changed while no record uses it
+// @Tags Tahun Pelajaran
+// @Accept json
+// @Produce json
+// @Param id path int true "Academic Year ID"
+// @Param request body requests.UpdateTahunPelajaranRequest true "Academic year data"
+// @Success 200 {object} utils.Response{data=responses.TahunPelajaranResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /tahun-pelajaran/{id} [put]
+func (h *TahunPelajaranHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid academic year ID", nil)
+		return
+	}
+
+	var req requests.UpdateTahunPelajaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Update(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Academic year updated successfully", response)
+}
+
+// Activate godoc
+// @Summary Activate academic year
+// @Description Set the academic year and semester as the active period
+// @Tags Tahun Pelajaran
+// @Accept json
+// @Produce json
+// @Param id path int true "Academic Year ID"
+// @Param request body requests.ActivateTahunPelajaranRequest true "Active semester"
+// @Success 200 {object} utils.Response{data=responses.TahunPelajaranResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /tahun-pelajaran/{id}/aktifkan [post]
+func (h *TahunPelajaranHandler) Activate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid academic year ID", nil)
+		return
+	}
+
+	var req requests.ActivateTahunPelajaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Activate(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Academic year activated successfully", response)
+}
+
+// Delete godoc
+// @Summary Delete academic year
+// @Description Delete an academic year that is not active and not in use
+// @Tags Tahun Pelajaran
+// @Param id path int true "Academic Year ID"
+// @Success 204
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /tahun-pelajaran/{id} [delete]
+func (h *TahunPelajaranHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid academic year ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
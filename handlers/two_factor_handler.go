@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/middlewares"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// TwoFactorHandler handles two-factor authentication endpoints
+type TwoFactorHandler struct {
+	service *services.TwoFactorService
+}
+
+func NewTwoFactorHandler(service *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{service: service}
+}
+
+// Status godoc
+// @Summary Get two-factor status
+// @Description Get whether the current user has two-factor authentication, whether their role requires it and how many recovery codes are left
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.Response{data=responses.TwoFactorStatusResponse}
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/2fa [get]
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	response, err := h.service.Status(userID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Two-factor status retrieved", response)
+}
+
+// Setup godoc
+// @Summary Start two-factor setup
+// @Description Generate a new TOTP secret. Show otpauth_uri as a QR code for the authenticator app, then confirm with POST /auth/2fa/enable.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.Response{data=responses.TwoFactorSetupResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	response, err := h.service.Setup(userID)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Two-factor setup started", response)
+}
+
+// Enable godoc
+// @Summary Enable two-factor authentication
+// @Description Confirm the setup with a code from the authenticator app. Returns recovery codes, shown only once. The user's other sessions are signed out.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} utils.Response{data=responses.RecoveryCodesResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	sessionID, _ := middlewares.GetSessionIDFromContext(c)
+
+	var req requests.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.Enable(userID, sessionID, req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Two-factor authentication enabled", response)
+}
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication with the password and an authenticator or recovery code. Not allowed when the user's role requires it.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req requests.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.service.Disable(userID, req); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace the recovery codes after checking an authenticator or recovery code. The old codes stop working.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body requests.TwoFactorCodeRequest true "Authenticator or recovery code"
+// @Success 200 {object} utils.Response{data=responses.RecoveryCodesResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Security BearerAuth
+// @Router /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := middlewares.GetUserIDFromContext(c)
+	if !exists {
+		utils.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req requests.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.RegenerateRecoveryCodes(userID, req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Recovery codes regenerated", response)
+}
+
+// AdminReset godoc
+// @Summary Reset user two-factor authentication
+// @Description Turn off two-factor authentication of a user who lost their authenticator and recovery codes and sign them out of every session. If their role requires it, they set it up again after logging in.
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/2fa [delete]
+func (h *TwoFactorHandler) AdminReset(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	if err := h.service.Reset(uint(id)); err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "User two-factor authentication reset", nil)
+}
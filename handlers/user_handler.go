@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// UserHandler handles admin user and role management endpoints
+type UserHandler struct {
+	service *services.UserService
+}
+
+func NewUserHandler(service *services.UserService) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+// FindAll godoc
+// @Summary List users
+// @Description List admin users with their roles
+// @Tags Users
+// @Produce json
+// @Param search query string false "Search by username or email"
+// @Param role query string false "Filter by role (super_admin, operator, wali_kelas, guru_mapel, kepala_sekolah)"
+// @Success 200 {object} utils.Response{data=[]responses.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users [get]
+func (h *UserHandler) FindAll(c *gin.Context) {
+	var filter requests.UserFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BadRequestResponse(c, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	response, err := h.service.FindAll(filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Users retrieved", response)
+}
+
+// FindByID godoc
+// @Summary Get user by ID
+// @Description Get an admin user with their roles
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response{data=responses.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id} [get]
+func (h *UserHandler) FindByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	response, err := h.service.FindByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "User retrieved", response)
+}
+
+// SetRoles godoc
+// @Summary Assign user roles
+// @Description Replace the roles of an admin user. The new roles apply from the user's next token refresh. The last active super admin cannot lose the super_admin role.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body requests.SetUserRolesRequest true "Roles"
+// @Success 200 {object} utils.Response{data=responses.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/roles [put]
+func (h *UserHandler) SetRoles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	var req requests.SetUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.SetRoles(uint(id), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "User roles updated", response)
+}
+
+// RevokeSessions godoc
+// @Summary Revoke user sessions
+// @Description Revoke every session of a user, signing them out on all devices
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response{data=responses.RevokedSessionsResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/sessions [delete]
+func (h *UserHandler) RevokeSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", nil)
+		return
+	}
+
+	response, err := h.service.RevokeSessions(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "User sessions revoked", response)
+}
+
+// Roles godoc
+// @Summary List roles
+// @Description List every role with the permissions it grants and whether it requires two-factor authentication. Scope "wali_kelas" limits a permission to the students and class groups of the user's own rombel in the active academic year.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]responses.RoleResponse}
+// @Security BearerAuth
+// @Router /roles [get]
+func (h *UserHandler) Roles(c *gin.Context) {
+	response, err := h.service.Roles()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "Roles retrieved", response)
+}
+
+// SetRolePolicy godoc
+// @Summary Set role two-factor policy
+// @Description Set whether a staff role requires two-factor authentication. Users of the role without it can only use logout, password change and the /auth/2fa routes until they set it up.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param role path string true "Role (super_admin, operator, wali_kelas, guru_mapel, kepala_sekolah)"
+// @Param request body requests.SetRolePolicyRequest true "Policy"
+// @Success 200 {object} utils.Response{data=responses.RoleResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Security BearerAuth
+// @Router /roles/{role}/policy [put]
+func (h *UserHandler) SetRolePolicy(c *gin.Context) {
+	var req requests.SetRolePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	response, err := h.service.SetRolePolicy(c.Param("role"), req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Role policy updated", response)
+}
@@ -1,14 +1,18 @@
 package middlewares
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/repositories"
 	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
 )
 
-// AuthMiddleware validates JWT token
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates JWT token and checks that its session has not been
+// revoked and its user is still active
+func AuthMiddleware(sessionRepo *repositories.SessionRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -35,14 +39,68 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Check session
+		user, err := sessionRepo.FindActiveUser(claims.SessionID, claims.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				utils.UnauthorizedResponse(c, "Session has been revoked or the account is deactivated")
+			} else {
+				utils.InternalServerErrorResponse(c, "Failed to verify session")
+			}
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("roles", claims.Roles)
+		c.Set("session_id", claims.SessionID)
+		c.Set("must_change_password", user.MustChangePassword)
+		c.Set("two_factor_enabled", user.TOTPEnabled)
 
 		c.Next()
 	}
 }
 
+// RequirePasswordChanged blocks users who must change their password first.
+// Only logout and the password change itself stay open to them.
+func RequirePasswordChanged() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("must_change_password") {
+			utils.ForbiddenResponse(c, "You must change your password before continuing")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireTwoFactor blocks users whose role requires two-factor authentication
+// until they have set it up. Logout, the password change and the two-factor
+// setup routes stay open to them.
+func RequireTwoFactor(twoFactorRepo *repositories.TwoFactorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("two_factor_enabled") {
+			c.Next()
+			return
+		}
+
+		required, err := twoFactorRepo.IsRequired(GetRolesFromContext(c))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to check two-factor policy")
+			c.Abort()
+			return
+		}
+		if required {
+			utils.ForbiddenResponse(c, "Your role requires two-factor authentication, set it up before continuing")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // GetUserIDFromContext gets user ID from gin context
 func GetUserIDFromContext(c *gin.Context) (uint, bool) {
 	userID, exists := c.Get("user_id")
@@ -60,3 +118,21 @@ func GetUsernameFromContext(c *gin.Context) (string, bool) {
 	}
 	return username.(string), true
 }
+
+// GetSessionIDFromContext gets the login session ID from gin context
+func GetSessionIDFromContext(c *gin.Context) (uint, bool) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		return 0, false
+	}
+	return sessionID.(uint), true
+}
+
+// GetRolesFromContext gets the user's roles from gin context
+func GetRolesFromContext(c *gin.Context) []string {
+	roles, exists := c.Get("roles")
+	if !exists {
+		return nil
+	}
+	return roles.([]string)
+}
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// RequirePortal allows only portal accounts of students and parents
+func RequirePortal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, role := range GetRolesFromContext(c) {
+			if utils.IsPortalRole(role) {
+				c.Next()
+				return
+			}
+		}
+
+		utils.ForbiddenResponse(c, "Only student and parent accounts can use the portal")
+		c.Abort()
+	}
+}
+
+// PortalScope limits portal accounts to the students linked to them
+type PortalScope struct {
+	userRepo *repositories.UserRepository
+}
+
+// NewPortalScope creates a new PortalScope
+func NewPortalScope(userRepo *repositories.UserRepository) *PortalScope {
+	return &PortalScope{userRepo: userRepo}
+}
+
+// Siswa checks that the student ID in a path parameter is linked to the account
+func (s *PortalScope) Siswa(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid student ID", nil)
+			c.Abort()
+			return
+		}
+
+		userID, _ := GetUserIDFromContext(c)
+		linked, err := s.userRepo.IsPortalSiswa(userID, uint(id))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			c.Abort()
+			return
+		}
+		if !linked {
+			utils.ForbiddenResponse(c, "You can only view your own or your children's data")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,128 @@
+package middlewares
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// ScopeChecker reports whether a user may act on the resource of a request
+// when a permission is only granted within the wali kelas scope
+type ScopeChecker func(c *gin.Context, userID uint) (bool, error)
+
+// RequirePermission allows the request when one of the user's roles grants the
+// permission. A permission limited to the wali kelas scope must also pass the
+// scope checkers; routes without a checker are denied to such roles.
+func RequirePermission(permission string, scope ...ScopeChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch utils.PermissionScope(GetRolesFromContext(c), permission) {
+		case utils.ScopeAll:
+			c.Next()
+			return
+		case utils.ScopeWaliKelas:
+			if len(scope) == 0 {
+				break
+			}
+			userID, _ := GetUserIDFromContext(c)
+			for _, check := range scope {
+				allowed, err := check(c, userID)
+				if err != nil {
+					utils.InternalServerErrorResponse(c, err.Error())
+					c.Abort()
+					return
+				}
+				if !allowed {
+					utils.ForbiddenResponse(c, "You can only manage students of your own class group")
+					c.Abort()
+					return
+				}
+			}
+			c.Next()
+			return
+		}
+
+		utils.ForbiddenResponse(c, "You do not have permission to perform this action")
+		c.Abort()
+	}
+}
+
+// WaliKelasScope builds scope checkers limiting a wali kelas to the students of
+// their class group in the active academic year
+type WaliKelasScope struct {
+	rombelRepo *repositories.RombelRepository
+}
+
+// NewWaliKelasScope creates a new WaliKelasScope
+func NewWaliKelasScope(rombelRepo *repositories.RombelRepository) *WaliKelasScope {
+	return &WaliKelasScope{rombelRepo: rombelRepo}
+}
+
+// Siswa checks the student ID in a path parameter
+func (s *WaliKelasScope) Siswa(param string) ScopeChecker {
+	return func(c *gin.Context, userID uint) (bool, error) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			return false, nil
+		}
+		return s.rombelRepo.IsWaliKelasSiswa(userID, uint(id))
+	}
+}
+
+// Rombel checks the class group ID in a path parameter
+func (s *WaliKelasScope) Rombel(param string) ScopeChecker {
+	return func(c *gin.Context, userID uint) (bool, error) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			return false, nil
+		}
+		return s.rombelRepo.IsWaliKelasRombel(userID, uint(id))
+	}
+}
+
+// Catatan checks the semester note ID in a path parameter
+func (s *WaliKelasScope) Catatan(param string) ScopeChecker {
+	return func(c *gin.Context, userID uint) (bool, error) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			return false, nil
+		}
+		return s.rombelRepo.IsWaliKelasCatatan(userID, uint(id))
+	}
+}
+
+// Record checks the ID in a path parameter of a record owned by a student
+// through its siswa_id, such as orang tua or prestasi
+func (s *WaliKelasScope) Record(param string, model interface{}) ScopeChecker {
+	return func(c *gin.Context, userID uint) (bool, error) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			return false, nil
+		}
+		return s.rombelRepo.IsWaliKelasRecord(userID, model, uint(id))
+	}
+}
+
+// CatatanRecord checks the ID in a path parameter of a record attached to a
+// semester note, such as PKL or ekstrakurikuler
+func (s *WaliKelasScope) CatatanRecord(param string, model interface{}) ScopeChecker {
+	return func(c *gin.Context, userID uint) (bool, error) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			return false, nil
+		}
+		return s.rombelRepo.IsWaliKelasCatatanRecord(userID, model, uint(id))
+	}
+}
+
+// RiwayatPenyakit checks the illness record ID in a path parameter
+func (s *WaliKelasScope) RiwayatPenyakit(param string) ScopeChecker {
+	return func(c *gin.Context, userID uint) (bool, error) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			return false, nil
+		}
+		return s.rombelRepo.IsWaliKelasRiwayatPenyakit(userID, uint(id))
+	}
+}
@@ -6,15 +6,32 @@ import (
 	"gorm.io/gorm"
 )
 
-// User model for admin authentication
+// User model for admin authentication. MustChangePassword limits the user to
+// changing their password; it is set for the seeded admin and after an admin reset.
+// TOTPSecret is kept while enrolment is pending and TOTPEnabled becomes true once
+// a first code is confirmed; TOTPLastCounter stops a code from being used twice.
+// FailedLoginCount counts failed logins since the last successful one and
+// LockedUntil is set once it reaches the lockout threshold.
 type User struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	Username     string    `gorm:"uniqueIndex;size:50;not null" json:"username"`
-	Email        string    `gorm:"uniqueIndex;size:100;not null" json:"email"`
-	PasswordHash string    `gorm:"size:255;not null" json:"-"`
-	IsActive     bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	Username           string     `gorm:"uniqueIndex;size:50;not null" json:"username"`
+	Email              string     `gorm:"uniqueIndex;size:100;not null" json:"email"`
+	PasswordHash       string     `gorm:"size:255;not null" json:"-"`
+	IsActive           bool       `gorm:"default:true" json:"is_active"`
+	MustChangePassword bool       `gorm:"default:false" json:"must_change_password"`
+	PasswordChangedAt  *time.Time `json:"password_changed_at"`
+	TOTPSecret         string     `gorm:"column:totp_secret;size:64" json:"-"`
+	TOTPEnabled        bool       `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`
+	TOTPLastCounter    int64      `gorm:"column:totp_last_counter;default:0" json:"-"`
+	FailedLoginCount   int        `gorm:"default:0" json:"failed_login_count"`
+	LastFailedLoginAt  *time.Time `json:"-"`
+	LockedUntil        *time.Time `json:"locked_until"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Relations
+	Roles       []UserRole   `gorm:"foreignKey:UserID" json:"roles,omitempty"`
+	PortalLinks []PortalLink `gorm:"foreignKey:UserID" json:"portal_links,omitempty"`
 }
 
 // TableName returns the table name for User
@@ -22,27 +39,167 @@ func (User) TableName() string {
 	return "users"
 }
 
+// Roles of admin users
+const (
+	RoleSuperAdmin    = "super_admin"
+	RoleOperator      = "operator"
+	RoleWaliKelas     = "wali_kelas"
+	RoleGuruMapel     = "guru_mapel"
+	RoleKepalaSekolah = "kepala_sekolah"
+
+	// Portal accounts of students and their parents or guardians
+	RoleSiswa    = "siswa"
+	RoleOrangTua = "orang_tua"
+)
+
+// UserRole model for the roles assigned to a user
+type UserRole struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_role" json:"user_id"`
+	Role      string    `gorm:"type:enum('super_admin','operator','wali_kelas','guru_mapel','kepala_sekolah','siswa','orang_tua');not null;uniqueIndex:idx_user_role" json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for UserRole
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+// PortalLink model for a student a portal account may read. Parent accounts
+// are linked through the OrangTua or Wali record of each child.
+type PortalLink struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_portal_link" json:"user_id"`
+	SiswaID    uint      `gorm:"not null;uniqueIndex:idx_portal_link" json:"siswa_id"`
+	OrangTuaID *uint     `gorm:"index" json:"orang_tua_id"`
+	WaliID     *uint     `gorm:"index" json:"wali_id"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relations
+	Siswa    *Siswa    `gorm:"foreignKey:SiswaID" json:"siswa,omitempty"`
+	OrangTua *OrangTua `gorm:"foreignKey:OrangTuaID" json:"orang_tua,omitempty"`
+	Wali     *Wali     `gorm:"foreignKey:WaliID" json:"wali,omitempty"`
+}
+
+// TableName returns the table name for PortalLink
+func (PortalLink) TableName() string {
+	return "portal_links"
+}
+
+// UserSession model for a login session. The refresh token is stored hashed and
+// rotated on every refresh; the previous hash is kept to detect a reused token.
+type UserSession struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	UserID            uint       `gorm:"not null;index" json:"user_id"`
+	RefreshTokenHash  string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	PreviousTokenHash string     `gorm:"size:64;index" json:"-"`
+	UserAgent         string     `gorm:"size:255" json:"user_agent"`
+	IPAddress         string     `gorm:"size:45" json:"ip_address"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	LastUsedAt        time.Time  `json:"last_used_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for UserSession
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// PasswordReset model for a password reset token sent by email. Only the hash
+// of the token is stored and it can be used once.
+type PasswordReset struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for PasswordReset
+func (PasswordReset) TableName() string {
+	return "password_resets"
+}
+
+// RecoveryCode model for a one-time backup code of two-factor authentication,
+// stored hashed
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for RecoveryCode
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// RolePolicy model for the security policy of a role. Users with a role that
+// requires two-factor authentication must enrol before using the API.
+type RolePolicy struct {
+	Role             string    `gorm:"primaryKey;size:20" json:"role"`
+	RequireTwoFactor bool      `gorm:"default:false" json:"require_two_factor"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for RolePolicy
+func (RolePolicy) TableName() string {
+	return "role_policies"
+}
+
+// Reasons a login attempt failed
+const (
+	LoginReasonUnknownUser   = "unknown_user"
+	LoginReasonDeactivated   = "deactivated"
+	LoginReasonWrongPassword = "wrong_password"
+	LoginReasonWrongCode     = "wrong_two_factor_code"
+	LoginReasonBlocked       = "blocked"
+)
+
+// LoginAttempt model for the login history. UserID is empty when the username
+// does not exist; Reason says why a failed attempt was rejected.
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    *uint     `gorm:"index" json:"user_id"`
+	Username  string    `gorm:"size:50;not null" json:"username"`
+	IPAddress string    `gorm:"size:45;not null" json:"ip_address"`
+	UserAgent string    `gorm:"size:255" json:"user_agent"`
+	Success   bool      `gorm:"not null" json:"success"`
+	Reason    string    `gorm:"size:30;not null;default:''" json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for LoginAttempt
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
 // Siswa model for student data
 type Siswa struct {
-	ID              uint           `gorm:"primaryKey" json:"id"`
-	NoInduk         string         `gorm:"uniqueIndex;size:20;not null" json:"no_induk"`
-	NISN            string         `gorm:"uniqueIndex;size:20;not null" json:"nisn"`
-	NamaLengkap     string         `gorm:"size:100;not null" json:"nama_lengkap"`
-	NamaPanggilan   string         `gorm:"size:50" json:"nama_panggilan"`
-	JenisKelamin    string         `gorm:"type:enum('L','P');not null" json:"jenis_kelamin"`
-	TempatLahir     string         `gorm:"size:100;not null" json:"tempat_lahir"`
-	TanggalLahir    time.Time      `gorm:"type:date;not null" json:"tanggal_lahir"`
-	Agama           string         `gorm:"size:20;not null" json:"agama"`
-	AnakKe          uint           `gorm:"default:1" json:"anak_ke"`
-	JumlahSaudara   uint           `gorm:"default:0" json:"jumlah_saudara"`
-	Kewarganegaraan string         `gorm:"size:50;default:'Indonesia'" json:"kewarganegaraan"`
-	BahasaRumah     string         `gorm:"size:50;default:'Indonesia'" json:"bahasa_rumah"`
-	FotoPath        string         `gorm:"size:255" json:"foto_path"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                   uint           `gorm:"primaryKey" json:"id"`
+	NoInduk              string         `gorm:"uniqueIndex;size:20;not null" json:"no_induk"`
+	NISN                 string         `gorm:"uniqueIndex;size:20;not null" json:"nisn"`
+	NamaLengkap          string         `gorm:"size:100;not null" json:"nama_lengkap"`
+	NamaPanggilan        string         `gorm:"size:50" json:"nama_panggilan"`
+	JenisKelamin         string         `gorm:"type:enum('L','P');not null" json:"jenis_kelamin"`
+	TempatLahir          string         `gorm:"size:100;not null" json:"tempat_lahir"`
+	TanggalLahir         time.Time      `gorm:"type:date;not null" json:"tanggal_lahir"`
+	Agama                string         `gorm:"size:20;not null" json:"agama"`
+	AnakKe               uint           `gorm:"default:1" json:"anak_ke"`
+	JumlahSaudara        uint           `gorm:"default:0" json:"jumlah_saudara"`
+	Kewarganegaraan      string         `gorm:"size:50;default:'Indonesia'" json:"kewarganegaraan"`
+	BahasaRumah          string         `gorm:"size:50;default:'Indonesia'" json:"bahasa_rumah"`
+	FotoPath             string         `gorm:"size:255" json:"foto_path"`
+	KompetensiKeahlianID *uint          `gorm:"index" json:"kompetensi_keahlian_id"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
+	KompetensiKeahlian   *KompetensiKeahlian    `gorm:"foreignKey:KompetensiKeahlianID" json:"kompetensi_keahlian,omitempty"`
 	Alamat               *AlamatSiswa           `gorm:"foreignKey:SiswaID" json:"alamat,omitempty"`
 	OrangTua             []OrangTua             `gorm:"foreignKey:SiswaID" json:"orang_tua,omitempty"`
 	Wali                 *Wali                  `gorm:"foreignKey:SiswaID" json:"wali,omitempty"`
@@ -57,6 +214,7 @@ type Siswa struct {
 	CatatanAkhirSemester []CatatanAkhirSemester `gorm:"foreignKey:SiswaID" json:"catatan_akhir_semester,omitempty"`
 	NilaiIjazah          []NilaiIjazah          `gorm:"foreignKey:SiswaID" json:"nilai_ijazah,omitempty"`
 	MeninggalkanSekolah  *MeninggalkanSekolah   `gorm:"foreignKey:SiswaID" json:"meninggalkan_sekolah,omitempty"`
+	KenaikanKelas        []KenaikanKelas        `gorm:"foreignKey:SiswaID" json:"kenaikan_kelas,omitempty"`
 }
 
 // TableName returns the table name for Siswa
@@ -192,6 +350,9 @@ type Kepribadian struct {
 	Aspek          string `gorm:"size:100;not null" json:"aspek"`
 	Nilai          string `gorm:"type:enum('Baik','Cukup','Kurang');not null" json:"nilai"`
 	TahunPelajaran string `gorm:"size:20" json:"tahun_pelajaran"`
+
+	// Relations
+	Siswa *Siswa `gorm:"foreignKey:SiswaID" json:"siswa,omitempty"`
 }
 
 // TableName returns the table name for Kepribadian
@@ -207,6 +368,9 @@ type Prestasi struct {
 	Keterangan string `gorm:"type:text" json:"keterangan"`
 	Tahun      uint   `json:"tahun"`
 	Tingkat    string `gorm:"type:enum('Sekolah','Kecamatan','Kota','Provinsi','Nasional','Internasional')" json:"tingkat"`
+
+	// Relations
+	Siswa *Siswa `gorm:"foreignKey:SiswaID" json:"siswa,omitempty"`
 }
 
 // TableName returns the table name for Prestasi
@@ -221,6 +385,9 @@ type Beasiswa struct {
 	TahunPelajaran string `gorm:"size:20;not null" json:"tahun_pelajaran"`
 	Pemberi        string `gorm:"size:100;not null" json:"pemberi"`
 	Keterangan     string `gorm:"type:text" json:"keterangan"`
+
+	// Relations
+	Siswa *Siswa `gorm:"foreignKey:SiswaID" json:"siswa,omitempty"`
 }
 
 // TableName returns the table name for Beasiswa
@@ -425,3 +592,215 @@ type PemeriksaanBuku struct {
 func (PemeriksaanBuku) TableName() string {
 	return "pemeriksaan_buku"
 }
+
+// Rombel model for class groups (rombongan belajar)
+type Rombel struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Nama           string    `gorm:"size:50;not null" json:"nama"`
+	Tingkat        string    `gorm:"type:enum('X','XI','XII');not null" json:"tingkat"`
+	Jurusan        string    `gorm:"size:100" json:"jurusan"`
+	TahunPelajaran string    `gorm:"size:20;not null;index" json:"tahun_pelajaran"`
+	WaliKelasID    *uint     `gorm:"index" json:"wali_kelas_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Relations
+	WaliKelas *User           `gorm:"foreignKey:WaliKelasID" json:"wali_kelas,omitempty"`
+	Anggota   []AnggotaRombel `gorm:"foreignKey:RombelID" json:"anggota,omitempty"`
+}
+
+// TableName returns the table name for Rombel
+func (Rombel) TableName() string {
+	return "rombel"
+}
+
+// AnggotaRombel model for student enrollment in a class group per academic year
+type AnggotaRombel struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	RombelID       uint      `gorm:"not null;index" json:"rombel_id"`
+	SiswaID        uint      `gorm:"not null;uniqueIndex:idx_anggota_siswa_tahun" json:"siswa_id"`
+	TahunPelajaran string    `gorm:"size:20;not null;uniqueIndex:idx_anggota_siswa_tahun" json:"tahun_pelajaran"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relations
+	Rombel *Rombel `gorm:"foreignKey:RombelID" json:"rombel,omitempty"`
+	Siswa  *Siswa  `gorm:"foreignKey:SiswaID" json:"siswa,omitempty"`
+}
+
+// TableName returns the table name for AnggotaRombel
+func (AnggotaRombel) TableName() string {
+	return "anggota_rombel"
+}
+
+// TahunPelajaran model for academic year master data
+type TahunPelajaran struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Label          string    `gorm:"uniqueIndex;size:20;not null" json:"label"`
+	TanggalMulai   time.Time `gorm:"type:date;not null" json:"tanggal_mulai"`
+	TanggalSelesai time.Time `gorm:"type:date;not null" json:"tanggal_selesai"`
+	IsActive       bool      `gorm:"default:false;index" json:"is_active"`
+	SemesterAktif  uint8     `gorm:"default:1" json:"semester_aktif"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for TahunPelajaran
+func (TahunPelajaran) TableName() string {
+	return "tahun_pelajaran"
+}
+
+// KenaikanKelas model for grade promotion decisions
+type KenaikanKelas struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	SiswaID        uint      `gorm:"not null;uniqueIndex:idx_kenaikan_siswa_tahun" json:"siswa_id"`
+	TahunPelajaran string    `gorm:"size:20;not null;uniqueIndex:idx_kenaikan_siswa_tahun" json:"tahun_pelajaran"`
+	RombelID       *uint     `gorm:"index" json:"rombel_id"`
+	TingkatAsal    string    `gorm:"type:enum('X','XI','XII');not null" json:"tingkat_asal"`
+	TingkatTujuan  string    `gorm:"type:enum('X','XI','XII');not null" json:"tingkat_tujuan"`
+	Keputusan      string    `gorm:"type:enum('Naik','Tinggal');not null" json:"keputusan"`
+	Catatan        string    `gorm:"type:text" json:"catatan"`
+	Tanggal        time.Time `gorm:"type:date;not null" json:"tanggal"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for KenaikanKelas
+func (KenaikanKelas) TableName() string {
+	return "kenaikan_kelas"
+}
+
+// KehadiranHarian model for the daily attendance ledger
+type KehadiranHarian struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SiswaID    uint      `gorm:"not null;uniqueIndex:idx_kehadiran_harian_siswa_tanggal" json:"siswa_id"`
+	Tanggal    time.Time `gorm:"type:date;not null;uniqueIndex:idx_kehadiran_harian_siswa_tanggal" json:"tanggal"`
+	Kelas      string    `gorm:"type:enum('X','XI','XII');not null" json:"kelas"`
+	Semester   uint8     `gorm:"not null" json:"semester"`
+	Status     string    `gorm:"type:enum('hadir','sakit','izin','alpa');not null" json:"status"`
+	Keterangan string    `gorm:"type:text" json:"keterangan"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for KehadiranHarian
+func (KehadiranHarian) TableName() string {
+	return "kehadiran_harian"
+}
+
+// SkemaPenilaian model for the KKM and predikat boundaries of an academic year.
+// A scheme without MataPelajaranID is the default for subjects without their own.
+type SkemaPenilaian struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	TahunPelajaran  string    `gorm:"size:20;not null;uniqueIndex:idx_skema_tahun_mapel" json:"tahun_pelajaran"`
+	MataPelajaranID *uint     `gorm:"uniqueIndex:idx_skema_tahun_mapel" json:"mata_pelajaran_id"`
+	KKM             uint      `gorm:"not null" json:"kkm"`
+	BatasA          uint      `gorm:"not null" json:"batas_a"`
+	BatasB          uint      `gorm:"not null" json:"batas_b"`
+	BatasC          uint      `gorm:"not null" json:"batas_c"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Relations
+	MataPelajaran *MataPelajaran `gorm:"foreignKey:MataPelajaranID" json:"mata_pelajaran,omitempty"`
+}
+
+// TableName returns the table name for SkemaPenilaian
+func (SkemaPenilaian) TableName() string {
+	return "skema_penilaian"
+}
+
+// Kurikulum model for the subjects taught to a kompetensi keahlian per tingkat and semester
+type Kurikulum struct {
+	ID                   uint      `gorm:"primaryKey" json:"id"`
+	KompetensiKeahlianID uint      `gorm:"not null;uniqueIndex:idx_kurikulum_unique" json:"kompetensi_keahlian_id"`
+	Tingkat              string    `gorm:"type:enum('X','XI','XII');not null;uniqueIndex:idx_kurikulum_unique" json:"tingkat"`
+	Semester             uint8     `gorm:"not null;uniqueIndex:idx_kurikulum_unique" json:"semester"`
+	MataPelajaranID      uint      `gorm:"not null;uniqueIndex:idx_kurikulum_unique" json:"mata_pelajaran_id"`
+	Urutan               uint      `gorm:"default:0" json:"urutan"`
+	MenitPerMinggu       uint      `gorm:"default:0" json:"menit_per_minggu"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+
+	// Relations
+	KompetensiKeahlian *KompetensiKeahlian `gorm:"foreignKey:KompetensiKeahlianID" json:"kompetensi_keahlian,omitempty"`
+	MataPelajaran      *MataPelajaran      `gorm:"foreignKey:MataPelajaranID" json:"mata_pelajaran,omitempty"`
+}
+
+// TableName returns the table name for Kurikulum
+func (Kurikulum) TableName() string {
+	return "kurikulum"
+}
+
+// BidangKeahlian model for the top level of the SMK jurusan hierarchy
+type BidangKeahlian struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Kode      string    `gorm:"uniqueIndex;size:20;not null" json:"kode"`
+	Nama      string    `gorm:"size:100;not null" json:"nama"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	ProgramKeahlian []ProgramKeahlian `gorm:"foreignKey:BidangKeahlianID" json:"program_keahlian,omitempty"`
+}
+
+// TableName returns the table name for BidangKeahlian
+func (BidangKeahlian) TableName() string {
+	return "bidang_keahlian"
+}
+
+// ProgramKeahlian model for the programs within a bidang keahlian
+type ProgramKeahlian struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	BidangKeahlianID uint      `gorm:"not null;index" json:"bidang_keahlian_id"`
+	Kode             string    `gorm:"uniqueIndex;size:20;not null" json:"kode"`
+	Nama             string    `gorm:"size:100;not null" json:"nama"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Relations
+	BidangKeahlian     *BidangKeahlian      `gorm:"foreignKey:BidangKeahlianID" json:"bidang_keahlian,omitempty"`
+	KompetensiKeahlian []KompetensiKeahlian `gorm:"foreignKey:ProgramKeahlianID" json:"kompetensi_keahlian,omitempty"`
+}
+
+// TableName returns the table name for ProgramKeahlian
+func (ProgramKeahlian) TableName() string {
+	return "program_keahlian"
+}
+
+// KompetensiKeahlian model for the jurusan a student is enrolled in
+type KompetensiKeahlian struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ProgramKeahlianID uint      `gorm:"not null;index" json:"program_keahlian_id"`
+	Kode              string    `gorm:"uniqueIndex;size:20;not null" json:"kode"`
+	Nama              string    `gorm:"size:100;not null" json:"nama"`
+	Singkatan         string    `gorm:"size:20" json:"singkatan"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	// Relations
+	ProgramKeahlian *ProgramKeahlian `gorm:"foreignKey:ProgramKeahlianID" json:"program_keahlian,omitempty"`
+}
+
+// TableName returns the table name for KompetensiKeahlian
+func (KompetensiKeahlian) TableName() string {
+	return "kompetensi_keahlian"
+}
+
+// RiwayatJurusan model for the kompetensi keahlian history of a student.
+// The current assignment is the entry without TanggalSelesai.
+type RiwayatJurusan struct {
+	ID                   uint       `gorm:"primaryKey" json:"id"`
+	SiswaID              uint       `gorm:"not null;index" json:"siswa_id"`
+	KompetensiKeahlianID uint       `gorm:"not null;index" json:"kompetensi_keahlian_id"`
+	TanggalMulai         time.Time  `gorm:"type:date;not null" json:"tanggal_mulai"`
+	TanggalSelesai       *time.Time `gorm:"type:date" json:"tanggal_selesai"`
+	Keterangan           string     `gorm:"type:text" json:"keterangan"`
+	CreatedAt            time.Time  `json:"created_at"`
+
+	// Relations
+	KompetensiKeahlian *KompetensiKeahlian `gorm:"foreignKey:KompetensiKeahlianID" json:"kompetensi_keahlian,omitempty"`
+}
+
+// TableName returns the table name for RiwayatJurusan
+func (RiwayatJurusan) TableName() string {
+	return "riwayat_jurusan"
+}
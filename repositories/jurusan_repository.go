@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// JurusanRepository handles bidang, program and kompetensi keahlian database operations
+type JurusanRepository struct {
+	db *gorm.DB
+}
+
+// NewJurusanRepository creates a new JurusanRepository
+func NewJurusanRepository(db *gorm.DB) *JurusanRepository {
+	return &JurusanRepository{db: db}
+}
+
+// existsKode checks if a code is already used in the table of the given model
+func (r *JurusanRepository) existsKode(model interface{}, kode string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(model).Where("kode = ?", kode)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateBidang creates a new bidang keahlian
+func (r *JurusanRepository) CreateBidang(bidang *models.BidangKeahlian) error {
+	return r.db.Create(bidang).Error
+}
+
+// FindBidangByID finds a bidang keahlian by ID
+func (r *JurusanRepository) FindBidangByID(id uint) (*models.BidangKeahlian, error) {
+	var bidang models.BidangKeahlian
+	if err := r.db.First(&bidang, id).Error; err != nil {
+		return nil, err
+	}
+	return &bidang, nil
+}
+
+// FindAllBidang finds all bidang keahlian with their programs and kompetensi keahlian
+func (r *JurusanRepository) FindAllBidang() ([]models.BidangKeahlian, error) {
+	var bidang []models.BidangKeahlian
+	if err := r.db.
+		Preload("ProgramKeahlian", func(db *gorm.DB) *gorm.DB {
+			return db.Order("kode")
+		}).
+		Preload("ProgramKeahlian.KompetensiKeahlian", func(db *gorm.DB) *gorm.DB {
+			return db.Order("kode")
+		}).
+		Order("kode").
+		Find(&bidang).Error; err != nil {
+		return nil, err
+	}
+	return bidang, nil
+}
+
+// ExistsBidangByKode checks if a bidang keahlian code exists
+func (r *JurusanRepository) ExistsBidangByKode(kode string, excludeID uint) (bool, error) {
+	return r.existsKode(&models.BidangKeahlian{}, kode, excludeID)
+}
+
+// UpdateBidang updates a bidang keahlian
+func (r *JurusanRepository) UpdateBidang(bidang *models.BidangKeahlian) error {
+	return r.db.Omit("ProgramKeahlian").Save(bidang).Error
+}
+
+// DeleteBidang deletes a bidang keahlian
+func (r *JurusanRepository) DeleteBidang(id uint) error {
+	return r.db.Delete(&models.BidangKeahlian{}, id).Error
+}
+
+// CountProgramByBidang counts the programs of a bidang keahlian
+func (r *JurusanRepository) CountProgramByBidang(bidangID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.ProgramKeahlian{}).Where("bidang_keahlian_id = ?", bidangID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateProgram creates a new program keahlian
+func (r *JurusanRepository) CreateProgram(program *models.ProgramKeahlian) error {
+	return r.db.Create(program).Error
+}
+
+// FindProgramByID finds a program keahlian by ID
+func (r *JurusanRepository) FindProgramByID(id uint) (*models.ProgramKeahlian, error) {
+	var program models.ProgramKeahlian
+	if err := r.db.First(&program, id).Error; err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+// FindAllProgram finds program keahlian, optionally within one bidang keahlian
+func (r *JurusanRepository) FindAllProgram(bidangID uint) ([]models.ProgramKeahlian, error) {
+	var program []models.ProgramKeahlian
+	query := r.db.Model(&models.ProgramKeahlian{})
+	if bidangID > 0 {
+		query = query.Where("bidang_keahlian_id = ?", bidangID)
+	}
+	if err := query.Order("kode").Find(&program).Error; err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// ExistsProgramByKode checks if a program keahlian code exists
+func (r *JurusanRepository) ExistsProgramByKode(kode string, excludeID uint) (bool, error) {
+	return r.existsKode(&models.ProgramKeahlian{}, kode, excludeID)
+}
+
+// UpdateProgram updates a program keahlian
+func (r *JurusanRepository) UpdateProgram(program *models.ProgramKeahlian) error {
+	return r.db.Omit("BidangKeahlian", "KompetensiKeahlian").Save(program).Error
+}
+
+// DeleteProgram deletes a program keahlian
+func (r *JurusanRepository) DeleteProgram(id uint) error {
+	return r.db.Delete(&models.ProgramKeahlian{}, id).Error
+}
+
+// CountKompetensiByProgram counts the kompetensi keahlian of a program
+func (r *JurusanRepository) CountKompetensiByProgram(programID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.KompetensiKeahlian{}).Where("program_keahlian_id = ?", programID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateKompetensi creates a new kompetensi keahlian
+func (r *JurusanRepository) CreateKompetensi(kompetensi *models.KompetensiKeahlian) error {
+	return r.db.Create(kompetensi).Error
+}
+
+// FindKompetensiByID finds a kompetensi keahlian by ID with its program and bidang
+func (r *JurusanRepository) FindKompetensiByID(id uint) (*models.KompetensiKeahlian, error) {
+	var kompetensi models.KompetensiKeahlian
+	if err := r.db.Preload("ProgramKeahlian.BidangKeahlian").First(&kompetensi, id).Error; err != nil {
+		return nil, err
+	}
+	return &kompetensi, nil
+}
+
+// FindKompetensiByIDs finds the kompetensi keahlian with the given IDs
+func (r *JurusanRepository) FindKompetensiByIDs(ids []uint) ([]models.KompetensiKeahlian, error) {
+	var kompetensi []models.KompetensiKeahlian
+	if len(ids) == 0 {
+		return kompetensi, nil
+	}
+	if err := r.db.Preload("ProgramKeahlian.BidangKeahlian").Where("id IN ?", ids).Find(&kompetensi).Error; err != nil {
+		return nil, err
+	}
+	return kompetensi, nil
+}
+
+// FindAllKompetensi finds kompetensi keahlian with optional filters
+func (r *JurusanRepository) FindAllKompetensi(filter map[string]interface{}) ([]models.KompetensiKeahlian, error) {
+	var kompetensi []models.KompetensiKeahlian
+	query := r.db.Preload("ProgramKeahlian.BidangKeahlian")
+
+	if val, ok := filter["program_keahlian_id"].(uint); ok && val > 0 {
+		query = query.Where("program_keahlian_id = ?", val)
+	}
+	if val, ok := filter["bidang_keahlian_id"].(uint); ok && val > 0 {
+		query = query.Where("program_keahlian_id IN (?)", r.db.Model(&models.ProgramKeahlian{}).Select("id").Where("bidang_keahlian_id = ?", val))
+	}
+
+	if err := query.Order("kode").Find(&kompetensi).Error; err != nil {
+		return nil, err
+	}
+	return kompetensi, nil
+}
+
+// ExistsKompetensiByKode checks if a kompetensi keahlian code exists
+func (r *JurusanRepository) ExistsKompetensiByKode(kode string, excludeID uint) (bool, error) {
+	return r.existsKode(&models.KompetensiKeahlian{}, kode, excludeID)
+}
+
+// UpdateKompetensi updates a kompetensi keahlian
+func (r *JurusanRepository) UpdateKompetensi(kompetensi *models.KompetensiKeahlian) error {
+	return r.db.Omit("ProgramKeahlian").Save(kompetensi).Error
+}
+
+// DeleteKompetensi deletes a kompetensi keahlian
+func (r *JurusanRepository) DeleteKompetensi(id uint) error {
+	return r.db.Delete(&models.KompetensiKeahlian{}, id).Error
+}
+
+// IsKompetensiInUse checks if a kompetensi keahlian is assigned to a student,
+// recorded in a jurusan history or used by a curriculum
+func (r *JurusanRepository) IsKompetensiInUse(id uint) (bool, error) {
+	for _, model := range []interface{}{&models.Siswa{}, &models.RiwayatJurusan{}, &models.Kurikulum{}} {
+		var count int64
+		if err := r.db.Unscoped().Model(model).Where("kompetensi_keahlian_id = ?", id).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FindRiwayatAktif finds the open jurusan history entry of a student
+func (r *JurusanRepository) FindRiwayatAktif(siswaID uint) (*models.RiwayatJurusan, error) {
+	var riwayat models.RiwayatJurusan
+	if err := r.db.Where("siswa_id = ? AND tanggal_selesai IS NULL", siswaID).
+		Order("tanggal_mulai DESC").
+		First(&riwayat).Error; err != nil {
+		return nil, err
+	}
+	return &riwayat, nil
+}
+
+// FindRiwayatBySiswaID finds the jurusan history of a student, oldest first
+func (r *JurusanRepository) FindRiwayatBySiswaID(siswaID uint) ([]models.RiwayatJurusan, error) {
+	var riwayat []models.RiwayatJurusan
+	if err := r.db.Preload("KompetensiKeahlian.ProgramKeahlian.BidangKeahlian").
+		Where("siswa_id = ?", siswaID).
+		Order("tanggal_mulai, id").
+		Find(&riwayat).Error; err != nil {
+		return nil, err
+	}
+	return riwayat, nil
+}
+
+// AssignSiswa moves a student to a kompetensi keahlian. The open history entry
+// is closed on the start date of the new one.
+func (r *JurusanRepository) AssignSiswa(riwayat *models.RiwayatJurusan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RiwayatJurusan{}).
+			Where("siswa_id = ? AND tanggal_selesai IS NULL", riwayat.SiswaID).
+			Update("tanggal_selesai", riwayat.TanggalMulai).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit("KompetensiKeahlian").Create(riwayat).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Siswa{}).
+			Where("id = ?", riwayat.SiswaID).
+			Update("kompetensi_keahlian_id", riwayat.KompetensiKeahlianID).Error
+	})
+}
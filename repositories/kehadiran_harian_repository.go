@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"math"
+	"time"
+
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KehadiranHarianRepository handles daily attendance ledger database operations
+type KehadiranHarianRepository struct {
+	db *gorm.DB
+}
+
+// NewKehadiranHarianRepository creates a new KehadiranHarianRepository
+func NewKehadiranHarianRepository(db *gorm.DB) *KehadiranHarianRepository {
+	return &KehadiranHarianRepository{db: db}
+}
+
+// periodeKehadiran identifies the semester summary a daily entry rolls up into
+type periodeKehadiran struct {
+	SiswaID  uint
+	Kelas    string
+	Semester uint8
+}
+
+// Upsert records daily attendance keyed by (siswa_id, tanggal) and recomputes
+// every semester summary touched by the change
+func (r *KehadiranHarianRepository) Upsert(entries []models.KehadiranHarian) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		periode := make(map[periodeKehadiran]bool)
+		var siswaIDs []uint
+		var tanggal []time.Time
+		for _, e := range entries {
+			periode[periodeKehadiran{e.SiswaID, e.Kelas, e.Semester}] = true
+			siswaIDs = append(siswaIDs, e.SiswaID)
+			tanggal = append(tanggal, e.Tanggal)
+		}
+
+		// An entry moved to another kelas/semester must also refresh its old summary
+		var existing []models.KehadiranHarian
+		if err := tx.Where("siswa_id IN ? AND tanggal IN ?", siswaIDs, tanggal).Find(&existing).Error; err != nil {
+			return err
+		}
+		for _, e := range existing {
+			periode[periodeKehadiran{e.SiswaID, e.Kelas, e.Semester}] = true
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "siswa_id"}, {Name: "tanggal"}},
+			DoUpdates: clause.AssignmentColumns([]string{"kelas", "semester", "status", "keterangan", "updated_at"}),
+		}).Create(&entries).Error; err != nil {
+			return err
+		}
+
+		for p := range periode {
+			if err := recomputeKehadiran(tx, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindByID finds a daily attendance entry by ID
+func (r *KehadiranHarianRepository) FindByID(id uint) (*models.KehadiranHarian, error) {
+	var harian models.KehadiranHarian
+	if err := r.db.First(&harian, id).Error; err != nil {
+		return nil, err
+	}
+	return &harian, nil
+}
+
+// FindBySiswaIDAndTanggal finds the entries of the given students on one date
+func (r *KehadiranHarianRepository) FindBySiswaIDAndTanggal(siswaIDs []uint, tanggal time.Time) ([]models.KehadiranHarian, error) {
+	var harian []models.KehadiranHarian
+	if len(siswaIDs) == 0 {
+		return harian, nil
+	}
+	if err := r.db.Where("siswa_id IN ? AND tanggal = ?", siswaIDs, tanggal).
+		Order("siswa_id").
+		Find(&harian).Error; err != nil {
+		return nil, err
+	}
+	return harian, nil
+}
+
+// FindBySiswaIDPaginated finds a student's daily attendance entries with pagination
+func (r *KehadiranHarianRepository) FindBySiswaIDPaginated(siswaID uint, filter map[string]interface{}, page, pageSize int) ([]models.KehadiranHarian, int64, error) {
+	var harian []models.KehadiranHarian
+	var total int64
+
+	query := r.db.Model(&models.KehadiranHarian{}).Where("siswa_id = ?", siswaID)
+
+	// Apply filters
+	if val, ok := filter["kelas"].(string); ok && val != "" {
+		query = query.Where("kelas = ?", val)
+	}
+	if val, ok := filter["semester"].(uint8); ok && val > 0 {
+		query = query.Where("semester = ?", val)
+	}
+	if val, ok := filter["status"].(string); ok && val != "" {
+		query = query.Where("status = ?", val)
+	}
+	if val, ok := filter["tanggal_mulai"].(time.Time); ok {
+		query = query.Where("tanggal >= ?", val)
+	}
+	if val, ok := filter["tanggal_selesai"].(time.Time); ok {
+		query = query.Where("tanggal <= ?", val)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * pageSize
+	if err := query.Order("tanggal DESC").Offset(offset).Limit(pageSize).Find(&harian).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return harian, total, nil
+}
+
+// CountByPeriode counts the ledger entries of a student's kelas/semester
+func (r *KehadiranHarianRepository) CountByPeriode(siswaID uint, kelas string, semester uint8) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.KehadiranHarian{}).
+		Where("siswa_id = ? AND kelas = ? AND semester = ?", siswaID, kelas, semester).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Delete deletes a daily attendance entry and recomputes its semester summary
+func (r *KehadiranHarianRepository) Delete(harian *models.KehadiranHarian) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.KehadiranHarian{}, harian.ID).Error; err != nil {
+			return err
+		}
+		return recomputeKehadiran(tx, periodeKehadiran{harian.SiswaID, harian.Kelas, harian.Semester})
+	})
+}
+
+// recomputeKehadiran rebuilds the kehadiran summary of one kelas/semester from
+// the daily ledger. Every ledger entry is one effective school day.
+func recomputeKehadiran(tx *gorm.DB, p periodeKehadiran) error {
+	var rows []struct {
+		Status string
+		Total  uint
+	}
+	if err := tx.Model(&models.KehadiranHarian{}).
+		Select("status, COUNT(*) AS total").
+		Where("siswa_id = ? AND kelas = ? AND semester = ?", p.SiswaID, p.Kelas, p.Semester).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	var kehadiran models.Kehadiran
+	if err := tx.Where("siswa_id = ? AND kelas = ? AND semester = ?", p.SiswaID, p.Kelas, p.Semester).
+		Limit(1).
+		Find(&kehadiran).Error; err != nil {
+		return err
+	}
+	if kehadiran.ID == 0 && len(rows) == 0 {
+		return nil
+	}
+
+	kehadiran.SiswaID = p.SiswaID
+	kehadiran.Kelas = p.Kelas
+	kehadiran.Semester = p.Semester
+	kehadiran.JumlahHadir = 0
+	kehadiran.JumlahSakit = 0
+	kehadiran.JumlahIzin = 0
+	kehadiran.JumlahAlpa = 0
+	for _, row := range rows {
+		switch row.Status {
+		case "hadir":
+			kehadiran.JumlahHadir = row.Total
+		case "sakit":
+			kehadiran.JumlahSakit = row.Total
+		case "izin":
+			kehadiran.JumlahIzin = row.Total
+		case "alpa":
+			kehadiran.JumlahAlpa = row.Total
+		}
+	}
+	kehadiran.JumlahHariEfektif = kehadiran.JumlahHadir + kehadiran.JumlahSakit + kehadiran.JumlahIzin + kehadiran.JumlahAlpa
+	kehadiran.PersentaseHadir = persentaseHadir(kehadiran.JumlahHadir, kehadiran.JumlahHariEfektif)
+
+	if err := tx.Save(&kehadiran).Error; err != nil {
+		return err
+	}
+	return syncKetidakhadiran(tx, &kehadiran)
+}
+
+// syncKetidakhadiran copies the absence counts of a semester summary onto the
+// absence notes of the matching semester notes, if any were created
+func syncKetidakhadiran(tx *gorm.DB, kehadiran *models.Kehadiran) error {
+	var catatanIDs []uint
+	if err := tx.Model(&models.CatatanAkhirSemester{}).
+		Where("siswa_id = ? AND kelas = ? AND semester = ?", kehadiran.SiswaID, kehadiran.Kelas, kehadiran.Semester).
+		Pluck("id", &catatanIDs).Error; err != nil {
+		return err
+	}
+
+	for _, catatanID := range catatanIDs {
+		ketidakhadiran := models.KetidakhadiranCatatan{
+			CatatanID:       catatanID,
+			KarenaSakit:     kehadiran.JumlahSakit,
+			DenganIzin:      kehadiran.JumlahIzin,
+			TanpaKeterangan: kehadiran.JumlahAlpa,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "catatan_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"karena_sakit", "dengan_izin", "tanpa_keterangan"}),
+		}).Create(&ketidakhadiran).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persentaseHadir returns the attendance percentage rounded to two decimals
+func persentaseHadir(hadir, hariEfektif uint) float64 {
+	if hariEfektif == 0 {
+		return 0
+	}
+	return math.Round(float64(hadir)*10000/float64(hariEfektif)) / 100
+}
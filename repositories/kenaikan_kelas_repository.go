@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// KenaikanKelasRepository handles grade promotion database operations
+type KenaikanKelasRepository struct {
+	db *gorm.DB
+}
+
+// NewKenaikanKelasRepository creates a new KenaikanKelasRepository
+func NewKenaikanKelasRepository(db *gorm.DB) *KenaikanKelasRepository {
+	return &KenaikanKelasRepository{db: db}
+}
+
+// CreateBatch stores promotion decisions in a single transaction
+func (r *KenaikanKelasRepository) CreateBatch(kenaikan []models.KenaikanKelas) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&kenaikan).Error
+	})
+}
+
+// FindBySiswaID finds the promotion history of a student
+func (r *KenaikanKelasRepository) FindBySiswaID(siswaID uint) ([]models.KenaikanKelas, error) {
+	var kenaikan []models.KenaikanKelas
+	if err := r.db.Where("siswa_id = ?", siswaID).Order("tahun_pelajaran").Find(&kenaikan).Error; err != nil {
+		return nil, err
+	}
+	return kenaikan, nil
+}
+
+// FindProcessedSiswaIDs returns the subset of students that already have a decision for an academic year
+func (r *KenaikanKelasRepository) FindProcessedSiswaIDs(siswaIDs []uint, tahunPelajaran string) ([]uint, error) {
+	var ids []uint
+	if len(siswaIDs) == 0 {
+		return ids, nil
+	}
+	if err := r.db.Model(&models.KenaikanKelas{}).
+		Where("siswa_id IN ? AND tahun_pelajaran = ?", siswaIDs, tahunPelajaran).
+		Pluck("siswa_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// KurikulumRepository handles curriculum database operations
+type KurikulumRepository struct {
+	db *gorm.DB
+}
+
+// NewKurikulumRepository creates a new KurikulumRepository
+func NewKurikulumRepository(db *gorm.DB) *KurikulumRepository {
+	return &KurikulumRepository{db: db}
+}
+
+// Create creates a new curriculum entry
+func (r *KurikulumRepository) Create(kurikulum *models.Kurikulum) error {
+	return r.db.Create(kurikulum).Error
+}
+
+// FindByID finds a curriculum entry by ID
+func (r *KurikulumRepository) FindByID(id uint) (*models.Kurikulum, error) {
+	var kurikulum models.Kurikulum
+	if err := r.db.Preload("KompetensiKeahlian").Preload("MataPelajaran").First(&kurikulum, id).Error; err != nil {
+		return nil, err
+	}
+	return &kurikulum, nil
+}
+
+// FindAll finds curriculum entries in report order
+func (r *KurikulumRepository) FindAll(filter map[string]interface{}) ([]models.Kurikulum, error) {
+	var kurikulum []models.Kurikulum
+	query := r.db.Preload("KompetensiKeahlian").Preload("MataPelajaran")
+
+	if val, ok := filter["kompetensi_keahlian_id"].(uint); ok && val > 0 {
+		query = query.Where("kompetensi_keahlian_id = ?", val)
+	}
+	if val, ok := filter["tingkat"].(string); ok && val != "" {
+		query = query.Where("tingkat = ?", val)
+	}
+	if val, ok := filter["semester"].(uint8); ok && val > 0 {
+		query = query.Where("semester = ?", val)
+	}
+	if val, ok := filter["mata_pelajaran_id"].(uint); ok && val > 0 {
+		query = query.Where("mata_pelajaran_id = ?", val)
+	}
+
+	if err := query.Order("kompetensi_keahlian_id, tingkat, semester, urutan, id").Find(&kurikulum).Error; err != nil {
+		return nil, err
+	}
+	return kurikulum, nil
+}
+
+// Exists checks if a subject is already in the curriculum of a kompetensi keahlian/tingkat/semester
+func (r *KurikulumRepository) Exists(kompetensiKeahlianID uint, tingkat string, semester uint8, mataPelajaranID, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.Kurikulum{}).
+		Where("kompetensi_keahlian_id = ? AND tingkat = ? AND semester = ? AND mata_pelajaran_id = ?", kompetensiKeahlianID, tingkat, semester, mataPelajaranID)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindMataPelajaranIDs returns the subjects in the curriculum of a kompetensi keahlian/tingkat/semester
+func (r *KurikulumRepository) FindMataPelajaranIDs(kompetensiKeahlianID uint, tingkat string, semester uint8) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.Kurikulum{}).
+		Where("kompetensi_keahlian_id = ? AND tingkat = ? AND semester = ?", kompetensiKeahlianID, tingkat, semester).
+		Order("urutan, id").
+		Pluck("mata_pelajaran_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Update updates a curriculum entry
+func (r *KurikulumRepository) Update(kurikulum *models.Kurikulum) error {
+	return r.db.Omit("KompetensiKeahlian", "MataPelajaran").Save(kurikulum).Error
+}
+
+// Delete deletes a curriculum entry
+func (r *KurikulumRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Kurikulum{}, id).Error
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository handles the login history
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new LoginAttemptRepository
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Create records a login attempt
+func (r *LoginAttemptRepository) Create(attempt *models.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// FailuresByIP counts the failed logins from an IP address since a time and
+// returns the oldest of them. Attempts rejected while blocked are not counted.
+func (r *LoginAttemptRepository) FailuresByIP(ip string, since time.Time) (int64, *time.Time, error) {
+	var row struct {
+		Jumlah  int64
+		Pertama *time.Time
+	}
+	if err := r.db.Model(&models.LoginAttempt{}).
+		Select("COUNT(*) AS jumlah, MIN(created_at) AS pertama").
+		Where("ip_address = ? AND success = ? AND reason <> ? AND created_at >= ?", ip, false, models.LoginReasonBlocked, since).
+		Scan(&row).Error; err != nil {
+		return 0, nil, err
+	}
+	return row.Jumlah, row.Pertama, nil
+}
+
+// FindByUserIDPaginated finds the login history of a user, newest first
+func (r *LoginAttemptRepository) FindByUserIDPaginated(userID uint, page, pageSize int) ([]models.LoginAttempt, int64, error) {
+	var attempts []models.LoginAttempt
+	var total int64
+
+	query := r.db.Model(&models.LoginAttempt{}).Where("user_id = ?", userID)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return attempts, total, nil
+}
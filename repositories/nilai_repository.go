@@ -1,8 +1,13 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
 	"github.com/kampunk/api-siswa/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MataPelajaranRepository handles subject database operations
@@ -14,9 +19,26 @@ func NewMataPelajaranRepository(db *gorm.DB) *MataPelajaranRepository {
 	return &MataPelajaranRepository{db: db}
 }
 
-func (r *MataPelajaranRepository) FindAll() ([]models.MataPelajaran, error) {
+func (r *MataPelajaranRepository) Create(mapel *models.MataPelajaran) error {
+	return r.db.Create(mapel).Error
+}
+
+func (r *MataPelajaranRepository) FindAll(search string, filter map[string]interface{}) ([]models.MataPelajaran, error) {
 	var mapel []models.MataPelajaran
-	if err := r.db.Where("aktif = ?", true).Order("kelompok, nama").Find(&mapel).Error; err != nil {
+	query := r.db.Model(&models.MataPelajaran{})
+
+	if search != "" {
+		searchPattern := "%" + search + "%"
+		query = query.Where("kode LIKE ? OR nama LIKE ?", searchPattern, searchPattern)
+	}
+	if val, ok := filter["aktif"].(bool); ok {
+		query = query.Where("aktif = ?", val)
+	}
+	if val, ok := filter["kelompok"].(string); ok && val != "" {
+		query = query.Where("kelompok = ?", val)
+	}
+
+	if err := query.Order("kelompok, nama").Find(&mapel).Error; err != nil {
 		return nil, err
 	}
 	return mapel, nil
@@ -30,6 +52,44 @@ func (r *MataPelajaranRepository) FindByID(id uint) (*models.MataPelajaran, erro
 	return &mapel, nil
 }
 
+func (r *MataPelajaranRepository) ExistsByKode(kode string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.MataPelajaran{}).Where("kode = ?", kode)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsInUse checks if any grade or curriculum entry still references the subject
+func (r *MataPelajaranRepository) IsInUse(id uint) (bool, error) {
+	for _, model := range []interface{}{
+		&models.NilaiSemester{},
+		&models.NilaiIjazah{},
+		&models.Kurikulum{},
+	} {
+		var count int64
+		if err := r.db.Model(model).Where("mata_pelajaran_id = ?", id).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MataPelajaranRepository) Update(mapel *models.MataPelajaran) error {
+	return r.db.Save(mapel).Error
+}
+
+func (r *MataPelajaranRepository) Delete(id uint) error {
+	return r.db.Delete(&models.MataPelajaran{}, id).Error
+}
+
 func (r *MataPelajaranRepository) FindByKelompok(kelompok string) ([]models.MataPelajaran, error) {
 	var mapel []models.MataPelajaran
 	if err := r.db.Where("kelompok = ? AND aktif = ?", kelompok, true).Find(&mapel).Error; err != nil {
@@ -126,6 +186,63 @@ func (r *NilaiSemesterRepository) FindBySiswaIDFiltered(siswaID uint, kelas stri
 	return nilai, nil
 }
 
+// FindLegger finds the grades of all members of a class group for a kelas,
+// semester and academic year in one query
+func (r *NilaiSemesterRepository) FindLegger(rombelID uint, kelas string, semester uint8, tahunPelajaran string) ([]models.NilaiSemester, error) {
+	var nilai []models.NilaiSemester
+	if err := r.db.Preload("MataPelajaran").
+		Joins("JOIN anggota_rombel ON anggota_rombel.siswa_id = nilai_semester.siswa_id AND anggota_rombel.tahun_pelajaran = nilai_semester.tahun_pelajaran").
+		Where("anggota_rombel.rombel_id = ?", rombelID).
+		Where("nilai_semester.kelas = ? AND nilai_semester.semester = ? AND nilai_semester.tahun_pelajaran = ?", kelas, semester, tahunPelajaran).
+		Order("nilai_semester.siswa_id, nilai_semester.mata_pelajaran_id").
+		Find(&nilai).Error; err != nil {
+		return nil, err
+	}
+	return nilai, nil
+}
+
+// FindByTingkat finds the grades of all students enrolled in a class group of a
+// tingkat for a semester and academic year
+func (r *NilaiSemesterRepository) FindByTingkat(tingkat string, semester uint8, tahunPelajaran string) ([]models.NilaiSemester, error) {
+	var nilai []models.NilaiSemester
+	if err := r.db.Preload("MataPelajaran").
+		Joins("JOIN anggota_rombel ON anggota_rombel.siswa_id = nilai_semester.siswa_id AND anggota_rombel.tahun_pelajaran = nilai_semester.tahun_pelajaran").
+		Joins("JOIN rombel ON rombel.id = anggota_rombel.rombel_id").
+		Where("rombel.tingkat = ?", tingkat).
+		Where("nilai_semester.kelas = ? AND nilai_semester.semester = ? AND nilai_semester.tahun_pelajaran = ?", tingkat, semester, tahunPelajaran).
+		Order("nilai_semester.siswa_id, nilai_semester.mata_pelajaran_id").
+		Find(&nilai).Error; err != nil {
+		return nil, err
+	}
+	return nilai, nil
+}
+
+// CountTidakTuntasBySiswaIDs counts grades below the minimum score per student in an academic year
+func (r *NilaiSemesterRepository) CountTidakTuntasBySiswaIDs(siswaIDs []uint, tahunPelajaran string, nilaiMinimum uint) (map[uint]int64, error) {
+	result := make(map[uint]int64)
+	if len(siswaIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		SiswaID uint
+		Total   int64
+	}
+	if err := r.db.Model(&models.NilaiSemester{}).
+		Select("siswa_id, COUNT(*) AS total").
+		Where("siswa_id IN ? AND tahun_pelajaran = ?", siswaIDs, tahunPelajaran).
+		Where("nilai_pengetahuan < ? OR nilai_keterampilan < ?", nilaiMinimum, nilaiMinimum).
+		Group("siswa_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.SiswaID] = row.Total
+	}
+	return result, nil
+}
+
 func (r *NilaiSemesterRepository) FindByID(id uint) (*models.NilaiSemester, error) {
 	var nilai models.NilaiSemester
 	if err := r.db.Preload("MataPelajaran").First(&nilai, id).Error; err != nil {
@@ -167,6 +284,35 @@ func (r *NilaiSikapRepository) Update(sikap *models.NilaiSikap) error {
 	return r.db.Save(sikap).Error
 }
 
+func (r *NilaiSikapRepository) FindBySiswaIDAndSemester(siswaID uint, kelas string, semester uint8) (*models.NilaiSikap, error) {
+	var sikap models.NilaiSikap
+	if err := r.db.Where("siswa_id = ? AND kelas = ? AND semester = ?", siswaID, kelas, semester).First(&sikap).Error; err != nil {
+		return nil, err
+	}
+	return &sikap, nil
+}
+
+func (r *NilaiSikapRepository) FindBySiswaIDsAndSemester(siswaIDs []uint, kelas string, semester uint8) ([]models.NilaiSikap, error) {
+	var sikap []models.NilaiSikap
+	if err := r.db.Where("siswa_id IN ? AND kelas = ? AND semester = ?", siswaIDs, kelas, semester).Order("siswa_id").Find(&sikap).Error; err != nil {
+		return nil, err
+	}
+	return sikap, nil
+}
+
+// Upsert creates or updates attitude grades keyed by the (siswa_id, kelas, semester) unique index
+func (r *NilaiSikapRepository) Upsert(sikap []models.NilaiSikap) error {
+	if len(sikap) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "siswa_id"}, {Name: "kelas"}, {Name: "semester"}},
+			DoUpdates: clause.AssignmentColumns([]string{"deskripsi_spiritual", "deskripsi_sosial"}),
+		}).Create(&sikap).Error
+	})
+}
+
 // CatatanRepository handles semester notes database operations
 type CatatanRepository struct {
 	db *gorm.DB
@@ -176,8 +322,15 @@ func NewCatatanRepository(db *gorm.DB) *CatatanRepository {
 	return &CatatanRepository{db: db}
 }
 
+// Create creates semester notes. It returns gorm.ErrDuplicatedKey when notes
+// for the kelas/semester already exist.
 func (r *CatatanRepository) Create(catatan *models.CatatanAkhirSemester) error {
-	return r.db.Create(catatan).Error
+	err := r.db.Create(catatan).Error
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return gorm.ErrDuplicatedKey
+	}
+	return err
 }
 
 func (r *CatatanRepository) FindBySiswaID(siswaID uint) ([]models.CatatanAkhirSemester, error) {
@@ -224,6 +377,73 @@ func (r *CatatanRepository) SetKetidakhadiran(ketidakhadiran *models.Ketidakhadi
 	return r.db.Save(ketidakhadiran).Error
 }
 
+func (r *CatatanRepository) FindBySiswaKelasSemester(siswaID uint, kelas string, semester uint8) (*models.CatatanAkhirSemester, error) {
+	var catatan models.CatatanAkhirSemester
+	if err := r.db.
+		Preload("PKL").
+		Preload("Ekstrakurikuler").
+		Preload("PrestasiSemester").
+		Preload("Ketidakhadiran").
+		Where("siswa_id = ? AND kelas = ? AND semester = ?", siswaID, kelas, semester).
+		First(&catatan).Error; err != nil {
+		return nil, err
+	}
+	return &catatan, nil
+}
+
+func (r *CatatanRepository) FindPKLByID(id uint) (*models.PraktikKerjaLapangan, error) {
+	var pkl models.PraktikKerjaLapangan
+	if err := r.db.First(&pkl, id).Error; err != nil {
+		return nil, err
+	}
+	return &pkl, nil
+}
+
+func (r *CatatanRepository) UpdatePKL(pkl *models.PraktikKerjaLapangan) error {
+	return r.db.Save(pkl).Error
+}
+
+func (r *CatatanRepository) DeletePKL(id uint) error {
+	return r.db.Delete(&models.PraktikKerjaLapangan{}, id).Error
+}
+
+func (r *CatatanRepository) FindEkstrakurikulerByID(id uint) (*models.Ekstrakurikuler, error) {
+	var ekskul models.Ekstrakurikuler
+	if err := r.db.First(&ekskul, id).Error; err != nil {
+		return nil, err
+	}
+	return &ekskul, nil
+}
+
+func (r *CatatanRepository) UpdateEkstrakurikuler(ekskul *models.Ekstrakurikuler) error {
+	return r.db.Save(ekskul).Error
+}
+
+func (r *CatatanRepository) DeleteEkstrakurikuler(id uint) error {
+	return r.db.Delete(&models.Ekstrakurikuler{}, id).Error
+}
+
+func (r *CatatanRepository) FindPrestasiSemesterByID(id uint) (*models.PrestasiSemester, error) {
+	var prestasi models.PrestasiSemester
+	if err := r.db.First(&prestasi, id).Error; err != nil {
+		return nil, err
+	}
+	return &prestasi, nil
+}
+
+func (r *CatatanRepository) UpdatePrestasiSemester(prestasi *models.PrestasiSemester) error {
+	return r.db.Save(prestasi).Error
+}
+
+func (r *CatatanRepository) DeletePrestasiSemester(id uint) error {
+	return r.db.Delete(&models.PrestasiSemester{}, id).Error
+}
+
+func (r *CatatanRepository) DeleteKetidakhadiran(catatanID uint) (int64, error) {
+	result := r.db.Where("catatan_id = ?", catatanID).Delete(&models.KetidakhadiranCatatan{})
+	return result.RowsAffected, result.Error
+}
+
 // NilaiIjazahRepository handles certificate grade database operations
 type NilaiIjazahRepository struct {
 	db *gorm.DB
@@ -274,6 +494,31 @@ func (r *MeninggalkanSekolahRepository) Update(keluar *models.MeninggalkanSekola
 	return r.db.Save(keluar).Error
 }
 
+func (r *MeninggalkanSekolahRepository) DeleteBySiswaID(siswaID uint) error {
+	return r.db.Where("siswa_id = ?", siswaID).Delete(&models.MeninggalkanSekolah{}).Error
+}
+
+// CreateBatch records several leaving school records in a single transaction
+func (r *MeninggalkanSekolahRepository) CreateBatch(keluar []models.MeninggalkanSekolah) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&keluar).Error
+	})
+}
+
+// FindSiswaIDsBySiswaIDs returns the subset of students that already left school
+func (r *MeninggalkanSekolahRepository) FindSiswaIDsBySiswaIDs(siswaIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(siswaIDs) == 0 {
+		return ids, nil
+	}
+	if err := r.db.Model(&models.MeninggalkanSekolah{}).
+		Where("siswa_id IN ?", siswaIDs).
+		Pluck("siswa_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // PemeriksaanRepository handles book inspection database operations
 type PemeriksaanRepository struct {
 	db *gorm.DB
@@ -283,22 +528,125 @@ func NewPemeriksaanRepository(db *gorm.DB) *PemeriksaanRepository {
 	return &PemeriksaanRepository{db: db}
 }
 
+// Create records a book inspection, numbering it after the last inspection of the same year
 func (r *PemeriksaanRepository) Create(pemeriksaan *models.PemeriksaanBuku) error {
-	return r.db.Create(pemeriksaan).Error
+	return retryNoUrut(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			noUrut, err := r.nextNoUrut(tx, pemeriksaan.Tanggal.Year())
+			if err != nil {
+				return err
+			}
+			pemeriksaan.NoUrut = noUrut
+			return tx.Create(pemeriksaan).Error
+		})
+	})
+}
+
+func (r *PemeriksaanRepository) FindByID(id uint) (*models.PemeriksaanBuku, error) {
+	var pemeriksaan models.PemeriksaanBuku
+	if err := r.db.First(&pemeriksaan, id).Error; err != nil {
+		return nil, err
+	}
+	return &pemeriksaan, nil
 }
 
-func (r *PemeriksaanRepository) FindAll() ([]models.PemeriksaanBuku, error) {
+func (r *PemeriksaanRepository) FindAll(page, pageSize int, filter map[string]interface{}) ([]models.PemeriksaanBuku, int64, error) {
 	var pemeriksaan []models.PemeriksaanBuku
-	if err := r.db.Order("tanggal DESC").Find(&pemeriksaan).Error; err != nil {
+	var total int64
+
+	query := r.filterQuery(filter)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * pageSize
+	if err := query.Order("tanggal DESC, no_urut DESC").Offset(offset).Limit(pageSize).Find(&pemeriksaan).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return pemeriksaan, total, nil
+}
+
+// FindByPeriode finds all inspections in a date range in chronological order
+func (r *PemeriksaanRepository) FindByPeriode(filter map[string]interface{}) ([]models.PemeriksaanBuku, error) {
+	var pemeriksaan []models.PemeriksaanBuku
+	if err := r.filterQuery(filter).Order("tanggal, no_urut").Find(&pemeriksaan).Error; err != nil {
 		return nil, err
 	}
 	return pemeriksaan, nil
 }
 
-func (r *PemeriksaanRepository) Update(pemeriksaan *models.PemeriksaanBuku) error {
-	return r.db.Save(pemeriksaan).Error
+// Update updates a book inspection, renumbering it when it moves to another year
+func (r *PemeriksaanRepository) Update(pemeriksaan *models.PemeriksaanBuku, pindahTahun bool) error {
+	return retryNoUrut(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if pindahTahun {
+				noUrut, err := r.nextNoUrut(tx, pemeriksaan.Tanggal.Year())
+				if err != nil {
+					return err
+				}
+				pemeriksaan.NoUrut = noUrut
+			}
+			return tx.Save(pemeriksaan).Error
+		})
+	})
 }
 
 func (r *PemeriksaanRepository) Delete(id uint) error {
 	return r.db.Delete(&models.PemeriksaanBuku{}, id).Error
 }
+
+// filterQuery applies the date range and year filters
+func (r *PemeriksaanRepository) filterQuery(filter map[string]interface{}) *gorm.DB {
+	query := r.db.Model(&models.PemeriksaanBuku{})
+	if val, ok := filter["tanggal_mulai"].(time.Time); ok {
+		query = query.Where("tanggal >= ?", val)
+	}
+	if val, ok := filter["tanggal_selesai"].(time.Time); ok {
+		query = query.Where("tanggal <= ?", val)
+	}
+	if val, ok := filter["tahun"].(int); ok && val > 0 {
+		query = query.Where("tanggal BETWEEN ? AND ?", awalTahun(val), akhirTahun(val))
+	}
+	return query
+}
+
+// nextNoUrut returns the next sequence number of a year, locking the rows it reads
+func (r *PemeriksaanRepository) nextNoUrut(tx *gorm.DB, tahun int) (uint, error) {
+	var last []uint
+	if err := tx.Model(&models.PemeriksaanBuku{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tanggal BETWEEN ? AND ?", awalTahun(tahun), akhirTahun(tahun)).
+		Order("no_urut DESC").
+		Limit(1).
+		Pluck("no_urut", &last).Error; err != nil {
+		return 0, err
+	}
+	if len(last) == 0 {
+		return 1, nil
+	}
+	return last[0] + 1, nil
+}
+
+// retryNoUrut runs a numbering transaction again once when it lost a race for
+// the same year: two first inspections of a year lock the same gap and one of
+// them deadlocks, or both pick the same number and one hits the unique index
+func retryNoUrut(fn func() error) error {
+	err := fn()
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && (mysqlErr.Number == 1213 || mysqlErr.Number == 1062) {
+		return fn()
+	}
+	return err
+}
+
+func awalTahun(tahun int) time.Time {
+	return time.Date(tahun, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func akhirTahun(tahun int) time.Time {
+	return time.Date(tahun, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository handles password reset token database operations
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository creates a new PasswordResetRepository
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create creates a reset token, dropping the user's earlier tokens so only the
+// latest email works
+func (r *PasswordResetRepository) Create(reset *models.PasswordReset) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", reset.UserID).Delete(&models.PasswordReset{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(reset).Error
+	})
+}
+
+// FindValid finds an unused, unexpired reset token by its hash
+func (r *PasswordResetRepository) FindValid(hash string) (*models.PasswordReset, error) {
+	var reset models.PasswordReset
+	if err := r.db.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, time.Now()).
+		First(&reset).Error; err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+// MarkUsed marks a reset token as used. It only succeeds once, so a token
+// submitted twice at the same time resets the password only once.
+func (r *PasswordResetRepository) MarkUsed(id uint) (bool, error) {
+	result := r.db.Model(&models.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
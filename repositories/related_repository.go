@@ -3,6 +3,7 @@ package repositories
 import (
 	"github.com/kampunk/api-siswa/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AlamatRepository handles address database operations
@@ -207,6 +208,48 @@ func (r *KepribadianRepository) FindBySiswaID(siswaID uint) ([]models.Kepribadia
 	return kepribadian, nil
 }
 
+func (r *KepribadianRepository) FindByID(id uint) (*models.Kepribadian, error) {
+	var kepribadian models.Kepribadian
+	if err := r.db.First(&kepribadian, id).Error; err != nil {
+		return nil, err
+	}
+	return &kepribadian, nil
+}
+
+func (r *KepribadianRepository) FindAll(page, pageSize int, filter map[string]interface{}) ([]models.Kepribadian, int64, error) {
+	var kepribadian []models.Kepribadian
+	var total int64
+
+	query := r.db.Model(&models.Kepribadian{})
+
+	// Apply filters
+	if val, ok := filter["siswa_id"].(uint); ok && val > 0 {
+		query = query.Where("siswa_id = ?", val)
+	}
+	if val, ok := filter["tahun_pelajaran"].(string); ok && val != "" {
+		query = query.Where("tahun_pelajaran = ?", val)
+	}
+	if val, ok := filter["aspek"].(string); ok && val != "" {
+		query = query.Where("aspek LIKE ?", "%"+val+"%")
+	}
+	if val, ok := filter["nilai"].(string); ok && val != "" {
+		query = query.Where("nilai = ?", val)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Siswa").Order("tahun_pelajaran DESC, id").Offset(offset).Limit(pageSize).Find(&kepribadian).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return kepribadian, total, nil
+}
+
 func (r *KepribadianRepository) Update(kepribadian *models.Kepribadian) error {
 	return r.db.Save(kepribadian).Error
 }
@@ -244,6 +287,40 @@ func (r *PrestasiRepository) FindByID(id uint) (*models.Prestasi, error) {
 	return &prestasi, nil
 }
 
+func (r *PrestasiRepository) FindAll(page, pageSize int, filter map[string]interface{}) ([]models.Prestasi, int64, error) {
+	var prestasi []models.Prestasi
+	var total int64
+
+	query := r.db.Model(&models.Prestasi{})
+
+	// Apply filters
+	if val, ok := filter["siswa_id"].(uint); ok && val > 0 {
+		query = query.Where("siswa_id = ?", val)
+	}
+	if val, ok := filter["bidang"].(string); ok && val != "" {
+		query = query.Where("bidang = ?", val)
+	}
+	if val, ok := filter["tingkat"].(string); ok && val != "" {
+		query = query.Where("tingkat = ?", val)
+	}
+	if val, ok := filter["tahun"].(uint); ok && val > 0 {
+		query = query.Where("tahun = ?", val)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Siswa").Order("tahun DESC, id").Offset(offset).Limit(pageSize).Find(&prestasi).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return prestasi, total, nil
+}
+
 func (r *PrestasiRepository) Update(prestasi *models.Prestasi) error {
 	return r.db.Save(prestasi).Error
 }
@@ -273,6 +350,45 @@ func (r *BeasiswaRepository) FindBySiswaID(siswaID uint) ([]models.Beasiswa, err
 	return beasiswa, nil
 }
 
+func (r *BeasiswaRepository) FindByID(id uint) (*models.Beasiswa, error) {
+	var beasiswa models.Beasiswa
+	if err := r.db.First(&beasiswa, id).Error; err != nil {
+		return nil, err
+	}
+	return &beasiswa, nil
+}
+
+func (r *BeasiswaRepository) FindAll(page, pageSize int, filter map[string]interface{}) ([]models.Beasiswa, int64, error) {
+	var beasiswa []models.Beasiswa
+	var total int64
+
+	query := r.db.Model(&models.Beasiswa{})
+
+	// Apply filters
+	if val, ok := filter["siswa_id"].(uint); ok && val > 0 {
+		query = query.Where("siswa_id = ?", val)
+	}
+	if val, ok := filter["tahun_pelajaran"].(string); ok && val != "" {
+		query = query.Where("tahun_pelajaran = ?", val)
+	}
+	if val, ok := filter["pemberi"].(string); ok && val != "" {
+		query = query.Where("pemberi LIKE ?", "%"+val+"%")
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Siswa").Order("tahun_pelajaran DESC, id").Offset(offset).Limit(pageSize).Find(&beasiswa).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return beasiswa, total, nil
+}
+
 func (r *BeasiswaRepository) Update(beasiswa *models.Beasiswa) error {
 	return r.db.Save(beasiswa).Error
 }
@@ -322,6 +438,17 @@ func (r *KehadiranRepository) FindBySiswaID(siswaID uint) ([]models.Kehadiran, e
 	return kehadiran, nil
 }
 
+func (r *KehadiranRepository) FindBySiswaIDs(siswaIDs []uint) ([]models.Kehadiran, error) {
+	var kehadiran []models.Kehadiran
+	if len(siswaIDs) == 0 {
+		return kehadiran, nil
+	}
+	if err := r.db.Where("siswa_id IN ?", siswaIDs).Order("siswa_id, kelas, semester").Find(&kehadiran).Error; err != nil {
+		return nil, err
+	}
+	return kehadiran, nil
+}
+
 func (r *KehadiranRepository) FindBySiswaIDAndKelas(siswaID uint, kelas string, semester uint8) (*models.Kehadiran, error) {
 	var kehadiran models.Kehadiran
 	if err := r.db.Where("siswa_id = ? AND kelas = ? AND semester = ?", siswaID, kelas, semester).First(&kehadiran).Error; err != nil {
@@ -334,6 +461,21 @@ func (r *KehadiranRepository) Update(kehadiran *models.Kehadiran) error {
 	return r.db.Save(kehadiran).Error
 }
 
+func (r *KehadiranRepository) Upsert(kehadiran *models.Kehadiran) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "siswa_id"}, {Name: "kelas"}, {Name: "semester"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"jumlah_hadir", "persentase_hadir", "jumlah_sakit",
+				"jumlah_izin", "jumlah_alpa", "jumlah_hari_efektif",
+			}),
+		}).Create(kehadiran).Error; err != nil {
+			return err
+		}
+		return syncKetidakhadiran(tx, kehadiran)
+	})
+}
+
 func (r *KehadiranRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Kehadiran{}, id).Error
 }
@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// RombelRepository handles class group database operations
+type RombelRepository struct {
+	db *gorm.DB
+}
+
+// NewRombelRepository creates a new RombelRepository
+func NewRombelRepository(db *gorm.DB) *RombelRepository {
+	return &RombelRepository{db: db}
+}
+
+// Create creates a new class group
+func (r *RombelRepository) Create(rombel *models.Rombel) error {
+	return r.db.Create(rombel).Error
+}
+
+// FindByID finds a class group by ID
+func (r *RombelRepository) FindByID(id uint) (*models.Rombel, error) {
+	var rombel models.Rombel
+	if err := r.db.Preload("WaliKelas").First(&rombel, id).Error; err != nil {
+		return nil, err
+	}
+	return &rombel, nil
+}
+
+// FindAll finds all class groups with pagination
+func (r *RombelRepository) FindAll(page, pageSize int, search string, filter map[string]interface{}) ([]models.Rombel, int64, error) {
+	var rombel []models.Rombel
+	var total int64
+
+	query := r.db.Model(&models.Rombel{}).Preload("WaliKelas")
+
+	// Search filter
+	if search != "" {
+		searchPattern := "%" + search + "%"
+		query = query.Where("nama LIKE ? OR jurusan LIKE ?", searchPattern, searchPattern)
+	}
+
+	// Apply filters
+	if val, ok := filter["tingkat"].(string); ok && val != "" {
+		query = query.Where("tingkat = ?", val)
+	}
+	if val, ok := filter["tahun_pelajaran"].(string); ok && val != "" {
+		query = query.Where("tahun_pelajaran = ?", val)
+	}
+	if val, ok := filter["wali_kelas_id"].(uint); ok && val > 0 {
+		query = query.Where("wali_kelas_id = ?", val)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * pageSize
+	if err := query.Order("tahun_pelajaran DESC, tingkat, nama").Offset(offset).Limit(pageSize).Find(&rombel).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rombel, total, nil
+}
+
+// Update updates a class group
+func (r *RombelRepository) Update(rombel *models.Rombel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("WaliKelas", "Anggota").Save(rombel).Error; err != nil {
+			return err
+		}
+		// Keep the denormalized academic year on enrollments in sync
+		return tx.Model(&models.AnggotaRombel{}).
+			Where("rombel_id = ?", rombel.ID).
+			Update("tahun_pelajaran", rombel.TahunPelajaran).Error
+	})
+}
+
+// Delete deletes a class group together with its enrollments
+func (r *RombelRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rombel_id = ?", id).Delete(&models.AnggotaRombel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Rombel{}, id).Error
+	})
+}
+
+// ExistsByNamaAndTahun checks if a class group name is already used in an academic year
+func (r *RombelRepository) ExistsByNamaAndTahun(nama, tahunPelajaran string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.Rombel{}).Where("nama = ? AND tahun_pelajaran = ?", nama, tahunPelajaran)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountAnggotaByRombelIDs counts members for each of the given class groups
+func (r *RombelRepository) CountAnggotaByRombelIDs(ids []uint) (map[uint]int64, error) {
+	result := make(map[uint]int64)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		RombelID uint
+		Total    int64
+	}
+	if err := r.db.Model(&models.AnggotaRombel{}).
+		Select("rombel_id, COUNT(*) AS total").
+		Where("rombel_id IN ?", ids).
+		Group("rombel_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.RombelID] = row.Total
+	}
+	return result, nil
+}
+
+// AddAnggota enrolls students in a class group
+func (r *RombelRepository) AddAnggota(anggota []models.AnggotaRombel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&anggota).Error
+	})
+}
+
+// RemoveAnggota removes a student from a class group
+func (r *RombelRepository) RemoveAnggota(rombelID, siswaID uint) (int64, error) {
+	result := r.db.Where("rombel_id = ? AND siswa_id = ?", rombelID, siswaID).Delete(&models.AnggotaRombel{})
+	return result.RowsAffected, result.Error
+}
+
+// FindAnggotaBySiswaAndTahun finds a student's enrollment for an academic year
+func (r *RombelRepository) FindAnggotaBySiswaAndTahun(siswaID uint, tahunPelajaran string) (*models.AnggotaRombel, error) {
+	var anggota models.AnggotaRombel
+	if err := r.db.Preload("Rombel").
+		Where("siswa_id = ? AND tahun_pelajaran = ?", siswaID, tahunPelajaran).
+		First(&anggota).Error; err != nil {
+		return nil, err
+	}
+	return &anggota, nil
+}
+
+// FindAnggotaBySiswaID finds all enrollments of a student
+func (r *RombelRepository) FindAnggotaBySiswaID(siswaID uint) ([]models.AnggotaRombel, error) {
+	var anggota []models.AnggotaRombel
+	if err := r.db.Preload("Rombel").
+		Where("siswa_id = ?", siswaID).
+		Order("tahun_pelajaran").
+		Find(&anggota).Error; err != nil {
+		return nil, err
+	}
+	return anggota, nil
+}
+
+// FindEnrolledSiswaIDs returns the subset of students already enrolled in an academic year
+func (r *RombelRepository) FindEnrolledSiswaIDs(siswaIDs []uint, tahunPelajaran string) ([]uint, error) {
+	var ids []uint
+	if len(siswaIDs) == 0 {
+		return ids, nil
+	}
+	if err := r.db.Model(&models.AnggotaRombel{}).
+		Where("siswa_id IN ? AND tahun_pelajaran = ?", siswaIDs, tahunPelajaran).
+		Pluck("siswa_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindAnggotaByTahun finds all enrollments of an academic year, optionally limited to one class group
+func (r *RombelRepository) FindAnggotaByTahun(tahunPelajaran string, rombelID uint) ([]models.AnggotaRombel, error) {
+	var anggota []models.AnggotaRombel
+	query := r.db.Preload("Rombel").Preload("Siswa").
+		Joins("JOIN rombel ON rombel.id = anggota_rombel.rombel_id").
+		Where("anggota_rombel.tahun_pelajaran = ?", tahunPelajaran)
+	if rombelID > 0 {
+		query = query.Where("anggota_rombel.rombel_id = ?", rombelID)
+	}
+	if err := query.Order("rombel.tingkat, rombel.nama, anggota_rombel.siswa_id").Find(&anggota).Error; err != nil {
+		return nil, err
+	}
+	return anggota, nil
+}
+
+// FindSiswaIDsByRombelID returns the IDs of all students enrolled in a class group
+func (r *RombelRepository) FindSiswaIDsByRombelID(rombelID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.AnggotaRombel{}).
+		Where("rombel_id = ?", rombelID).
+		Pluck("siswa_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// waliKelasAnggota selects the enrollments of the active academic year in the
+// class groups whose homeroom teacher is the user
+func (r *RombelRepository) waliKelasAnggota(userID uint) *gorm.DB {
+	return r.db.Model(&models.AnggotaRombel{}).
+		Joins("JOIN rombel ON rombel.id = anggota_rombel.rombel_id").
+		Where("rombel.wali_kelas_id = ?", userID).
+		Where("anggota_rombel.tahun_pelajaran IN (?)", r.db.Model(&models.TahunPelajaran{}).Select("label").Where("is_active = ?", true))
+}
+
+// IsWaliKelasSiswa checks that a student is in the user's class group of the
+// active academic year
+func (r *RombelRepository) IsWaliKelasSiswa(userID, siswaID uint) (bool, error) {
+	var count int64
+	if err := r.waliKelasAnggota(userID).Where("anggota_rombel.siswa_id = ?", siswaID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsWaliKelasCatatan checks that a semester note belongs to a student in the
+// user's class group of the active academic year
+func (r *RombelRepository) IsWaliKelasCatatan(userID, catatanID uint) (bool, error) {
+	return r.IsWaliKelasRecord(userID, &models.CatatanAkhirSemester{}, catatanID)
+}
+
+// IsWaliKelasRecord checks that a record of a table with a siswa_id column
+// belongs to a student in the user's class group of the active academic year
+func (r *RombelRepository) IsWaliKelasRecord(userID uint, model interface{}, id uint) (bool, error) {
+	return r.isWaliKelasSiswaIn(userID, r.db.Model(model).Select("siswa_id").Where("id = ?", id))
+}
+
+// IsWaliKelasCatatanRecord checks that a record attached to a semester note
+// through catatan_id belongs to a student in the user's class group of the
+// active academic year
+func (r *RombelRepository) IsWaliKelasCatatanRecord(userID uint, model interface{}, id uint) (bool, error) {
+	return r.isWaliKelasSiswaIn(userID, r.db.Model(&models.CatatanAkhirSemester{}).Select("siswa_id").
+		Where("id IN (?)", r.db.Model(model).Select("catatan_id").Where("id = ?", id)))
+}
+
+// IsWaliKelasRiwayatPenyakit checks that an illness record belongs to a student
+// in the user's class group of the active academic year
+func (r *RombelRepository) IsWaliKelasRiwayatPenyakit(userID, riwayatID uint) (bool, error) {
+	return r.isWaliKelasSiswaIn(userID, r.db.Model(&models.KesehatanSiswa{}).Select("siswa_id").
+		Where("id IN (?)", r.db.Model(&models.RiwayatPenyakit{}).Select("kesehatan_id").Where("id = ?", riwayatID)))
+}
+
+// isWaliKelasSiswaIn checks that a student selected by a subquery is in the
+// user's class group of the active academic year
+func (r *RombelRepository) isWaliKelasSiswaIn(userID uint, siswaIDs *gorm.DB) (bool, error) {
+	var count int64
+	if err := r.waliKelasAnggota(userID).
+		Where("anggota_rombel.siswa_id IN (?)", siswaIDs).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsWaliKelasRombel checks that the user is the homeroom teacher of a class
+// group of the active academic year
+func (r *RombelRepository) IsWaliKelasRombel(userID, rombelID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Rombel{}).
+		Where("id = ? AND wali_kelas_id = ?", rombelID, userID).
+		Where("tahun_pelajaran IN (?)", r.db.Model(&models.TahunPelajaran{}).Select("label").Where("is_active = ?", true)).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// SessionRepository handles login session database operations
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new SessionRepository
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create creates a new session
+func (r *SessionRepository) Create(session *models.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByRefreshHash finds a session by the hash of its current refresh token
+func (r *SessionRepository) FindByRefreshHash(hash string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("refresh_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByPreviousHash finds a session by the hash of its already rotated refresh token
+func (r *SessionRepository) FindByPreviousHash(hash string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("previous_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate replaces the refresh token of a session and extends its expiry. It
+// only succeeds while the session still holds oldHash, so of two concurrent
+// refreshes with the same token only one wins.
+func (r *SessionRepository) Rotate(sessionID uint, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.UserSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", sessionID, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": oldHash,
+			"expires_at":          expiresAt,
+			"last_used_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Revoke revokes a session
+func (r *SessionRepository) Revoke(sessionID uint) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllByUserID revokes every active session of a user and returns how many
+// were revoked
+func (r *SessionRepository) RevokeAllByUserID(userID uint) (int64, error) {
+	result := r.db.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// RevokeOthers revokes every active session of a user except one, such as the
+// session a password was just changed from
+func (r *SessionRepository) RevokeOthers(userID, keepSessionID uint) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// DeleteExpired deletes the expired and revoked sessions of a user
+func (r *SessionRepository) DeleteExpired(userID uint) error {
+	return r.db.Where("user_id = ? AND (expires_at <= ? OR revoked_at IS NOT NULL)", userID, time.Now()).
+		Delete(&models.UserSession{}).Error
+}
+
+// FindActiveUser finds the user of a session that is neither revoked nor
+// expired, provided the user is still active
+func (r *SessionRepository) FindActiveUser(sessionID, userID uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Joins("JOIN user_sessions ON user_sessions.user_id = users.id").
+		Where("user_sessions.id = ? AND users.id = ?", sessionID, userID).
+		Where("user_sessions.revoked_at IS NULL AND user_sessions.expires_at > ?", time.Now()).
+		Where("users.is_active = ?", true).
+		First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
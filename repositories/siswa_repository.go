@@ -3,8 +3,16 @@ package repositories
 import (
 	"github.com/kampunk/api-siswa/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// JumlahSiswaJurusan holds the student count of a kompetensi keahlian and gender
+type JumlahSiswaJurusan struct {
+	KompetensiKeahlianID *uint
+	JenisKelamin         string
+	Jumlah               int64
+}
+
 // SiswaRepository handles student database operations
 type SiswaRepository struct {
 	db *gorm.DB
@@ -20,6 +28,19 @@ func (r *SiswaRepository) Create(siswa *models.Siswa) error {
 	return r.db.Create(siswa).Error
 }
 
+// CreateBatch creates students with their address, parents and guardian in one
+// transaction, so either every student is saved or none is
+func (r *SiswaRepository) CreateBatch(siswa []*models.Siswa) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, s := range siswa {
+			if err := tx.Create(s).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // FindByID finds a student by ID with all related data
 func (r *SiswaRepository) FindByID(id uint) (*models.Siswa, error) {
 	var siswa models.Siswa
@@ -33,6 +54,7 @@ func (r *SiswaRepository) FindByID(id uint) (*models.Siswa, error) {
 func (r *SiswaRepository) FindByIDWithRelations(id uint) (*models.Siswa, error) {
 	var siswa models.Siswa
 	if err := r.db.
+		Preload("KompetensiKeahlian.ProgramKeahlian.BidangKeahlian").
 		Preload("Alamat").
 		Preload("OrangTua").
 		Preload("Wali").
@@ -54,6 +76,9 @@ func (r *SiswaRepository) FindByIDWithRelations(id uint) (*models.Siswa, error)
 		Preload("NilaiIjazah").
 		Preload("NilaiIjazah.MataPelajaran").
 		Preload("MeninggalkanSekolah").
+		Preload("KenaikanKelas", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tahun_pelajaran")
+		}).
 		First(&siswa, id).Error; err != nil {
 		return nil, err
 	}
@@ -79,18 +104,11 @@ func (r *SiswaRepository) FindByNoInduk(noInduk string) (*models.Siswa, error) {
 }
 
 // FindAll finds all students with pagination
-func (r *SiswaRepository) FindAll(page, pageSize int, search, sortBy, sortDir string) ([]models.Siswa, int64, error) {
+func (r *SiswaRepository) FindAll(page, pageSize int, search, sortBy, sortDir string, filter map[string]interface{}) ([]models.Siswa, int64, error) {
 	var siswa []models.Siswa
 	var total int64
 
-	query := r.db.Model(&models.Siswa{})
-
-	// Search filter
-	if search != "" {
-		searchPattern := "%" + search + "%"
-		query = query.Where("nama_lengkap LIKE ? OR nisn LIKE ? OR no_induk LIKE ?",
-			searchPattern, searchPattern, searchPattern)
-	}
+	query := r.applyFilter(r.db.Model(&models.Siswa{}), search, filter)
 
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
@@ -108,18 +126,138 @@ func (r *SiswaRepository) FindAll(page, pageSize int, search, sortBy, sortDir st
 
 	// Pagination
 	offset := (page - 1) * pageSize
-	if err := query.Offset(offset).Limit(pageSize).Find(&siswa).Error; err != nil {
+	if err := query.Preload("KompetensiKeahlian").Offset(offset).Limit(pageSize).Find(&siswa).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return siswa, total, nil
 }
 
+// FindAllWithRelations finds all students matching the search and filters of
+// FindAll, ordered by name, with address, parents, guardian and previous education
+func (r *SiswaRepository) FindAllWithRelations(search string, filter map[string]interface{}) ([]models.Siswa, error) {
+	var siswa []models.Siswa
+	if err := r.applyFilter(r.db.Model(&models.Siswa{}), search, filter).
+		Preload("Alamat").
+		Preload("OrangTua").
+		Preload("Wali").
+		Preload("PendidikanSebelumnya", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tanggal_diterima")
+		}).
+		Order("nama_lengkap").
+		Find(&siswa).Error; err != nil {
+		return nil, err
+	}
+	return siswa, nil
+}
+
+// FindByNISNsWithRelations finds students by NISN with address, parents,
+// guardian and previous education
+func (r *SiswaRepository) FindByNISNsWithRelations(nisn []string) ([]models.Siswa, error) {
+	var siswa []models.Siswa
+	if len(nisn) == 0 {
+		return siswa, nil
+	}
+	if err := r.db.
+		Preload("Alamat").
+		Preload("OrangTua").
+		Preload("Wali").
+		Preload("PendidikanSebelumnya", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tanggal_diterima")
+		}).
+		Where("nisn IN ?", nisn).
+		Find(&siswa).Error; err != nil {
+		return nil, err
+	}
+	return siswa, nil
+}
+
+// CountByKompetensiKeahlian counts students per kompetensi keahlian and gender,
+// using the same search and filters as FindAll
+func (r *SiswaRepository) CountByKompetensiKeahlian(search string, filter map[string]interface{}) ([]JumlahSiswaJurusan, error) {
+	var rows []JumlahSiswaJurusan
+	if err := r.applyFilter(r.db.Model(&models.Siswa{}), search, filter).
+		Select("kompetensi_keahlian_id, jenis_kelamin, COUNT(*) AS jumlah").
+		Group("kompetensi_keahlian_id, jenis_kelamin").
+		Order("kompetensi_keahlian_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// applyFilter applies the student list search and filters to a query
+func (r *SiswaRepository) applyFilter(query *gorm.DB, search string, filter map[string]interface{}) *gorm.DB {
+	// Search filter
+	if search != "" {
+		searchPattern := "%" + search + "%"
+		query = query.Where("nama_lengkap LIKE ? OR nisn LIKE ? OR no_induk LIKE ?",
+			searchPattern, searchPattern, searchPattern)
+	}
+
+	// Apply filters
+	if val, ok := filter["rombel_id"].(uint); ok && val > 0 {
+		query = query.Where("id IN (?)", r.db.Model(&models.AnggotaRombel{}).Select("siswa_id").Where("rombel_id = ?", val))
+	}
+	if val, ok := filter["status"].(string); ok && val != "" {
+		keluar := r.db.Model(&models.MeninggalkanSekolah{}).Select("siswa_id")
+		switch val {
+		case "aktif":
+			query = query.Where("id NOT IN (?)", keluar)
+		case "tamat", "pindah", "putus":
+			query = query.Where("id IN (?)", keluar.Where("tipe = ?", val))
+		}
+	}
+	if val, ok := filter["kompetensi_keahlian_id"].(uint); ok && val > 0 {
+		query = query.Where("kompetensi_keahlian_id = ?", val)
+	}
+	if val, ok := filter["program_keahlian_id"].(uint); ok && val > 0 {
+		query = query.Where("kompetensi_keahlian_id IN (?)", r.db.Model(&models.KompetensiKeahlian{}).Select("id").Where("program_keahlian_id = ?", val))
+	}
+	if val, ok := filter["bidang_keahlian_id"].(uint); ok && val > 0 {
+		programIDs := r.db.Model(&models.ProgramKeahlian{}).Select("id").Where("bidang_keahlian_id = ?", val)
+		query = query.Where("kompetensi_keahlian_id IN (?)", r.db.Model(&models.KompetensiKeahlian{}).Select("id").Where("program_keahlian_id IN (?)", programIDs))
+	}
+
+	return query
+}
+
 // Update updates a student
 func (r *SiswaRepository) Update(siswa *models.Siswa) error {
 	return r.db.Save(siswa).Error
 }
 
+// UpdateBatchWithRelations saves students together with their address, parents
+// and guardian in one transaction; relations without an ID are created
+func (r *SiswaRepository) UpdateBatchWithRelations(siswa []*models.Siswa) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, s := range siswa {
+			if err := tx.Omit(clause.Associations).Save(s).Error; err != nil {
+				return err
+			}
+			if s.Alamat != nil {
+				s.Alamat.SiswaID = s.ID
+				if err := tx.Save(s.Alamat).Error; err != nil {
+					return err
+				}
+			}
+			for i := range s.OrangTua {
+				s.OrangTua[i].SiswaID = s.ID
+				if err := tx.Save(&s.OrangTua[i]).Error; err != nil {
+					return err
+				}
+			}
+			if s.Wali != nil {
+				s.Wali.SiswaID = s.ID
+				if err := tx.Save(s.Wali).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
 // Delete soft deletes a student
 func (r *SiswaRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Siswa{}, id).Error
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SkemaPenilaianRepository handles grading scheme database operations
+type SkemaPenilaianRepository struct {
+	db *gorm.DB
+}
+
+// NewSkemaPenilaianRepository creates a new SkemaPenilaianRepository
+func NewSkemaPenilaianRepository(db *gorm.DB) *SkemaPenilaianRepository {
+	return &SkemaPenilaianRepository{db: db}
+}
+
+// Create creates a new grading scheme
+func (r *SkemaPenilaianRepository) Create(skema *models.SkemaPenilaian) error {
+	return r.db.Create(skema).Error
+}
+
+// FindByID finds a grading scheme by ID
+func (r *SkemaPenilaianRepository) FindByID(id uint) (*models.SkemaPenilaian, error) {
+	var skema models.SkemaPenilaian
+	if err := r.db.Preload("MataPelajaran").First(&skema, id).Error; err != nil {
+		return nil, err
+	}
+	return &skema, nil
+}
+
+// FindAll finds grading schemes, optionally filtered by academic year and subject
+func (r *SkemaPenilaianRepository) FindAll(filter map[string]interface{}) ([]models.SkemaPenilaian, error) {
+	var skema []models.SkemaPenilaian
+	query := r.db.Preload("MataPelajaran")
+
+	if val, ok := filter["tahun_pelajaran"].(string); ok && val != "" {
+		query = query.Where("tahun_pelajaran = ?", val)
+	}
+	if val, ok := filter["mata_pelajaran_id"].(uint); ok && val > 0 {
+		query = query.Where("mata_pelajaran_id = ?", val)
+	}
+
+	if err := query.Order("tahun_pelajaran DESC, mata_pelajaran_id IS NOT NULL, mata_pelajaran_id").Find(&skema).Error; err != nil {
+		return nil, err
+	}
+	return skema, nil
+}
+
+// ExistsByTahunAndMapel checks if a scheme is already defined for an academic
+// year and subject. A nil subject checks the year's default scheme.
+func (r *SkemaPenilaianRepository) ExistsByTahunAndMapel(tahunPelajaran string, mataPelajaranID *uint, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.SkemaPenilaian{}).Where("tahun_pelajaran = ?", tahunPelajaran)
+	if mataPelajaranID == nil {
+		query = query.Where("mata_pelajaran_id IS NULL")
+	} else {
+		query = query.Where("mata_pelajaran_id = ?", *mataPelajaranID)
+	}
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindForMataPelajaran finds the scheme that applies to a subject in an academic
+// year, preferring the subject's own scheme over the year's default
+func (r *SkemaPenilaianRepository) FindForMataPelajaran(tahunPelajaran string, mataPelajaranID uint) (*models.SkemaPenilaian, error) {
+	var skema models.SkemaPenilaian
+	if err := r.db.
+		Where("tahun_pelajaran = ? AND (mata_pelajaran_id = ? OR mata_pelajaran_id IS NULL)", tahunPelajaran, mataPelajaranID).
+		Order("mata_pelajaran_id IS NULL").
+		First(&skema).Error; err != nil {
+		return nil, err
+	}
+	return &skema, nil
+}
+
+// Update updates a grading scheme
+func (r *SkemaPenilaianRepository) Update(skema *models.SkemaPenilaian) error {
+	return r.db.Omit("MataPelajaran").Save(skema).Error
+}
+
+// Delete deletes a grading scheme
+func (r *SkemaPenilaianRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SkemaPenilaian{}, id).Error
+}
+
+// HitungUlangPredikat recomputes the predikat of every semester grade covered
+// by a scheme and returns the number of grades that changed. A default scheme
+// skips subjects that have their own scheme in the same academic year.
+func (r *SkemaPenilaianRepository) HitungUlangPredikat(skema *models.SkemaPenilaian) (int64, error) {
+	query := r.db.Model(&models.NilaiSemester{}).Where("tahun_pelajaran = ?", skema.TahunPelajaran)
+	if skema.MataPelajaranID != nil {
+		query = query.Where("mata_pelajaran_id = ?", *skema.MataPelajaranID)
+	} else {
+		query = query.Where("mata_pelajaran_id NOT IN (?)",
+			r.db.Model(&models.SkemaPenilaian{}).
+				Select("mata_pelajaran_id").
+				Where("tahun_pelajaran = ? AND mata_pelajaran_id IS NOT NULL", skema.TahunPelajaran))
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"predikat_pengetahuan":  predikatExpr("nilai_pengetahuan", skema),
+		"predikat_keterampilan": predikatExpr("nilai_keterampilan", skema),
+	})
+	return result.RowsAffected, result.Error
+}
+
+// predikatExpr builds the SQL CASE that maps a score column to its predikat
+func predikatExpr(column string, skema *models.SkemaPenilaian) clause.Expr {
+	return gorm.Expr(
+		"CASE WHEN "+column+" >= ? THEN 'A' WHEN "+column+" >= ? THEN 'B' WHEN "+column+" >= ? THEN 'C' ELSE 'D' END",
+		skema.BatasA, skema.BatasB, skema.BatasC,
+	)
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// JumlahStatistik holds the count of one group of a statistic
+type JumlahStatistik struct {
+	Kunci  string
+	Jumlah int64
+}
+
+// JumlahStatistikTahun holds the count of one year and type of a statistic
+type JumlahStatistikTahun struct {
+	Tahun  int
+	Tipe   string
+	Jumlah int64
+}
+
+// RataRataKehadiran holds the attendance averages of one kelas and semester
+type RataRataKehadiran struct {
+	Kelas           string
+	Semester        uint8
+	JumlahSiswa     int64
+	PersentaseHadir float64
+	Sakit           float64
+	Izin            float64
+	Alpa            float64
+}
+
+// StatistikRepository runs the grouped queries of the dashboard statistics
+type StatistikRepository struct {
+	db *gorm.DB
+}
+
+func NewStatistikRepository(db *gorm.DB) *StatistikRepository {
+	return &StatistikRepository{db: db}
+}
+
+// siswaAktif selects students that have not left school
+func (r *StatistikRepository) siswaAktif() *gorm.DB {
+	return r.db.Model(&models.Siswa{}).
+		Where("siswa.id NOT IN (?)", r.db.Model(&models.MeninggalkanSekolah{}).Select("siswa_id"))
+}
+
+// CountAktif counts the active students
+func (r *StatistikRepository) CountAktif() (int64, error) {
+	var count int64
+	err := r.siswaAktif().Count(&count).Error
+	return count, err
+}
+
+// CountAktifBy counts active students grouped by a column of siswa, such as
+// jenis_kelamin or agama
+func (r *StatistikRepository) CountAktifBy(kolom string) ([]JumlahStatistik, error) {
+	var rows []JumlahStatistik
+	if err := r.siswaAktif().
+		Select(kolom + " AS kunci, COUNT(*) AS jumlah").
+		Group(kolom).
+		Order(kolom).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountAktifByTingkat counts active students per tingkat of their class group
+// in an academic year
+func (r *StatistikRepository) CountAktifByTingkat(tahunPelajaran string) ([]JumlahStatistik, error) {
+	var rows []JumlahStatistik
+	if err := r.siswaAktif().
+		Select("rombel.tingkat AS kunci, COUNT(*) AS jumlah").
+		Joins("JOIN anggota_rombel ON anggota_rombel.siswa_id = siswa.id").
+		Joins("JOIN rombel ON rombel.id = anggota_rombel.rombel_id").
+		Where("anggota_rombel.tahun_pelajaran = ?", tahunPelajaran).
+		Group("rombel.tingkat").
+		Order("rombel.tingkat").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountPenerimaanPerTahun counts admissions per year of tanggal_diterima and
+// type (siswa_baru, pindahan)
+func (r *StatistikRepository) CountPenerimaanPerTahun() ([]JumlahStatistikTahun, error) {
+	var rows []JumlahStatistikTahun
+	if err := r.db.Model(&models.PendidikanSebelumnya{}).
+		Select("YEAR(pendidikan_sebelumnya.tanggal_diterima) AS tahun, pendidikan_sebelumnya.tipe AS tipe, COUNT(*) AS jumlah").
+		Joins("JOIN siswa ON siswa.id = pendidikan_sebelumnya.siswa_id AND siswa.deleted_at IS NULL").
+		Group("YEAR(pendidikan_sebelumnya.tanggal_diterima), pendidikan_sebelumnya.tipe").
+		Order("tahun, tipe").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountKeluarPerTahun counts students who left school per year and type
+// (tamat, pindah, putus)
+func (r *StatistikRepository) CountKeluarPerTahun() ([]JumlahStatistikTahun, error) {
+	var rows []JumlahStatistikTahun
+	if err := r.db.Model(&models.MeninggalkanSekolah{}).
+		Select("YEAR(meninggalkan_sekolah.tanggal) AS tahun, meninggalkan_sekolah.tipe AS tipe, COUNT(*) AS jumlah").
+		Joins("JOIN siswa ON siswa.id = meninggalkan_sekolah.siswa_id AND siswa.deleted_at IS NULL").
+		Group("YEAR(meninggalkan_sekolah.tanggal), meninggalkan_sekolah.tipe").
+		Order("tahun, tipe").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// AverageKehadiran averages the semester attendance summaries per kelas and
+// semester
+func (r *StatistikRepository) AverageKehadiran() ([]RataRataKehadiran, error) {
+	var rows []RataRataKehadiran
+	if err := r.db.Model(&models.Kehadiran{}).
+		Select("kehadiran.kelas, kehadiran.semester, COUNT(*) AS jumlah_siswa, " +
+			"AVG(kehadiran.persentase_hadir) AS persentase_hadir, AVG(kehadiran.jumlah_sakit) AS sakit, " +
+			"AVG(kehadiran.jumlah_izin) AS izin, AVG(kehadiran.jumlah_alpa) AS alpa").
+		Joins("JOIN siswa ON siswa.id = kehadiran.siswa_id AND siswa.deleted_at IS NULL").
+		Group("kehadiran.kelas, kehadiran.semester").
+		Order("kehadiran.kelas, kehadiran.semester").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountPrestasiByTingkat counts achievements per competition level
+func (r *StatistikRepository) CountPrestasiByTingkat() ([]JumlahStatistik, error) {
+	var rows []JumlahStatistik
+	if err := r.db.Model(&models.Prestasi{}).
+		Select("COALESCE(prestasi.tingkat, '') AS kunci, COUNT(*) AS jumlah").
+		Joins("JOIN siswa ON siswa.id = prestasi.siswa_id AND siswa.deleted_at IS NULL").
+		Group("prestasi.tingkat").
+		Order("prestasi.tingkat").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+)
+
+// TahunPelajaranRepository handles academic year database operations
+type TahunPelajaranRepository struct {
+	db *gorm.DB
+}
+
+// NewTahunPelajaranRepository creates a new TahunPelajaranRepository
+func NewTahunPelajaranRepository(db *gorm.DB) *TahunPelajaranRepository {
+	return &TahunPelajaranRepository{db: db}
+}
+
+// Create creates a new academic year
+func (r *TahunPelajaranRepository) Create(tahun *models.TahunPelajaran) error {
+	return r.db.Create(tahun).Error
+}
+
+// FindByID finds an academic year by ID
+func (r *TahunPelajaranRepository) FindByID(id uint) (*models.TahunPelajaran, error) {
+	var tahun models.TahunPelajaran
+	if err := r.db.First(&tahun, id).Error; err != nil {
+		return nil, err
+	}
+	return &tahun, nil
+}
+
+// FindByLabel finds an academic year by its label (e.g. 2024/2025)
+func (r *TahunPelajaranRepository) FindByLabel(label string) (*models.TahunPelajaran, error) {
+	var tahun models.TahunPelajaran
+	if err := r.db.Where("label = ?", label).First(&tahun).Error; err != nil {
+		return nil, err
+	}
+	return &tahun, nil
+}
+
+// FindActive finds the currently active academic year
+func (r *TahunPelajaranRepository) FindActive() (*models.TahunPelajaran, error) {
+	var tahun models.TahunPelajaran
+	if err := r.db.Where("is_active = ?", true).First(&tahun).Error; err != nil {
+		return nil, err
+	}
+	return &tahun, nil
+}
+
+// FindAll finds all academic years, newest first
+func (r *TahunPelajaranRepository) FindAll() ([]models.TahunPelajaran, error) {
+	var tahun []models.TahunPelajaran
+	if err := r.db.Order("tanggal_mulai DESC").Find(&tahun).Error; err != nil {
+		return nil, err
+	}
+	return tahun, nil
+}
+
+// ExistsByLabel checks if a label is already used by another academic year
+func (r *TahunPelajaranRepository) ExistsByLabel(label string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.TahunPelajaran{}).Where("label = ?", label)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsInUse checks if any record still references the academic year label
+func (r *TahunPelajaranRepository) IsInUse(label string) (bool, error) {
+	for _, model := range []interface{}{
+		&models.NilaiSemester{},
+		&models.Kepribadian{},
+		&models.Beasiswa{},
+		&models.Rombel{},
+		&models.SkemaPenilaian{},
+	} {
+		var count int64
+		if err := r.db.Model(model).Where("tahun_pelajaran = ?", label).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Update updates an academic year
+func (r *TahunPelajaranRepository) Update(tahun *models.TahunPelajaran) error {
+	return r.db.Save(tahun).Error
+}
+
+// Activate marks an academic year as the only active one
+func (r *TahunPelajaranRepository) Activate(id uint, semester uint8) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TahunPelajaran{}).
+			Where("is_active = ? AND id != ?", true, id).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TahunPelajaran{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"is_active": true, "semester_aktif": semester}).Error
+	})
+}
+
+// Delete deletes an academic year
+func (r *TahunPelajaranRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TahunPelajaran{}, id).Error
+}
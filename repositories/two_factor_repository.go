@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/kampunk/api-siswa/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TwoFactorRepository handles TOTP enrolment, recovery codes and the per-role
+// two-factor policy
+type TwoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new TwoFactorRepository
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SetSecret stores the secret of a pending enrolment
+func (r *TwoFactorRepository) SetSecret(userID uint, secret string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":       secret,
+		"totp_enabled":      false,
+		"totp_last_counter": 0,
+	}).Error
+}
+
+// Enable turns on two-factor authentication with its first recovery codes
+func (r *TwoFactorRepository) Enable(userID uint, counter int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_enabled":      true,
+			"totp_last_counter": counter,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Disable turns off two-factor authentication and drops the secret and
+// recovery codes
+func (r *TwoFactorRepository) Disable(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":       "",
+			"totp_enabled":      false,
+			"totp_last_counter": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// UseCounter records the time step of an accepted code. It only succeeds while
+// the stored step is still lastCounter, so a code is accepted once even when
+// submitted twice at the same time.
+func (r *TwoFactorRepository) UseCounter(userID uint, lastCounter, counter int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_counter = ?", userID, lastCounter).
+		Update("totp_last_counter", counter)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes replaces the recovery codes of a user
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if err := tx.Create(&models.RecoveryCode{UserID: userID, CodeHash: hash}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used
+func (r *TwoFactorRepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Limit(1).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountRecoveryCodes counts the unused recovery codes of a user
+func (r *TwoFactorRepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindPolicies finds the policies of every role that has one
+func (r *TwoFactorRepository) FindPolicies() ([]models.RolePolicy, error) {
+	var policies []models.RolePolicy
+	if err := r.db.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SetRequired sets whether a role requires two-factor authentication
+func (r *TwoFactorRepository) SetRequired(role string, required bool) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&models.RolePolicy{Role: role, RequireTwoFactor: required}).Error
+}
+
+// IsRequired checks whether any of the roles requires two-factor authentication
+func (r *TwoFactorRepository) IsRequired(roles []string) (bool, error) {
+	if len(roles) == 0 {
+		return false, nil
+	}
+	var count int64
+	if err := r.db.Model(&models.RolePolicy{}).
+		Where("role IN ? AND require_two_factor = ?", roles, true).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
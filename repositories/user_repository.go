@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/kampunk/api-siswa/models"
 	"gorm.io/gorm"
 )
@@ -18,7 +20,7 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 // FindByUsername finds a user by username
 func (r *UserRepository) FindByUsername(username string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := r.db.Preload("Roles").Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -36,22 +38,112 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 // FindByID finds a user by ID
 func (r *UserRepository) FindByID(id uint) (*models.User, error) {
 	var user models.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := r.db.Preload("Roles").Preload("PortalLinks").First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-// Create creates a new user
+// FindAll finds all users with their roles, optionally only those with a role
+func (r *UserRepository) FindAll(search, role string) ([]models.User, error) {
+	var users []models.User
+	query := r.db.Preload("Roles").Preload("PortalLinks")
+	if search != "" {
+		searchPattern := "%" + search + "%"
+		query = query.Where("username LIKE ? OR email LIKE ?", searchPattern, searchPattern)
+	}
+	if role != "" {
+		query = query.Where("id IN (?)", r.db.Model(&models.UserRole{}).Select("user_id").Where("role = ?", role))
+	}
+	if err := query.Order("username").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Create creates a new user together with its roles and portal links
 func (r *UserRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
 
+// SetRoles replaces the roles of a user
+func (r *UserRepository) SetRoles(userID uint, roles []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		for _, role := range roles {
+			if err := tx.Create(&models.UserRole{UserID: userID, Role: role}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountActiveByRole counts active users having a role, optionally excluding one user
+func (r *UserRepository) CountActiveByRole(role string, excludeID uint) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.User{}).
+		Where("is_active = ?", true).
+		Where("id IN (?)", r.db.Model(&models.UserRole{}).Select("user_id").Where("role = ?", role))
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // Update updates a user
 func (r *UserRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdatePassword sets a new password hash and whether the user must change it
+// at their next login
+func (r *UserRepository) UpdatePassword(userID uint, passwordHash string, mustChange bool) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"password_hash":        passwordHash,
+		"must_change_password": mustChange,
+		"password_changed_at":  time.Now(),
+	}).Error
+}
+
+// RecordLoginFailure counts a failed login and returns the new count. The
+// account is locked until lockedUntil in the same UPDATE once the count reaches
+// lockAfter (0 never locks), so parallel failures cannot skip the lockout.
+func (r *UserRepository) RecordLoginFailure(userID uint, lockAfter int, lockedUntil time.Time) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// MySQL assigns SET columns left to right, so locked_until comes first
+		// to see the count before the increment
+		if err := tx.Exec(`UPDATE users SET
+			locked_until = CASE WHEN ? > 0 AND failed_login_count + 1 >= ? THEN ? ELSE locked_until END,
+			failed_login_count = failed_login_count + 1,
+			last_failed_login_at = ?
+			WHERE id = ?`, lockAfter, lockAfter, lockedUntil, time.Now(), userID).Error; err != nil {
+			return err
+		}
+		// The row stays locked until commit, so this reads our own increment
+		return tx.Model(&models.User{}).Select("failed_login_count").Where("id = ?", userID).Scan(&count).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ResetLoginFailures clears the failed login count and lock of a user
+func (r *UserRepository) ResetLoginFailures(userID uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"failed_login_count":   0,
+		"last_failed_login_at": nil,
+		"locked_until":         nil,
+	}).Error
+}
+
 // ExistsByUsername checks if username exists
 func (r *UserRepository) ExistsByUsername(username string) (bool, error) {
 	var count int64
@@ -69,3 +161,42 @@ func (r *UserRepository) ExistsByEmail(email string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// SetPortalLinks replaces the students a portal account is linked to
+func (r *UserRepository) SetPortalLinks(userID uint, links []models.PortalLink) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.PortalLink{}).Error; err != nil {
+			return err
+		}
+		for i := range links {
+			links[i].UserID = userID
+			if err := tx.Create(&links[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindPortalLinks finds the students a portal account is linked to
+func (r *UserRepository) FindPortalLinks(userID uint) ([]models.PortalLink, error) {
+	var links []models.PortalLink
+	if err := r.db.Preload("Siswa").Preload("OrangTua").Preload("Wali").
+		Where("user_id = ?", userID).
+		Order("siswa_id").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// IsPortalSiswa checks that a portal account is linked to a student
+func (r *UserRepository) IsPortalSiswa(userID, siswaID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.PortalLink{}).
+		Where("user_id = ? AND siswa_id = ?", userID, siswaID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -1,14 +1,18 @@
 package routes
 
 import (
+	"log"
 	"time"
 
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
+	"github.com/kampunk/api-siswa/configs"
 	"github.com/kampunk/api-siswa/handlers"
 	"github.com/kampunk/api-siswa/middlewares"
+	"github.com/kampunk/api-siswa/models"
 	"github.com/kampunk/api-siswa/repositories"
 	"github.com/kampunk/api-siswa/services"
+	"github.com/kampunk/api-siswa/utils"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
@@ -42,24 +46,97 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 	ijazahRepo := repositories.NewNilaiIjazahRepository(db)
 	kehadiranRepo := repositories.NewKehadiranRepository(db)
 	pendidikanRepo := repositories.NewPendidikanRepository(db)
+	rombelRepo := repositories.NewRombelRepository(db)
+	tahunPelajaranRepo := repositories.NewTahunPelajaranRepository(db)
+	kenaikanKelasRepo := repositories.NewKenaikanKelasRepository(db)
+	meninggalkanSekolahRepo := repositories.NewMeninggalkanSekolahRepository(db)
+	pemeriksaanRepo := repositories.NewPemeriksaanRepository(db)
+	prestasiRepo := repositories.NewPrestasiRepository(db)
+	beasiswaRepo := repositories.NewBeasiswaRepository(db)
+	kepribadianRepo := repositories.NewKepribadianRepository(db)
+	kehadiranHarianRepo := repositories.NewKehadiranHarianRepository(db)
+	skemaPenilaianRepo := repositories.NewSkemaPenilaianRepository(db)
+	kurikulumRepo := repositories.NewKurikulumRepository(db)
+	jurusanRepo := repositories.NewJurusanRepository(db)
+	statistikRepo := repositories.NewStatistikRepository(db)
+	sessionRepo := repositories.NewSessionRepository(db)
+	passwordResetRepo := repositories.NewPasswordResetRepository(db)
+	twoFactorRepo := repositories.NewTwoFactorRepository(db)
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(db)
+
+	// Initialize mailer
+	mailer, err := utils.NewMailer(configs.AppConfig.Mail)
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo)
-	siswaService := services.NewSiswaService(siswaRepo, alamatRepo, orangTuaRepo, waliRepo, kesehatanRepo)
-	nilaiService := services.NewNilaiService(siswaRepo, mapelRepo, nilaiRepo, sikapRepo, catatanRepo, ijazahRepo, kehadiranRepo)
+	twoFactorService := services.NewTwoFactorService(userRepo, sessionRepo, twoFactorRepo)
+	loginAttemptService := services.NewLoginAttemptService(userRepo, loginAttemptRepo)
+	authService := services.NewAuthService(userRepo, sessionRepo, twoFactorRepo, twoFactorService, loginAttemptService)
+	userService := services.NewUserService(userRepo, sessionRepo, twoFactorRepo)
+	passwordService := services.NewPasswordService(userRepo, sessionRepo, passwordResetRepo, mailer)
+	siswaService := services.NewSiswaService(siswaRepo, alamatRepo, orangTuaRepo, waliRepo, kesehatanRepo, jurusanRepo)
+	nilaiService := services.NewNilaiService(siswaRepo, mapelRepo, nilaiRepo, sikapRepo, catatanRepo, ijazahRepo, kehadiranRepo, tahunPelajaranRepo, skemaPenilaianRepo, kurikulumRepo)
 	orangTuaService := services.NewOrangTuaService(siswaRepo, orangTuaRepo)
 	waliService := services.NewWaliService(siswaRepo, waliRepo)
 	kesehatanService := services.NewKesehatanService(siswaRepo, kesehatanRepo)
 	pendidikanService := services.NewPendidikanService(siswaRepo, pendidikanRepo)
+	rombelService := services.NewRombelService(rombelRepo, siswaRepo, userRepo, tahunPelajaranRepo)
+	tahunPelajaranService := services.NewTahunPelajaranService(tahunPelajaranRepo)
+	kenaikanKelasService := services.NewKenaikanKelasService(kenaikanKelasRepo, rombelRepo, nilaiRepo, kehadiranRepo, tahunPelajaranRepo)
+	meninggalkanSekolahService := services.NewMeninggalkanSekolahService(siswaRepo, meninggalkanSekolahRepo, rombelRepo, tahunPelajaranRepo)
+	pemeriksaanService := services.NewPemeriksaanService(pemeriksaanRepo)
+	prestasiService := services.NewPrestasiService(siswaRepo, prestasiRepo)
+	beasiswaService := services.NewBeasiswaService(siswaRepo, beasiswaRepo, tahunPelajaranRepo)
+	kepribadianService := services.NewKepribadianService(siswaRepo, kepribadianRepo, tahunPelajaranRepo)
+	nilaiSikapService := services.NewNilaiSikapService(siswaRepo, sikapRepo, rombelRepo)
+	kehadiranService := services.NewKehadiranService(siswaRepo, kehadiranRepo, kehadiranHarianRepo, rombelRepo, tahunPelajaranRepo)
+	skemaPenilaianService := services.NewSkemaPenilaianService(skemaPenilaianRepo, mapelRepo, tahunPelajaranRepo)
+	mataPelajaranService := services.NewMataPelajaranService(mapelRepo)
+	kurikulumService := services.NewKurikulumService(kurikulumRepo, mapelRepo, jurusanRepo)
+	jurusanService := services.NewJurusanService(jurusanRepo, siswaRepo)
+	bukuIndukService := services.NewBukuIndukService(siswaRepo, pemeriksaanRepo)
+	peringkatService := services.NewPeringkatService(rombelRepo, nilaiRepo, tahunPelajaranRepo)
+	leggerService := services.NewLeggerService(rombelRepo, nilaiRepo, peringkatService)
+	dapodikService := services.NewDapodikService(siswaRepo, rombelRepo, tahunPelajaranRepo)
+	portalService := services.NewPortalService(userRepo, siswaRepo, orangTuaRepo, waliRepo, siswaService)
+	statistikService := services.NewStatistikService(statistikRepo, tahunPelajaranRepo, siswaService)
+	raporService := services.NewRaporService(siswaRepo, nilaiRepo, sikapRepo, catatanRepo, kehadiranRepo, rombelRepo, jurusanRepo, kurikulumRepo, skemaPenilaianRepo, peringkatService)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
+	userHandler := handlers.NewUserHandler(userService)
+	loginAttemptHandler := handlers.NewLoginAttemptHandler(loginAttemptService)
 	siswaHandler := handlers.NewSiswaHandler(siswaService)
 	nilaiHandler := handlers.NewNilaiHandler(nilaiService)
 	orangTuaHandler := handlers.NewOrangTuaHandler(orangTuaService)
 	waliHandler := handlers.NewWaliHandler(waliService)
 	kesehatanHandler := handlers.NewKesehatanHandler(kesehatanService)
 	pendidikanHandler := handlers.NewPendidikanHandler(pendidikanService)
+	rombelHandler := handlers.NewRombelHandler(rombelService)
+	tahunPelajaranHandler := handlers.NewTahunPelajaranHandler(tahunPelajaranService)
+	kenaikanKelasHandler := handlers.NewKenaikanKelasHandler(kenaikanKelasService)
+	meninggalkanSekolahHandler := handlers.NewMeninggalkanSekolahHandler(meninggalkanSekolahService)
+	pemeriksaanHandler := handlers.NewPemeriksaanHandler(pemeriksaanService)
+	prestasiHandler := handlers.NewPrestasiHandler(prestasiService)
+	beasiswaHandler := handlers.NewBeasiswaHandler(beasiswaService)
+	kepribadianHandler := handlers.NewKepribadianHandler(kepribadianService)
+	nilaiSikapHandler := handlers.NewNilaiSikapHandler(nilaiSikapService)
+	kehadiranHandler := handlers.NewKehadiranHandler(kehadiranService)
+	skemaPenilaianHandler := handlers.NewSkemaPenilaianHandler(skemaPenilaianService)
+	mataPelajaranHandler := handlers.NewMataPelajaranHandler(mataPelajaranService)
+	kurikulumHandler := handlers.NewKurikulumHandler(kurikulumService)
+	jurusanHandler := handlers.NewJurusanHandler(jurusanService)
+	raporHandler := handlers.NewRaporHandler(raporService)
+	bukuIndukHandler := handlers.NewBukuIndukHandler(bukuIndukService)
+	leggerHandler := handlers.NewLeggerHandler(leggerService)
+	dapodikHandler := handlers.NewDapodikHandler(dapodikService)
+	peringkatHandler := handlers.NewPeringkatHandler(peringkatService)
+	statistikHandler := handlers.NewStatistikHandler(statistikService)
+	portalHandler := handlers.NewPortalHandler(portalService)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
 
 	// API v1 routes
 	api := r.Group("/api/v1")
@@ -73,67 +150,306 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/2fa", authHandler.LoginTwoFactor)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/forgot-password", passwordHandler.Forgot)
+			auth.POST("/reset-password", passwordHandler.Reset)
+		}
+
+		// Session routes, open to staff and portal accounts, also while they
+		// must change their password or set up two-factor authentication
+		authMiddleware := middlewares.AuthMiddleware(sessionRepo)
+		passwordChanged := middlewares.RequirePasswordChanged()
+		twoFactor := middlewares.RequireTwoFactor(twoFactorRepo)
+		session := api.Group("/auth", authMiddleware)
+		{
+			session.POST("/logout", authHandler.Logout)
+			session.POST("/logout-all", authHandler.LogoutAll)
+			session.PUT("/password", passwordHandler.Change)
+			session.GET("/2fa", twoFactorHandler.Status)
+			session.POST("/2fa/setup", twoFactorHandler.Setup)
+			session.POST("/2fa/enable", twoFactorHandler.Enable)
+			session.POST("/2fa/disable", twoFactorHandler.Disable)
+			session.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+			session.GET("/login-history", loginAttemptHandler.MyHistory)
 		}
 
+		// Permission middlewares. Every role can read; writes need the permission
+		// of the route, and wali kelas are limited to their own class group.
+		can := middlewares.RequirePermission
+		scope := middlewares.NewWaliKelasScope(rombelRepo)
+		siswaWrite := can(utils.PermSiswaWrite, scope.Siswa("id"))
+		nilaiWrite := can(utils.PermNilaiWrite, scope.Siswa("id"))
+		raporWrite := can(utils.PermRaporWrite, scope.Siswa("id"))
+		siswaManage := can(utils.PermSiswaManage)
+		masterWrite := can(utils.PermMasterWrite)
+		userManage := can(utils.PermUserManage)
+
 		// Protected routes
 		protected := api.Group("")
-		protected.Use(middlewares.AuthMiddleware())
+		protected.Use(authMiddleware, passwordChanged, twoFactor, can(utils.PermDataRead))
 		{
 			// Auth routes (protected)
 			authProtected := protected.Group("/auth")
 			{
-				authProtected.POST("/register", authHandler.Register)
+				authProtected.POST("/register", userManage, authHandler.Register)
 				authProtected.GET("/profile", authHandler.GetProfile)
 			}
 
+			// User & role routes
+			users := protected.Group("/users", userManage)
+			{
+				users.GET("", userHandler.FindAll)
+				users.GET("/:id", userHandler.FindByID)
+				users.PUT("/:id/roles", userHandler.SetRoles)
+				users.DELETE("/:id/sessions", userHandler.RevokeSessions)
+				users.POST("/:id/reset-password", passwordHandler.AdminReset)
+				users.DELETE("/:id/2fa", twoFactorHandler.AdminReset)
+				users.GET("/:id/login-history", loginAttemptHandler.History)
+				users.POST("/:id/unlock", loginAttemptHandler.Unlock)
+				users.POST("/portal", portalHandler.CreateAccount)
+				users.PUT("/:id/portal", portalHandler.SetLinks)
+			}
+			protected.GET("/roles", userHandler.Roles)
+			protected.PUT("/roles/:role/policy", userManage, userHandler.SetRolePolicy)
+
 			// Siswa routes
 			siswa := protected.Group("/siswa")
 			{
-				siswa.POST("", siswaHandler.Create)
+				siswa.POST("", can(utils.PermSiswaWrite), siswaHandler.Create)
 				siswa.GET("", siswaHandler.FindAll)
+				siswa.GET("/rekap-jurusan", siswaHandler.RekapJurusan)
+				siswa.POST("/import", siswaManage, siswaHandler.Import)
+				siswa.GET("/import/template", siswaHandler.ImportTemplate)
 				siswa.GET("/:id", siswaHandler.FindByID)
-				siswa.PUT("/:id", siswaHandler.Update)
-				siswa.DELETE("/:id", siswaHandler.Delete)
-				siswa.POST("/:id/foto", siswaHandler.UploadFoto)
+				siswa.GET("/:id/buku-induk", bukuIndukHandler.Get)
+				siswa.PUT("/:id", siswaWrite, siswaHandler.Update)
+				siswa.DELETE("/:id", siswaManage, siswaHandler.Delete)
+				siswa.POST("/:id/foto", siswaWrite, siswaHandler.UploadFoto)
+				siswa.PUT("/:id/jurusan", siswaManage, jurusanHandler.SetJurusanSiswa)
+				siswa.GET("/:id/jurusan", jurusanHandler.GetRiwayatJurusan)
 
 				// Sub-resources routes
-				siswa.POST("/:id/orang-tua", orangTuaHandler.Create)
-				siswa.POST("/:id/wali", waliHandler.CreateOrUpdate)
-				siswa.POST("/:id/kesehatan", kesehatanHandler.CreateOrUpdate)
-				siswa.POST("/:id/pendidikan", pendidikanHandler.Add)
+				siswa.POST("/:id/orang-tua", siswaWrite, orangTuaHandler.Create)
+				siswa.POST("/:id/wali", siswaWrite, waliHandler.CreateOrUpdate)
+				siswa.POST("/:id/kesehatan", siswaWrite, kesehatanHandler.CreateOrUpdate)
+				siswa.POST("/:id/pendidikan", siswaWrite, pendidikanHandler.Add)
+				siswa.POST("/:id/prestasi", siswaWrite, prestasiHandler.Add)
+				siswa.GET("/:id/prestasi", prestasiHandler.FindBySiswaID)
+				siswa.POST("/:id/beasiswa", siswaWrite, beasiswaHandler.Add)
+				siswa.GET("/:id/beasiswa", beasiswaHandler.FindBySiswaID)
+				siswa.POST("/:id/kepribadian", siswaWrite, kepribadianHandler.Add)
+				siswa.GET("/:id/kepribadian", kepribadianHandler.FindBySiswaID)
 
 				// Nested routes for nilai & kehadiran (using same :id parameter)
-				siswa.POST("/:id/nilai-semester", nilaiHandler.CreateNilaiSemester)
-				siswa.POST("/:id/nilai-semester/batch", nilaiHandler.BatchCreateNilaiSemester)
+				siswa.POST("/:id/nilai-semester", nilaiWrite, nilaiHandler.CreateNilaiSemester)
+				siswa.POST("/:id/nilai-semester/batch", nilaiWrite, nilaiHandler.BatchCreateNilaiSemester)
 				siswa.GET("/:id/nilai-semester", nilaiHandler.GetNilaiSemester)
 
+				siswa.POST("/:id/nilai-sikap", raporWrite, nilaiSikapHandler.CreateOrUpdate)
+				siswa.GET("/:id/nilai-sikap", nilaiSikapHandler.GetBySiswaID)
+				siswa.GET("/:id/nilai-sikap/:kelas/:semester", nilaiSikapHandler.GetBySemester)
+
 				// Kehadiran routes
+				siswa.POST("/:id/kehadiran", raporWrite, kehadiranHandler.Upsert)
 				siswa.GET("/:id/kehadiran", nilaiHandler.GetKehadiran)
+				siswa.POST("/:id/kehadiran-harian", raporWrite, kehadiranHandler.RecordHarian)
+				siswa.GET("/:id/kehadiran-harian", kehadiranHandler.GetHarian)
 
-				siswa.POST("/:id/nilai-ijazah", nilaiHandler.CreateNilaiIjazah)
+				siswa.POST("/:id/nilai-ijazah", raporWrite, nilaiHandler.CreateNilaiIjazah)
 				siswa.GET("/:id/nilai-ijazah", nilaiHandler.GetNilaiIjazah)
 
-				siswa.POST("/:id/catatan-semester", nilaiHandler.CreateCatatanSemester)
+				siswa.POST("/:id/catatan-semester", raporWrite, nilaiHandler.CreateCatatanSemester)
 				siswa.GET("/:id/catatan-semester", nilaiHandler.GetCatatanSemester)
+				siswa.GET("/:id/rapor", raporHandler.GetSiswa)
+
+				siswa.GET("/:id/kenaikan-kelas", kenaikanKelasHandler.GetBySiswaID)
+
+				siswa.POST("/:id/meninggalkan-sekolah", siswaManage, meninggalkanSekolahHandler.Create)
+				siswa.GET("/:id/meninggalkan-sekolah", meninggalkanSekolahHandler.Get)
+				siswa.PUT("/:id/meninggalkan-sekolah", siswaManage, meninggalkanSekolahHandler.Update)
+				siswa.DELETE("/:id/meninggalkan-sekolah", siswaManage, meninggalkanSekolahHandler.Delete)
 			}
 
 			// Direct resource routes for updates/deletes
-			protected.PUT("/orang-tua/:id", orangTuaHandler.Update)
-			protected.DELETE("/orang-tua/:id", orangTuaHandler.Delete)
+			protected.PUT("/orang-tua/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.OrangTua{})), orangTuaHandler.Update)
+			protected.DELETE("/orang-tua/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.OrangTua{})), orangTuaHandler.Delete)
+
+			protected.POST("/kesehatan/:id/riwayat-penyakit", can(utils.PermSiswaWrite, scope.Record("id", &models.KesehatanSiswa{})), kesehatanHandler.AddRiwayatPenyakit)
+			protected.DELETE("/riwayat-penyakit/:id", can(utils.PermSiswaWrite, scope.RiwayatPenyakit("id")), kesehatanHandler.DeleteRiwayatPenyakit)
+
+			protected.PUT("/pendidikan/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.PendidikanSebelumnya{})), pendidikanHandler.Update)
+			protected.DELETE("/pendidikan/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.PendidikanSebelumnya{})), pendidikanHandler.Delete)
+
+			protected.GET("/prestasi", prestasiHandler.FindAll)
+			protected.PUT("/prestasi/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.Prestasi{})), prestasiHandler.Update)
+			protected.DELETE("/prestasi/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.Prestasi{})), prestasiHandler.Delete)
 
-			protected.POST("/kesehatan/:id/riwayat-penyakit", kesehatanHandler.AddRiwayatPenyakit)
-			protected.DELETE("/riwayat-penyakit/:id", kesehatanHandler.DeleteRiwayatPenyakit)
+			protected.GET("/beasiswa", beasiswaHandler.FindAll)
+			protected.PUT("/beasiswa/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.Beasiswa{})), beasiswaHandler.Update)
+			protected.DELETE("/beasiswa/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.Beasiswa{})), beasiswaHandler.Delete)
 
-			protected.PUT("/pendidikan/:id", pendidikanHandler.Update)
-			protected.DELETE("/pendidikan/:id", pendidikanHandler.Delete)
+			protected.GET("/kepribadian", kepribadianHandler.FindAll)
+			protected.PUT("/kepribadian/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.Kepribadian{})), kepribadianHandler.Update)
+			protected.DELETE("/kepribadian/:id", can(utils.PermSiswaWrite, scope.Record("id", &models.Kepribadian{})), kepribadianHandler.Delete)
+
+			protected.DELETE("/kehadiran-harian/:id", can(utils.PermRaporWrite, scope.Record("id", &models.KehadiranHarian{})), kehadiranHandler.DeleteHarian)
 
 			// Mata pelajaran routes
-			protected.GET("/mata-pelajaran", nilaiHandler.GetMataPelajaran)
+			mataPelajaran := protected.Group("/mata-pelajaran")
+			{
+				mataPelajaran.POST("", masterWrite, mataPelajaranHandler.Create)
+				mataPelajaran.GET("", mataPelajaranHandler.FindAll)
+				mataPelajaran.GET("/:id", mataPelajaranHandler.FindByID)
+				mataPelajaran.PUT("/:id", masterWrite, mataPelajaranHandler.Update)
+				mataPelajaran.DELETE("/:id", masterWrite, mataPelajaranHandler.Delete)
+				mataPelajaran.POST("/:id/aktifkan", masterWrite, mataPelajaranHandler.Activate)
+				mataPelajaran.POST("/:id/nonaktifkan", masterWrite, mataPelajaranHandler.Deactivate)
+			}
+
+			// Jurusan (bidang, program & kompetensi keahlian) routes
+			bidangKeahlian := protected.Group("/bidang-keahlian")
+			{
+				bidangKeahlian.POST("", masterWrite, jurusanHandler.CreateBidang)
+				bidangKeahlian.GET("", jurusanHandler.FindAllBidang)
+				bidangKeahlian.PUT("/:id", masterWrite, jurusanHandler.UpdateBidang)
+				bidangKeahlian.DELETE("/:id", masterWrite, jurusanHandler.DeleteBidang)
+			}
+
+			programKeahlian := protected.Group("/program-keahlian")
+			{
+				programKeahlian.POST("", masterWrite, jurusanHandler.CreateProgram)
+				programKeahlian.GET("", jurusanHandler.FindAllProgram)
+				programKeahlian.PUT("/:id", masterWrite, jurusanHandler.UpdateProgram)
+				programKeahlian.DELETE("/:id", masterWrite, jurusanHandler.DeleteProgram)
+			}
+
+			kompetensiKeahlian := protected.Group("/kompetensi-keahlian")
+			{
+				kompetensiKeahlian.POST("", masterWrite, jurusanHandler.CreateKompetensi)
+				kompetensiKeahlian.GET("", jurusanHandler.FindAllKompetensi)
+				kompetensiKeahlian.GET("/:id", jurusanHandler.FindKompetensiByID)
+				kompetensiKeahlian.PUT("/:id", masterWrite, jurusanHandler.UpdateKompetensi)
+				kompetensiKeahlian.DELETE("/:id", masterWrite, jurusanHandler.DeleteKompetensi)
+			}
+
+			// Kurikulum routes
+			kurikulum := protected.Group("/kurikulum")
+			{
+				kurikulum.POST("", masterWrite, kurikulumHandler.Create)
+				kurikulum.GET("", kurikulumHandler.FindAll)
+				kurikulum.GET("/:id", kurikulumHandler.FindByID)
+				kurikulum.PUT("/:id", masterWrite, kurikulumHandler.Update)
+				kurikulum.DELETE("/:id", masterWrite, kurikulumHandler.Delete)
+			}
+
+			// Skema penilaian (KKM & predikat) routes
+			skemaPenilaian := protected.Group("/skema-penilaian")
+			{
+				skemaPenilaian.POST("", masterWrite, skemaPenilaianHandler.Create)
+				skemaPenilaian.GET("", skemaPenilaianHandler.FindAll)
+				skemaPenilaian.GET("/:id", skemaPenilaianHandler.FindByID)
+				skemaPenilaian.PUT("/:id", masterWrite, skemaPenilaianHandler.Update)
+				skemaPenilaian.DELETE("/:id", masterWrite, skemaPenilaianHandler.Delete)
+				skemaPenilaian.POST("/:id/hitung-ulang", masterWrite, skemaPenilaianHandler.HitungUlang)
+			}
 
 			// Catatan semester routes (separate group)
 			catatanSemester := protected.Group("/catatan-semester")
 			{
-				catatanSemester.POST("/:id/pkl", nilaiHandler.AddPKL)
+				catatanSemester.POST("/:id/pkl", can(utils.PermRaporWrite, scope.Catatan("id")), nilaiHandler.AddPKL)
+				catatanSemester.POST("/:id/ekstrakurikuler", can(utils.PermRaporWrite, scope.Catatan("id")), nilaiHandler.AddEkstrakurikuler)
+				catatanSemester.POST("/:id/prestasi", can(utils.PermRaporWrite, scope.Catatan("id")), nilaiHandler.AddPrestasiSemester)
+				catatanSemester.PUT("/:id/ketidakhadiran", can(utils.PermRaporWrite, scope.Catatan("id")), nilaiHandler.SetKetidakhadiran)
+				catatanSemester.DELETE("/:id/ketidakhadiran", can(utils.PermRaporWrite, scope.Catatan("id")), nilaiHandler.DeleteKetidakhadiran)
+			}
+
+			protected.PUT("/pkl/:id", can(utils.PermRaporWrite, scope.CatatanRecord("id", &models.PraktikKerjaLapangan{})), nilaiHandler.UpdatePKL)
+			protected.DELETE("/pkl/:id", can(utils.PermRaporWrite, scope.CatatanRecord("id", &models.PraktikKerjaLapangan{})), nilaiHandler.DeletePKL)
+			protected.PUT("/ekstrakurikuler/:id", can(utils.PermRaporWrite, scope.CatatanRecord("id", &models.Ekstrakurikuler{})), nilaiHandler.UpdateEkstrakurikuler)
+			protected.DELETE("/ekstrakurikuler/:id", can(utils.PermRaporWrite, scope.CatatanRecord("id", &models.Ekstrakurikuler{})), nilaiHandler.DeleteEkstrakurikuler)
+			protected.PUT("/prestasi-semester/:id", can(utils.PermRaporWrite, scope.CatatanRecord("id", &models.PrestasiSemester{})), nilaiHandler.UpdatePrestasiSemester)
+			protected.DELETE("/prestasi-semester/:id", can(utils.PermRaporWrite, scope.CatatanRecord("id", &models.PrestasiSemester{})), nilaiHandler.DeletePrestasiSemester)
+
+			// Rombel routes
+			rombel := protected.Group("/rombel")
+			{
+				rombel.POST("", masterWrite, rombelHandler.Create)
+				rombel.GET("", rombelHandler.FindAll)
+				rombel.GET("/:id", rombelHandler.FindByID)
+				rombel.PUT("/:id", masterWrite, rombelHandler.Update)
+				rombel.DELETE("/:id", masterWrite, rombelHandler.Delete)
+				rombel.GET("/:id/anggota", rombelHandler.GetAnggota)
+				rombel.POST("/:id/anggota", masterWrite, rombelHandler.AddAnggota)
+				rombel.DELETE("/:id/anggota/:siswa_id", masterWrite, rombelHandler.RemoveAnggota)
+				rombel.POST("/:id/nilai-sikap", can(utils.PermRaporWrite, scope.Rombel("id")), nilaiSikapHandler.BulkCreateOrUpdate)
+				rombel.POST("/:id/kehadiran-harian", can(utils.PermRaporWrite, scope.Rombel("id")), kehadiranHandler.RecordHarianRombel)
+				rombel.GET("/:id/rapor", raporHandler.GetRombel)
+				rombel.GET("/:id/legger", leggerHandler.Get)
+				rombel.GET("/:id/peringkat", peringkatHandler.GetRombel)
+			}
+
+			// Tahun pelajaran routes
+			tahunPelajaran := protected.Group("/tahun-pelajaran")
+			{
+				tahunPelajaran.POST("", masterWrite, tahunPelajaranHandler.Create)
+				tahunPelajaran.GET("", tahunPelajaranHandler.FindAll)
+				tahunPelajaran.GET("/aktif", tahunPelajaranHandler.FindActive)
+				tahunPelajaran.GET("/:id", tahunPelajaranHandler.FindByID)
+				tahunPelajaran.PUT("/:id", masterWrite, tahunPelajaranHandler.Update)
+				tahunPelajaran.POST("/:id/aktifkan", masterWrite, tahunPelajaranHandler.Activate)
+				tahunPelajaran.DELETE("/:id", masterWrite, tahunPelajaranHandler.Delete)
+			}
+
+			// Kenaikan kelas routes
+			kenaikanKelas := protected.Group("/kenaikan-kelas")
+			{
+				kenaikanKelas.POST("/preview", siswaManage, kenaikanKelasHandler.Preview)
+				kenaikanKelas.POST("", siswaManage, kenaikanKelasHandler.Proses)
+			}
+
+			// Peringkat route
+			protected.GET("/peringkat", peringkatHandler.GetTingkat)
+
+			// Statistik route
+			protected.GET("/statistik", statistikHandler.Get)
+
+			// Kelulusan route
+			protected.POST("/kelulusan", siswaManage, meninggalkanSekolahHandler.Luluskan)
+
+			// Pemeriksaan buku induk routes
+			pemeriksaan := protected.Group("/pemeriksaan-buku")
+			{
+				pemeriksaan.POST("", masterWrite, pemeriksaanHandler.Create)
+				pemeriksaan.GET("", pemeriksaanHandler.FindAll)
+				pemeriksaan.GET("/:id", pemeriksaanHandler.FindByID)
+				pemeriksaan.PUT("/:id", masterWrite, pemeriksaanHandler.Update)
+				pemeriksaan.DELETE("/:id", masterWrite, pemeriksaanHandler.Delete)
+			}
+
+			// Dapodik routes
+			dapodik := protected.Group("/dapodik")
+			{
+				dapodik.GET("/export", dapodikHandler.Export)
+				dapodik.POST("/compare", siswaManage, dapodikHandler.Compare)
+				dapodik.POST("/apply", siswaManage, dapodikHandler.Apply)
+			}
+		}
+
+		// Portal routes for student and parent accounts, limited to their linked students
+		portalScope := middlewares.NewPortalScope(userRepo)
+		portal := api.Group("/portal")
+		portal.Use(authMiddleware, passwordChanged, twoFactor, middlewares.RequirePortal())
+		{
+			portal.GET("/profile", authHandler.GetProfile)
+			portal.GET("/siswa", portalHandler.FindSiswa)
+
+			anak := portal.Group("/siswa/:id", portalScope.Siswa("id"))
+			{
+				anak.GET("", portalHandler.GetSiswa)
+				anak.GET("/nilai-semester", nilaiHandler.GetNilaiSemester)
+				anak.GET("/kehadiran", nilaiHandler.GetKehadiran)
+				anak.GET("/rapor", raporHandler.GetSiswa)
 			}
 		}
 	}
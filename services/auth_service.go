@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	"github.com/kampunk/api-siswa/configs"
 	"github.com/kampunk/api-siswa/dtos/requests"
@@ -14,57 +15,310 @@ import (
 
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo *repositories.UserRepository
+	userRepo         *repositories.UserRepository
+	sessionRepo      *repositories.SessionRepository
+	twoFactorRepo    *repositories.TwoFactorRepository
+	twoFactorService *TwoFactorService
+	attemptService   *LoginAttemptService
 }
 
 // NewAuthService creates a new AuthService
-func NewAuthService(userRepo *repositories.UserRepository) *AuthService {
-	return &AuthService{userRepo: userRepo}
+func NewAuthService(
+	userRepo *repositories.UserRepository,
+	sessionRepo *repositories.SessionRepository,
+	twoFactorRepo *repositories.TwoFactorRepository,
+	twoFactorService *TwoFactorService,
+	attemptService *LoginAttemptService,
+) *AuthService {
+	return &AuthService{
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		twoFactorRepo:    twoFactorRepo,
+		twoFactorService: twoFactorService,
+		attemptService:   attemptService,
+	}
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *AuthService) Login(req requests.LoginRequest) (*responses.LoginResponse, error) {
+// Login authenticates a user and starts a session, returning a short-lived
+// access token and the refresh token of the session. Users with two-factor
+// authentication only get a challenge token for LoginTwoFactor. Every attempt
+// is recorded; repeated failures slow down and then lock the account, and a
+// LoginBlockedError is returned while the username or IP address is blocked.
+func (s *AuthService) Login(req requests.LoginRequest, userAgent, ipAddress string) (*responses.LoginResponse, error) {
 	// Find user by username
 	user, err := s.userRepo.FindByUsername(req.Username)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid username or password")
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
 		}
+		user = nil
+	}
+
+	if err := s.checkAttempt(user, req.Username, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, s.failLogin(nil, req.Username, ipAddress, userAgent, models.LoginReasonUnknownUser, "invalid username or password")
+	}
+
+	// Verify password
+	if !utils.CheckPassword(req.Password, user.PasswordHash) {
+		return nil, s.failLogin(user, req.Username, ipAddress, userAgent, models.LoginReasonWrongPassword, "invalid username or password")
+	}
+	if user, err = s.recheckAttempt(user, ipAddress, userAgent); err != nil {
 		return nil, err
 	}
 
 	// Check if user is active
+	if !user.IsActive {
+		return nil, s.failLogin(user, req.Username, ipAddress, userAgent, models.LoginReasonDeactivated, "account is deactivated")
+	}
+
+	if user.TOTPEnabled {
+		challenge, err := utils.GenerateChallengeToken(user.ID)
+		if err != nil {
+			return nil, errors.New("failed to generate token")
+		}
+		return &responses.LoginResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	return s.startSession(user, userAgent, ipAddress)
+}
+
+// LoginTwoFactor completes the login of a user with two-factor authentication
+// with the challenge token of the first step and an authenticator or recovery
+// code. Wrong codes count towards the lockout like wrong passwords.
+func (s *AuthService) LoginTwoFactor(req requests.LoginTwoFactorRequest, userAgent, ipAddress string) (*responses.LoginResponse, error) {
+	userID, err := utils.ValidateChallengeToken(req.ChallengeToken)
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge token, please log in again")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired challenge token, please log in again")
+		}
+		return nil, err
+	}
 	if !user.IsActive {
 		return nil, errors.New("account is deactivated")
 	}
+	// Two-factor authentication was reset after the first step
+	if !user.TOTPEnabled {
+		return nil, errors.New("invalid or expired challenge token, please log in again")
+	}
 
-	// Verify password
-	if !utils.CheckPassword(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid username or password")
+	if err := s.checkAttempt(user, user.Username, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	valid, err := s.twoFactorService.verify(user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, s.failLogin(user, user.Username, ipAddress, userAgent, models.LoginReasonWrongCode, "invalid authentication code")
+	}
+	if user, err = s.recheckAttempt(user, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, userAgent, ipAddress)
+}
+
+// checkAttempt rejects a login while the user or IP address is blocked,
+// recording the rejected attempt
+func (s *AuthService) checkAttempt(user *models.User, username, ipAddress, userAgent string) error {
+	err := s.attemptService.Check(user, ipAddress)
+	if err == nil {
+		return nil
+	}
+	var blocked *LoginBlockedError
+	if !errors.As(err, &blocked) {
+		return err
+	}
+	if err := s.attemptService.Blocked(user, username, ipAddress, userAgent); err != nil {
+		return err
+	}
+	return blocked
+}
+
+// recheckAttempt repeats checkAttempt with the stored state once the
+// credentials are verified. Requests sent in parallel all pass the first check,
+// so this stops those still running after the others locked the account or
+// blocked the address.
+func (s *AuthService) recheckAttempt(user *models.User, ipAddress, userAgent string) (*models.User, error) {
+	current, err := s.userRepo.FindByID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAttempt(current, current.Username, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// failLogin records a failed login and returns the error shown to the client
+func (s *AuthService) failLogin(user *models.User, username, ipAddress, userAgent, reason, message string) error {
+	if err := s.attemptService.Failure(user, username, ipAddress, userAgent, reason); err != nil {
+		return err
 	}
+	return errors.New(message)
+}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Username)
+// startSession creates the session of an authenticated user
+func (s *AuthService) startSession(user *models.User, userAgent, ipAddress string) (*responses.LoginResponse, error) {
+	// Clean up the user's finished sessions before starting a new one
+	if err := s.sessionRepo.DeleteExpired(user.ID); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken()
 	if err != nil {
 		return nil, errors.New("failed to generate token")
 	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	now := time.Now()
+	session := &models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+		ExpiresAt:        now.Add(refreshExpiry()),
+		LastUsedAt:       now,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+	if err := s.attemptService.Success(user, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
 
-	return &responses.LoginResponse{
-		Token:     token,
-		ExpiresIn: configs.AppConfig.JWT.ExpiryHours * 3600,
-		User: responses.UserResponse{
-			ID:       user.ID,
-			Username: user.Username,
-			Email:    user.Email,
-			IsActive: user.IsActive,
-		},
-	}, nil
+	return s.newLoginResponse(user, session.ID, refreshToken)
 }
 
-// Register creates a new admin user
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. Each refresh token works once; presenting one that was already
+// rotated means it was copied, so the whole session is revoked.
+func (s *AuthService) Refresh(req requests.RefreshTokenRequest) (*responses.LoginResponse, error) {
+	hash := utils.HashToken(req.RefreshToken)
+
+	session, err := s.sessionRepo.FindByRefreshHash(hash)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		reused, err := s.sessionRepo.FindByPreviousHash(hash)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("invalid or expired refresh token")
+			}
+			return nil, err
+		}
+		if err := s.sessionRepo.Revoke(reused.ID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token reuse detected, session revoked")
+	}
+
+	if session.RevokedAt != nil || !session.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("invalid or expired refresh token")
+	}
+
+	// Reload the user so deactivation and role changes take effect
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired refresh token")
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, errors.New("account is deactivated")
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	rotated, err := s.sessionRepo.Rotate(session.ID, hash, utils.HashToken(refreshToken), time.Now().Add(refreshExpiry()))
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, errors.New("invalid or expired refresh token")
+	}
+
+	return s.newLoginResponse(user, session.ID, refreshToken)
+}
+
+// Logout revokes the session of the current access token
+func (s *AuthService) Logout(sessionID uint) error {
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// LogoutAll revokes every session of a user, signing them out on all devices
+func (s *AuthService) LogoutAll(userID uint) (*responses.RevokedSessionsResponse, error) {
+	revoked, err := s.sessionRepo.RevokeAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &responses.RevokedSessionsResponse{Revoked: revoked}, nil
+}
+
+// newLoginResponse issues the access token of a session
+func (s *AuthService) newLoginResponse(user *models.User, sessionID uint, refreshToken string) (*responses.LoginResponse, error) {
+	token, err := utils.GenerateToken(user.ID, user.Username, roleNames(user), sessionID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	resp := &responses.LoginResponse{
+		Token:            token,
+		ExpiresIn:        configs.AppConfig.JWT.ExpiryMinutes * 60,
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int(refreshExpiry().Seconds()),
+		User:             toUserResponse(user),
+	}
+	if !user.TOTPEnabled {
+		if resp.TwoFactorSetupRequired, err = s.twoFactorRepo.IsRequired(roleNames(user)); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// refreshExpiry is how long a session lasts without being refreshed
+func refreshExpiry() time.Duration {
+	return time.Duration(configs.AppConfig.JWT.RefreshExpiryDays) * 24 * time.Hour
+}
+
+// Register creates a new admin user with its roles
 func (s *AuthService) Register(req requests.RegisterRequest) (*responses.UserResponse, error) {
+	user, err := newUser(s.userRepo, req.Username, req.Email, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range uniqueRoles(req.Roles) {
+		user.Roles = append(user.Roles, models.UserRole{Role: role})
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+// newUser prepares an active user after checking that the username and email
+// are free
+func newUser(userRepo *repositories.UserRepository, username, email, password string) (*models.User, error) {
 	// Check if username exists
-	exists, err := s.userRepo.ExistsByUsername(req.Username)
+	exists, err := userRepo.ExistsByUsername(username)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +327,7 @@ func (s *AuthService) Register(req requests.RegisterRequest) (*responses.UserRes
 	}
 
 	// Check if email exists
-	exists, err = s.userRepo.ExistsByEmail(req.Email)
+	exists, err = userRepo.ExistsByEmail(email)
 	if err != nil {
 		return nil, err
 	}
@@ -82,28 +336,16 @@ func (s *AuthService) Register(req requests.RegisterRequest) (*responses.UserRes
 	}
 
 	// Hash password
-	hashedPassword, err := utils.HashPassword(req.Password)
+	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
 		return nil, errors.New("failed to hash password")
 	}
 
-	// Create user
-	user := &models.User{
-		Username:     req.Username,
-		Email:        req.Email,
+	return &models.User{
+		Username:     username,
+		Email:        email,
 		PasswordHash: hashedPassword,
 		IsActive:     true,
-	}
-
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, err
-	}
-
-	return &responses.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		IsActive: user.IsActive,
 	}, nil
 }
 
@@ -114,10 +356,50 @@ func (s *AuthService) GetProfile(userID uint) (*responses.UserResponse, error) {
 		return nil, err
 	}
 
-	return &responses.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		IsActive: user.IsActive,
-	}, nil
+	return toUserResponse(user), nil
+}
+
+// roleNames lists the roles of a user
+func roleNames(user *models.User) []string {
+	roles := make([]string, 0, len(user.Roles))
+	for _, r := range user.Roles {
+		roles = append(roles, r.Role)
+	}
+	return roles
+}
+
+// uniqueRoles drops repeated roles, keeping their order
+func uniqueRoles(roles []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, role := range roles {
+		if !seen[role] {
+			seen[role] = true
+			result = append(result, role)
+		}
+	}
+	return result
+}
+
+func toUserResponse(user *models.User) *responses.UserResponse {
+	resp := &responses.UserResponse{
+		ID:                 user.ID,
+		Username:           user.Username,
+		Email:              user.Email,
+		IsActive:           user.IsActive,
+		MustChangePassword: user.MustChangePassword,
+		TwoFactorEnabled:   user.TOTPEnabled,
+		Roles:              roleNames(user),
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		resp.LockedUntil = user.LockedUntil
+	}
+	for _, link := range user.PortalLinks {
+		resp.Portal = append(resp.Portal, responses.PortalLinkResponse{
+			SiswaID:    link.SiswaID,
+			OrangTuaID: link.OrangTuaID,
+			WaliID:     link.WaliID,
+		})
+	}
+	return resp
 }
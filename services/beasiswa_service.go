@@ -15,11 +15,12 @@ import (
 type BeasiswaService struct {
 	siswaRepo    *repositories.SiswaRepository
 	beasiswaRepo *repositories.BeasiswaRepository
+	tahunRepo    *repositories.TahunPelajaranRepository
 }
 
 // NewBeasiswaService creates a new BeasiswaService
-func NewBeasiswaService(siswaRepo *repositories.SiswaRepository, beasiswaRepo *repositories.BeasiswaRepository) *BeasiswaService {
-	return &BeasiswaService{siswaRepo: siswaRepo, beasiswaRepo: beasiswaRepo}
+func NewBeasiswaService(siswaRepo *repositories.SiswaRepository, beasiswaRepo *repositories.BeasiswaRepository, tahunRepo *repositories.TahunPelajaranRepository) *BeasiswaService {
+	return &BeasiswaService{siswaRepo: siswaRepo, beasiswaRepo: beasiswaRepo, tahunRepo: tahunRepo}
 }
 
 // Add adds scholarship record to a student
@@ -33,9 +34,14 @@ func (s *BeasiswaService) Add(siswaID uint, req requests.CreateBeasiswaRequest)
 		return nil, err
 	}
 
+	tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
 	beasiswa := &models.Beasiswa{
 		SiswaID:        siswaID,
-		TahunPelajaran: utils.SanitizeString(req.TahunPelajaran),
+		TahunPelajaran: tahunPelajaran,
 		Pemberi:        utils.SanitizeString(req.Pemberi),
 		Keterangan:     utils.SanitizeString(req.Keterangan),
 	}
@@ -47,11 +53,96 @@ func (s *BeasiswaService) Add(siswaID uint, req requests.CreateBeasiswaRequest)
 	return s.toResponse(beasiswa), nil
 }
 
+// FindAll gets scholarship records with pagination, optionally limited to one student
+func (s *BeasiswaService) FindAll(siswaID uint, req requests.PaginationRequest, filter requests.BeasiswaFilterRequest) ([]responses.BeasiswaResponse, utils.Pagination, error) {
+	if siswaID > 0 {
+		if _, err := s.siswaRepo.FindByID(siswaID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, utils.Pagination{}, errors.New("student not found")
+			}
+			return nil, utils.Pagination{}, err
+		}
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filterMap := map[string]interface{}{
+		"siswa_id":        siswaID,
+		"tahun_pelajaran": utils.SanitizeString(filter.TahunPelajaran),
+		"pemberi":         utils.SanitizeString(filter.Pemberi),
+	}
+
+	beasiswaList, total, err := s.beasiswaRepo.FindAll(req.Page, req.PageSize, filterMap)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var response []responses.BeasiswaResponse
+	for i := range beasiswaList {
+		response = append(response, *s.toResponse(&beasiswaList[i]))
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+
+	return response, pagination, nil
+}
+
+// Update updates scholarship record
+func (s *BeasiswaService) Update(id uint, req requests.UpdateBeasiswaRequest) (*responses.BeasiswaResponse, error) {
+	beasiswa, err := s.beasiswaRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("scholarship not found")
+		}
+		return nil, err
+	}
+
+	if req.TahunPelajaran != "" {
+		tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+		if err != nil {
+			return nil, err
+		}
+		beasiswa.TahunPelajaran = tahunPelajaran
+	}
+	if req.Pemberi != "" {
+		beasiswa.Pemberi = utils.SanitizeString(req.Pemberi)
+	}
+	if req.Keterangan != "" {
+		beasiswa.Keterangan = utils.SanitizeString(req.Keterangan)
+	}
+
+	if err := s.beasiswaRepo.Update(beasiswa); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(beasiswa), nil
+}
+
 // Delete deletes scholarship record
 func (s *BeasiswaService) Delete(id uint) error {
-	// BeasiswaRepo also might missing FindByID.
-	// We'll trust ID for now or check repo.
-	// BeasiswaRepo in related_repository.go only showed Create, FindBySiswaID, Update, Delete.
+	_, err := s.beasiswaRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("scholarship not found")
+		}
+		return err
+	}
+
 	return s.beasiswaRepo.Delete(id)
 }
 
@@ -62,5 +153,6 @@ func (s *BeasiswaService) toResponse(b *models.Beasiswa) *responses.BeasiswaResp
 		TahunPelajaran: b.TahunPelajaran,
 		Pemberi:        b.Pemberi,
 		Keterangan:     b.Keterangan,
+		Siswa:          toSiswaRingkasResponse(b.Siswa),
 	}
 }
@@ -0,0 +1,752 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kampunk/api-siswa/configs"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// BukuIndukService renders the buku induk (student master record) of a student
+type BukuIndukService struct {
+	siswaRepo       *repositories.SiswaRepository
+	pemeriksaanRepo *repositories.PemeriksaanRepository
+}
+
+// NewBukuIndukService creates a new BukuIndukService
+func NewBukuIndukService(
+	siswaRepo *repositories.SiswaRepository,
+	pemeriksaanRepo *repositories.PemeriksaanRepository,
+) *BukuIndukService {
+	return &BukuIndukService{
+		siswaRepo:       siswaRepo,
+		pemeriksaanRepo: pemeriksaanRepo,
+	}
+}
+
+// bukuIndukKolom is a table header cell spanning one or more columns
+type bukuIndukKolom struct {
+	Label string
+	Span  int
+}
+
+// bukuIndukBagian is one lettered section of the buku induk. A section holds
+// label/value pairs, a table, or both. A table row with a single cell spans
+// the whole table.
+type bukuIndukBagian struct {
+	Judul  string
+	Isian  [][2]string
+	Header [][]bukuIndukKolom
+	Lebar  []float64
+	Baris  [][]string
+}
+
+// JumlahKolom returns the number of table columns
+func (b bukuIndukBagian) JumlahKolom() int {
+	return len(b.Lebar)
+}
+
+// bukuInduk is the format independent content of a buku induk, rendered to
+// PDF or HTML
+type bukuInduk struct {
+	Sekolah configs.SchoolConfig
+	Siswa   *models.Siswa
+	Foto    []byte
+	Bagian  []bukuIndukBagian
+	Dicetak string
+}
+
+// FotoURI returns the photo as a data URI for the HTML page
+func (b *bukuInduk) FotoURI() template.URL {
+	contentType := http.DetectContentType(b.Foto)
+	if !strings.HasPrefix(contentType, "image/") {
+		return ""
+	}
+	return template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(b.Foto))
+}
+
+// GeneratePDF renders the buku induk of a student as PDF
+func (s *BukuIndukService) GeneratePDF(siswaID uint) ([]byte, string, error) {
+	data, err := s.build(siswaID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("buku-induk-%s.pdf", data.Siswa.NoInduk)
+	return data.pdf(), sanitizeFilename(filename), nil
+}
+
+// pdf lays out the sections on A4 pages with the photo next to the first section
+func (data *bukuInduk) pdf() []byte {
+	pdf := utils.NewPDF()
+	pdf.AddPage()
+	kopLaporan(pdf, "BUKU INDUK PESERTA DIDIK")
+
+	// The photo sits to the right of the first section
+	const fotoW, fotoH = 85.0, 113.0
+	fotoX := pdf.ContentWidth() - fotoW
+	fotoY := pdf.Y()
+	if len(data.Foto) == 0 || pdf.Image(data.Foto, fotoX, fotoW, fotoH) != nil {
+		pdf.Rect(fotoX, fotoW, fotoH)
+	}
+
+	for i, bagian := range data.Bagian {
+		isian := []float64{150, pdf.ContentWidth() - 150}
+		if i == 0 {
+			isian = []float64{150, fotoX - 160}
+		}
+
+		judulBagian(pdf, bagian.Judul)
+		pdf.SetFont(false, 9)
+		for _, row := range bagian.Isian {
+			pdf.Row(isian, []string{row[0], ": " + teksAtauStrip(row[1])}, utils.PDFRowStyle{})
+		}
+		if len(bagian.Isian) > 0 && len(bagian.Lebar) > 0 {
+			pdf.Ln(4)
+		}
+
+		if len(bagian.Lebar) > 0 {
+			pdf.SetFont(true, 8)
+			for _, header := range bagian.Header {
+				widths := make([]float64, len(header))
+				labels := make([]string, len(header))
+				aligns := make([]utils.PDFAlign, len(header))
+				col := 0
+				for j, k := range header {
+					for n := 0; n < k.Span; n++ {
+						widths[j] += bagian.Lebar[col]
+						col++
+					}
+					labels[j] = k.Label
+					aligns[j] = utils.AlignCenter
+				}
+				pdf.Row(widths, labels, utils.PDFRowStyle{Border: true, Fill: true, Aligns: aligns})
+			}
+			for _, row := range bagian.Baris {
+				if len(row) == 1 {
+					pdf.SetFont(true, 8)
+					pdf.Row([]float64{pdf.ContentWidth()}, row, utils.PDFRowStyle{Border: true})
+					continue
+				}
+				pdf.SetFont(false, 8)
+				pdf.Row(bagian.Lebar, row, utils.PDFRowStyle{Border: true})
+			}
+		}
+
+		if i == 0 && pdf.Y() < fotoY+fotoH {
+			pdf.SetY(fotoY + fotoH)
+		}
+		pdf.Ln(10)
+	}
+
+	pdf.SetFont(false, 8)
+	pdf.Paragraph("Dicetak pada "+data.Dicetak, utils.AlignRight)
+
+	return pdf.Bytes()
+}
+
+// GenerateHTML renders the buku induk of a student as a printable HTML page
+func (s *BukuIndukService) GenerateHTML(siswaID uint) ([]byte, string, error) {
+	data, err := s.build(siswaID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	if err := bukuIndukTemplate.Execute(&buf, data); err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("buku-induk-%s.html", data.Siswa.NoInduk)
+	return buf.Bytes(), sanitizeFilename(filename), nil
+}
+
+// build loads a student with all related data and lays out the sections
+func (s *BukuIndukService) build(siswaID uint) (*bukuInduk, error) {
+	siswa, err := s.siswaRepo.FindByIDWithRelations(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	pemeriksaan, err := s.pemeriksaanRepo.FindByPeriode(periodeSiswa(siswa))
+	if err != nil {
+		return nil, err
+	}
+
+	// Stored text is HTML-escaped; the PDF encoder and html/template escape
+	// it again for their own output
+	utils.UnescapeStrings(siswa, pemeriksaan)
+
+	data := &bukuInduk{
+		Sekolah: configs.AppConfig.School,
+		Siswa:   siswa,
+		Dicetak: tanggalIndonesia(time.Now()),
+	}
+	// A missing photo file leaves an empty frame instead of failing the export
+	if siswa.FotoPath != "" {
+		if foto, err := utils.ReadFile(siswa.FotoPath); err == nil {
+			data.Foto = foto
+		}
+	}
+
+	var ayah, ibu *models.OrangTua
+	for i := range siswa.OrangTua {
+		if siswa.OrangTua[i].Tipe == "ayah" {
+			ayah = &siswa.OrangTua[i]
+		} else {
+			ibu = &siswa.OrangTua[i]
+		}
+	}
+
+	bagian := []bukuIndukBagian{
+		bagianDiriSiswa(siswa),
+		bagianTempatTinggal(siswa.Alamat),
+		bagianKesehatan(siswa.Kesehatan),
+		bagianPendidikan(siswa.PendidikanSebelumnya),
+		bagianOrangTua("Keterangan Tentang Ayah Kandung", ayah),
+		bagianOrangTua("Keterangan Tentang Ibu Kandung", ibu),
+		bagianWali(siswa.Wali),
+		bagianKepribadian(siswa.Kepribadian),
+		bagianPrestasi(siswa.Prestasi),
+		bagianBeasiswa(siswa.Beasiswa),
+		bagianNilaiSemester(siswa.NilaiSemester),
+		bagianSikap(siswa.NilaiSikap),
+		bagianKetidakhadiran(siswa.Kehadiran),
+		bagianKenaikanKelas(siswa.KenaikanKelas),
+		bagianNilaiIjazah(siswa.NilaiIjazah),
+		bagianMeninggalkanSekolah(siswa.MeninggalkanSekolah),
+		bagianPemeriksaan(pemeriksaan),
+	}
+	for i := range bagian {
+		bagian[i].Judul = fmt.Sprintf("%c. %s", 'A'+i, bagian[i].Judul)
+	}
+	data.Bagian = bagian
+
+	return data, nil
+}
+
+// periodeSiswa returns the inspection filter covering the student's time at
+// school, from admission until leaving
+func periodeSiswa(siswa *models.Siswa) map[string]interface{} {
+	mulai := siswa.CreatedAt
+	for _, p := range siswa.PendidikanSebelumnya {
+		if p.TanggalDiterima.Before(mulai) {
+			mulai = p.TanggalDiterima
+		}
+	}
+
+	filter := map[string]interface{}{
+		"tanggal_mulai": time.Date(mulai.Year(), mulai.Month(), mulai.Day(), 0, 0, 0, 0, time.UTC),
+	}
+	if siswa.MeninggalkanSekolah != nil {
+		filter["tanggal_selesai"] = siswa.MeninggalkanSekolah.Tanggal
+	}
+	return filter
+}
+
+// tabel builds a single-header table, printing a dash row when it is empty
+func tabel(judul string, kolom []string, lebar []float64, baris [][]string) bukuIndukBagian {
+	header := make([]bukuIndukKolom, len(kolom))
+	for i, k := range kolom {
+		header[i] = bukuIndukKolom{Label: k, Span: 1}
+	}
+	if len(baris) == 0 {
+		kosong := make([]string, len(kolom))
+		for i := range kosong {
+			kosong[i] = "-"
+		}
+		baris = [][]string{kosong}
+	}
+	return bukuIndukBagian{Judul: judul, Header: [][]bukuIndukKolom{header}, Lebar: lebar, Baris: baris}
+}
+
+func bagianDiriSiswa(siswa *models.Siswa) bukuIndukBagian {
+	jurusan := ""
+	if siswa.KompetensiKeahlian != nil {
+		jurusan = siswa.KompetensiKeahlian.Nama
+		if siswa.KompetensiKeahlian.ProgramKeahlian != nil {
+			jurusan = siswa.KompetensiKeahlian.ProgramKeahlian.Nama + " / " + jurusan
+		}
+	}
+
+	return bukuIndukBagian{
+		Judul: "Keterangan Tentang Diri Peserta Didik",
+		Isian: [][2]string{
+			{"Nomor Induk", siswa.NoInduk},
+			{"NISN", siswa.NISN},
+			{"Nama Lengkap", siswa.NamaLengkap},
+			{"Nama Panggilan", siswa.NamaPanggilan},
+			{"Jenis Kelamin", namaJenisKelamin(siswa.JenisKelamin)},
+			{"Tempat, Tanggal Lahir", siswa.TempatLahir + ", " + tanggalIndonesia(siswa.TanggalLahir)},
+			{"Agama", siswa.Agama},
+			{"Kewarganegaraan", siswa.Kewarganegaraan},
+			{"Anak Ke", strconv.FormatUint(uint64(siswa.AnakKe), 10)},
+			{"Jumlah Saudara", strconv.FormatUint(uint64(siswa.JumlahSaudara), 10)},
+			{"Bahasa Sehari-hari", siswa.BahasaRumah},
+			{"Program / Kompetensi Keahlian", jurusan},
+		},
+	}
+}
+
+func bagianTempatTinggal(alamat *models.AlamatSiswa) bukuIndukBagian {
+	bagian := bukuIndukBagian{Judul: "Keterangan Tempat Tinggal"}
+	if alamat == nil {
+		bagian.Isian = [][2]string{{"Alamat", ""}}
+		return bagian
+	}
+
+	jarak := ""
+	if alamat.JarakKeSekolah > 0 {
+		jarak = strconv.FormatFloat(alamat.JarakKeSekolah, 'f', -1, 64) + " km"
+	}
+	bagian.Isian = [][2]string{
+		{"Alamat", alamat.AlamatLengkap},
+		{"Kelurahan / Desa", alamat.Kelurahan},
+		{"Kecamatan", alamat.Kecamatan},
+		{"Kabupaten / Kota", alamat.Kota},
+		{"Provinsi", alamat.Provinsi},
+		{"Kode Pos", alamat.KodePos},
+		{"Nomor Telepon", alamat.NoTelepon},
+		{"Tinggal Dengan", alamat.TinggalDengan},
+		{"Jarak ke Sekolah", jarak},
+		{"Transportasi", alamat.Transportasi},
+	}
+	return bagian
+}
+
+func bagianKesehatan(kesehatan *models.KesehatanSiswa) bukuIndukBagian {
+	var baris [][]string
+	var isian [][2]string
+	if kesehatan != nil {
+		isian = [][2]string{
+			{"Golongan Darah", kesehatan.GolonganDarah},
+			{"Berat / Tinggi Badan Masuk", ukuranBadan(kesehatan.BeratBadanMasuk, kesehatan.TinggiBadanMasuk)},
+			{"Berat / Tinggi Badan Keluar", ukuranBadan(kesehatan.BeratBadanKeluar, kesehatan.TinggiBadanKeluar)},
+			{"Kesanggupan Jasmani", kesehatan.KesanggupanJasmani},
+		}
+		for i, r := range kesehatan.RiwayatPenyakit {
+			tahun := "-"
+			if r.Tahun > 0 {
+				tahun = strconv.FormatUint(uint64(r.Tahun), 10)
+			}
+			baris = append(baris, []string{strconv.Itoa(i + 1), r.JenisPenyakit, tahun, teksAtauStrip(r.LamaSakit), teksAtauStrip(r.Keterangan)})
+		}
+	} else {
+		isian = [][2]string{{"Golongan Darah", ""}}
+	}
+
+	bagian := tabel("Keterangan Kesehatan", []string{"No", "Riwayat Penyakit", "Tahun", "Lama Sakit", "Keterangan"},
+		[]float64{25, 170, 50, 80, 190}, baris)
+	bagian.Isian = isian
+	return bagian
+}
+
+func bagianPendidikan(pendidikan []models.PendidikanSebelumnya) bukuIndukBagian {
+	var baris [][]string
+	for i, p := range pendidikan {
+		tipe := "Siswa Baru"
+		if p.Tipe == "pindahan" {
+			tipe = "Pindahan"
+		}
+		ijazah := teksAtauStrip(p.NoIjazah)
+		if p.TanggalIjazah != nil {
+			ijazah += "\n" + tanggalIndonesia(*p.TanggalIjazah)
+		}
+		skhun := teksAtauStrip(p.NoSKHUN)
+		if p.TanggalSKHUN != nil {
+			skhun += "\n" + tanggalIndonesia(*p.TanggalSKHUN)
+		}
+		baris = append(baris, []string{
+			strconv.Itoa(i + 1), tipe, p.AsalSekolah + "\n" + p.AlamatSekolah, tanggalIndonesia(p.TanggalDiterima),
+			p.KelasDiterima, ijazah, skhun, teksAtauStrip(p.AlasanPindah),
+		})
+	}
+
+	return tabel("Keterangan Pendidikan Sebelumnya",
+		[]string{"No", "Status", "Asal Sekolah", "Tanggal Diterima", "Kelas", "No. / Tgl. Ijazah", "No. / Tgl. SKHUN", "Alasan Pindah"},
+		[]float64{22, 50, 110, 65, 35, 75, 75, 83}, baris)
+}
+
+func bagianOrangTua(judul string, ortu *models.OrangTua) bukuIndukBagian {
+	bagian := bukuIndukBagian{Judul: judul}
+	if ortu == nil {
+		bagian.Isian = [][2]string{{"Nama", ""}}
+		return bagian
+	}
+
+	status := "Masih hidup"
+	if !ortu.MasihHidup {
+		status = "Meninggal dunia"
+	}
+	bagian.Isian = [][2]string{
+		{"Nama", ortu.Nama},
+		{"Tempat, Tanggal Lahir", tempatTanggal(ortu.TempatLahir, ortu.TanggalLahir)},
+		{"Kewarganegaraan", ortu.Kewarganegaraan},
+		{"Pendidikan Terakhir", ortu.PendidikanTerakhir},
+		{"Pekerjaan", ortu.Pekerjaan},
+		{"Penghasilan per Bulan", rupiah(ortu.PenghasilanBulanan)},
+		{"Alamat", ortu.Alamat},
+		{"Nomor Telepon", ortu.NoTelepon},
+		{"Keterangan", status},
+	}
+	return bagian
+}
+
+func bagianWali(wali *models.Wali) bukuIndukBagian {
+	bagian := bukuIndukBagian{Judul: "Keterangan Tentang Wali"}
+	if wali == nil {
+		bagian.Isian = [][2]string{{"Nama", ""}}
+		return bagian
+	}
+
+	bagian.Isian = [][2]string{
+		{"Nama", wali.Nama},
+		{"Jenis Kelamin", namaJenisKelamin(wali.JenisKelamin)},
+		{"Tempat, Tanggal Lahir", tempatTanggal(wali.TempatLahir, wali.TanggalLahir)},
+		{"Kewarganegaraan", wali.Kewarganegaraan},
+		{"Pendidikan Terakhir", wali.PendidikanTerakhir},
+		{"Pekerjaan", wali.Pekerjaan},
+		{"Penghasilan per Bulan", rupiah(wali.PenghasilanBulanan)},
+		{"Alamat", wali.Alamat},
+		{"Nomor Telepon", wali.NoTelepon},
+		{"Hubungan dengan Peserta Didik", wali.HubunganDenganSiswa},
+	}
+	return bagian
+}
+
+func bagianKepribadian(kepribadian []models.Kepribadian) bukuIndukBagian {
+	sort.SliceStable(kepribadian, func(i, j int) bool {
+		return kepribadian[i].TahunPelajaran < kepribadian[j].TahunPelajaran
+	})
+
+	var baris [][]string
+	for i, k := range kepribadian {
+		baris = append(baris, []string{strconv.Itoa(i + 1), teksAtauStrip(k.TahunPelajaran), k.Aspek, k.Nilai})
+	}
+	return tabel("Kepribadian", []string{"No", "Tahun Pelajaran", "Aspek", "Nilai"},
+		[]float64{25, 100, 290, 100}, baris)
+}
+
+func bagianPrestasi(prestasi []models.Prestasi) bukuIndukBagian {
+	var baris [][]string
+	for i, p := range prestasi {
+		tahun := "-"
+		if p.Tahun > 0 {
+			tahun = strconv.FormatUint(uint64(p.Tahun), 10)
+		}
+		baris = append(baris, []string{strconv.Itoa(i + 1), p.Bidang, teksAtauStrip(p.Tingkat), tahun, teksAtauStrip(p.Keterangan)})
+	}
+	return tabel("Prestasi", []string{"No", "Bidang", "Tingkat", "Tahun", "Keterangan"},
+		[]float64{25, 90, 80, 50, 270}, baris)
+}
+
+func bagianBeasiswa(beasiswa []models.Beasiswa) bukuIndukBagian {
+	var baris [][]string
+	for i, b := range beasiswa {
+		baris = append(baris, []string{strconv.Itoa(i + 1), b.TahunPelajaran, b.Pemberi, teksAtauStrip(b.Keterangan)})
+	}
+	return tabel("Beasiswa", []string{"No", "Tahun Pelajaran", "Pemberi", "Keterangan"},
+		[]float64{25, 90, 150, 250}, baris)
+}
+
+// semesterBukuInduk lists the six semesters printed as columns of the grade table
+var semesterBukuInduk = []struct {
+	Kelas    string
+	Semester uint8
+}{{"X", 1}, {"X", 2}, {"XI", 1}, {"XI", 2}, {"XII", 1}, {"XII", 2}}
+
+// bagianNilaiSemester lays out all grades as one row per subject and a
+// knowledge/skill column pair per semester. When a kelas was repeated, the
+// grades of the latest academic year are printed.
+func bagianNilaiSemester(nilai []models.NilaiSemester) bukuIndukBagian {
+	type kunci struct {
+		mapelID  uint
+		kelas    string
+		semester uint8
+	}
+	terbaru := make(map[kunci]models.NilaiSemester)
+	mapel := make(map[uint]*models.MataPelajaran)
+	for _, n := range nilai {
+		if n.MataPelajaran == nil {
+			continue
+		}
+		k := kunci{n.MataPelajaranID, n.Kelas, n.Semester}
+		if lama, ok := terbaru[k]; !ok || n.TahunPelajaran > lama.TahunPelajaran {
+			terbaru[k] = n
+		}
+		mapel[n.MataPelajaranID] = n.MataPelajaran
+	}
+
+	daftar := make([]*models.MataPelajaran, 0, len(mapel))
+	for _, m := range mapel {
+		daftar = append(daftar, m)
+	}
+	sort.Slice(daftar, func(i, j int) bool {
+		a, b := daftar[i], daftar[j]
+		if a.Kelompok != b.Kelompok {
+			return a.Kelompok < b.Kelompok
+		}
+		if a.SubKelompok != b.SubKelompok {
+			return a.SubKelompok < b.SubKelompok
+		}
+		return a.Kode < b.Kode
+	})
+
+	atas := []bukuIndukKolom{{Label: "No", Span: 1}, {Label: "Mata Pelajaran", Span: 1}}
+	bawah := []bukuIndukKolom{{Span: 1}, {Span: 1}}
+	lebar := []float64{20, 135}
+	for _, sm := range semesterBukuInduk {
+		atas = append(atas, bukuIndukKolom{Label: fmt.Sprintf("%s / %d", sm.Kelas, sm.Semester), Span: 2})
+		bawah = append(bawah, bukuIndukKolom{Label: "P", Span: 1}, bukuIndukKolom{Label: "K", Span: 1})
+		lebar = append(lebar, 30, 30)
+	}
+
+	var baris [][]string
+	kelompok := ""
+	for i, m := range daftar {
+		if m.Kelompok != kelompok {
+			kelompok = m.Kelompok
+			label, ok := namaKelompok[kelompok]
+			if !ok {
+				label = "Kelompok " + kelompok
+			}
+			baris = append(baris, []string{label})
+		}
+
+		row := []string{strconv.Itoa(i + 1), m.Nama}
+		for _, sm := range semesterBukuInduk {
+			if n, ok := terbaru[kunci{m.ID, sm.Kelas, sm.Semester}]; ok {
+				row = append(row, strconv.FormatUint(uint64(n.NilaiPengetahuan), 10), strconv.FormatUint(uint64(n.NilaiKeterampilan), 10))
+			} else {
+				row = append(row, "-", "-")
+			}
+		}
+		baris = append(baris, row)
+	}
+	if len(baris) == 0 {
+		baris = [][]string{{"Belum ada nilai"}}
+	}
+
+	return bukuIndukBagian{
+		Judul:  "Nilai Semester (P = Pengetahuan, K = Keterampilan)",
+		Header: [][]bukuIndukKolom{atas, bawah},
+		Lebar:  lebar,
+		Baris:  baris,
+	}
+}
+
+func bagianSikap(sikap []models.NilaiSikap) bukuIndukBagian {
+	sort.SliceStable(sikap, func(i, j int) bool {
+		if sikap[i].Kelas != sikap[j].Kelas {
+			return sikap[i].Kelas < sikap[j].Kelas
+		}
+		return sikap[i].Semester < sikap[j].Semester
+	})
+
+	var baris [][]string
+	for _, s := range sikap {
+		baris = append(baris, []string{fmt.Sprintf("%s / %d", s.Kelas, s.Semester), teksAtauStrip(s.DeskripsiSpiritual), teksAtauStrip(s.DeskripsiSosial)})
+	}
+	return tabel("Sikap", []string{"Kelas / Smt", "Sikap Spiritual", "Sikap Sosial"},
+		[]float64{60, 227, 228}, baris)
+}
+
+func bagianKetidakhadiran(kehadiran []models.Kehadiran) bukuIndukBagian {
+	sort.SliceStable(kehadiran, func(i, j int) bool {
+		if kehadiran[i].Kelas != kehadiran[j].Kelas {
+			return kehadiran[i].Kelas < kehadiran[j].Kelas
+		}
+		return kehadiran[i].Semester < kehadiran[j].Semester
+	})
+
+	var baris [][]string
+	for _, k := range kehadiran {
+		baris = append(baris, []string{
+			fmt.Sprintf("%s / %d", k.Kelas, k.Semester),
+			strconv.FormatUint(uint64(k.JumlahSakit), 10),
+			strconv.FormatUint(uint64(k.JumlahIzin), 10),
+			strconv.FormatUint(uint64(k.JumlahAlpa), 10),
+			strconv.FormatFloat(k.PersentaseHadir, 'f', 2, 64) + "%",
+		})
+	}
+	return tabel("Ketidakhadiran", []string{"Kelas / Smt", "Sakit", "Izin", "Tanpa Keterangan", "Persentase Hadir"},
+		[]float64{95, 95, 95, 115, 115}, baris)
+}
+
+func bagianKenaikanKelas(kenaikan []models.KenaikanKelas) bukuIndukBagian {
+	var baris [][]string
+	for i, k := range kenaikan {
+		baris = append(baris, []string{
+			strconv.Itoa(i + 1), k.TahunPelajaran, k.TingkatAsal, k.TingkatTujuan, k.Keputusan, tanggalIndonesia(k.Tanggal), teksAtauStrip(k.Catatan),
+		})
+	}
+	return tabel("Kenaikan Kelas", []string{"No", "Tahun Pelajaran", "Dari Kelas", "Ke Kelas", "Keputusan", "Tanggal", "Catatan"},
+		[]float64{25, 80, 50, 50, 60, 90, 160}, baris)
+}
+
+func bagianNilaiIjazah(ijazah []models.NilaiIjazah) bukuIndukBagian {
+	var baris [][]string
+	noIjazah, tahunLulus, tanggalLulus := "", "", ""
+	for i, n := range ijazah {
+		nama := "-"
+		if n.MataPelajaran != nil {
+			nama = n.MataPelajaran.Nama
+		}
+		baris = append(baris, []string{strconv.Itoa(i + 1), nama, strconv.FormatUint(uint64(n.NilaiAkhir), 10)})
+
+		if n.NoIjazah != "" {
+			noIjazah = n.NoIjazah
+		}
+		if n.TahunLulus != "" {
+			tahunLulus = n.TahunLulus
+		}
+		if n.TanggalLulus != nil {
+			tanggalLulus = tanggalIndonesia(*n.TanggalLulus)
+		}
+	}
+
+	bagian := tabel("Nilai Ijazah", []string{"No", "Mata Pelajaran", "Nilai Akhir"},
+		[]float64{25, 390, 100}, baris)
+	bagian.Isian = [][2]string{
+		{"Nomor Ijazah", noIjazah},
+		{"Tahun Lulus", tahunLulus},
+		{"Tanggal Lulus", tanggalLulus},
+	}
+	return bagian
+}
+
+func bagianMeninggalkanSekolah(m *models.MeninggalkanSekolah) bukuIndukBagian {
+	bagian := bukuIndukBagian{Judul: "Meninggalkan Sekolah"}
+	if m == nil {
+		bagian.Isian = [][2]string{{"Keterangan", "Masih bersekolah"}}
+		return bagian
+	}
+
+	keterangan := map[string]string{"tamat": "Tamat belajar", "pindah": "Pindah sekolah", "putus": "Putus sekolah"}[m.Tipe]
+	bagian.Isian = [][2]string{
+		{"Keterangan", keterangan},
+		{"Tanggal", tanggalIndonesia(m.Tanggal)},
+		{"Nomor Ijazah", m.NoIjazah},
+		{"Sekolah Tujuan", m.SekolahTujuan},
+		{"Alamat Sekolah Tujuan", m.AlamatSekolahTujuan},
+		{"Alasan", m.Alasan},
+	}
+	return bagian
+}
+
+func bagianPemeriksaan(pemeriksaan []models.PemeriksaanBuku) bukuIndukBagian {
+	var baris [][]string
+	for _, p := range pemeriksaan {
+		baris = append(baris, []string{
+			strconv.FormatUint(uint64(p.NoUrut), 10), tanggalIndonesia(p.Tanggal), p.NamaPemeriksa, teksAtauStrip(p.Jabatan), teksAtauStrip(p.Keterangan), "",
+		})
+	}
+	return tabel("Pemeriksaan Buku Induk", []string{"No", "Tanggal", "Nama Pemeriksa", "Jabatan", "Keterangan", "Paraf"},
+		[]float64{25, 80, 110, 90, 150, 60}, baris)
+}
+
+func namaJenisKelamin(jk string) string {
+	switch jk {
+	case "L":
+		return "Laki-laki"
+	case "P":
+		return "Perempuan"
+	}
+	return jk
+}
+
+func tempatTanggal(tempat string, tanggal *time.Time) string {
+	if tanggal == nil {
+		return tempat
+	}
+	if tempat == "" {
+		return tanggalIndonesia(*tanggal)
+	}
+	return tempat + ", " + tanggalIndonesia(*tanggal)
+}
+
+func ukuranBadan(berat, tinggi float64) string {
+	if berat == 0 && tinggi == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(berat, 'f', -1, 64) + " kg / " + strconv.FormatFloat(tinggi, 'f', -1, 64) + " cm"
+}
+
+// rupiah formats an amount as "Rp 1.500.000"
+func rupiah(jumlah float64) string {
+	if jumlah <= 0 {
+		return ""
+	}
+	digits := strconv.FormatFloat(jumlah, 'f', 0, 64)
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(d)
+	}
+	return "Rp " + sb.String()
+}
+
+var bukuIndukTemplate = template.Must(template.New("buku-induk").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Buku Induk - {{.Siswa.NamaLengkap}}</title>
+<style>
+  @page { size: A4; margin: 15mm; }
+  body { font-family: Helvetica, Arial, sans-serif; font-size: 10pt; color: #000; max-width: 190mm; margin: 0 auto; }
+  .kop { text-align: center; border-bottom: 1px solid #000; padding-bottom: 4pt; }
+  .kop h1 { font-size: 14pt; margin: 0; }
+  .kop p { font-size: 9pt; margin: 2pt 0; }
+  h2 { font-size: 12pt; text-align: center; }
+  h3 { font-size: 10pt; margin: 12pt 0 4pt; page-break-after: avoid; }
+  .foto { float: right; width: 30mm; height: 40mm; border: 1px solid #000; object-fit: cover; }
+  table { border-collapse: collapse; width: 100%; font-size: 8pt; }
+  table.isian { font-size: 9pt; width: auto; }
+  table.isian td { padding: 1pt 4pt 1pt 0; vertical-align: top; }
+  table.isian td:first-child { width: 150pt; }
+  table.tabel { margin-top: 4pt; }
+  table.tabel th, table.tabel td { border: 1px solid #000; padding: 2pt 3pt; vertical-align: top; white-space: pre-line; }
+  table.tabel th { background: #e6e6e6; text-align: center; }
+  table.tabel td.grup { font-weight: bold; }
+  tr { page-break-inside: avoid; }
+  .dicetak { text-align: right; font-size: 8pt; margin-top: 12pt; }
+</style>
+</head>
+<body>
+<div class="kop">
+  <h1>{{.Sekolah.Name}}</h1>
+  <p>{{.Sekolah.Address}}{{if .Sekolah.NPSN}} - NPSN {{.Sekolah.NPSN}}{{end}}</p>
+</div>
+<h2>BUKU INDUK PESERTA DIDIK</h2>
+{{if .Foto}}{{with .FotoURI}}<img class="foto" src="{{.}}" alt="Foto">{{else}}<div class="foto"></div>{{end}}{{else}}<div class="foto"></div>{{end}}
+{{range .Bagian}}
+<h3>{{.Judul}}</h3>
+{{if .Isian}}<table class="isian">
+{{range .Isian}}  <tr><td>{{index . 0}}</td><td>: {{if index . 1}}{{index . 1}}{{else}}-{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Lebar}}{{$kolom := .JumlahKolom}}<table class="tabel">
+{{range .Header}}  <tr>{{range .}}<th{{if gt .Span 1}} colspan="{{.Span}}"{{end}}>{{.Label}}</th>{{end}}</tr>
+{{end}}{{range .Baris}}  <tr>{{if eq (len .) 1}}<td class="grup" colspan="{{$kolom}}">{{index . 0}}</td>{{else}}{{range .}}<td>{{.}}</td>{{end}}{{end}}</tr>
+{{end}}</table>{{end}}
+{{end}}
+<p class="dicetak">Dicetak pada {{.Dicetak}}</p>
+</body>
+</html>
+`))
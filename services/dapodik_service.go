@@ -0,0 +1,713 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kampunk/api-siswa/configs"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// DapodikService exports students in the Dapodik peserta didik layout and
+// reconciles Dapodik exports with our records
+type DapodikService struct {
+	siswaRepo  *repositories.SiswaRepository
+	rombelRepo *repositories.RombelRepository
+	tahunRepo  *repositories.TahunPelajaranRepository
+}
+
+// NewDapodikService creates a new DapodikService
+func NewDapodikService(
+	siswaRepo *repositories.SiswaRepository,
+	rombelRepo *repositories.RombelRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+) *DapodikService {
+	return &DapodikService{
+		siswaRepo:  siswaRepo,
+		rombelRepo: rombelRepo,
+		tahunRepo:  tahunRepo,
+	}
+}
+
+// dapodikSiswa is a student with the data of one Dapodik row
+type dapodikSiswa struct {
+	*models.Siswa
+	Rombel string
+}
+
+// dapodikKolom is one column of the Dapodik peserta didik spreadsheet. Grouped
+// columns (Data Ayah, Data Ibu, Data Wali) have a sub title. Columns without get
+// are exported blank. Only columns with a field are compared, and only columns
+// with set can take over the Dapodik value.
+type dapodikKolom struct {
+	judul string
+	sub   string
+	field string
+	get   func(s *dapodikSiswa) string
+	set   func(s *dapodikSiswa, v string) error
+}
+
+// label returns the column title as shown in Dapodik, e.g. "Data Ayah - Nama"
+func (k dapodikKolom) label() string {
+	if k.sub == "" {
+		return k.judul
+	}
+	return k.judul + " - " + k.sub
+}
+
+// nilai returns our value of the column as plain text
+func (k dapodikKolom) nilai(s *dapodikSiswa) string {
+	if k.get == nil {
+		return ""
+	}
+	return html.UnescapeString(k.get(s))
+}
+
+// dapodikKolomSiswa follows the column order of the Dapodik peserta didik export
+var dapodikKolomSiswa = concatKolom(
+	[]dapodikKolom{
+		kolomTeks("Nama", "", "nama_lengkap", func(s *dapodikSiswa, _ bool) *string { return &s.NamaLengkap }),
+		{judul: "NIPD", field: "no_induk", get: func(s *dapodikSiswa) string { return s.NoInduk }},
+		{judul: "JK", field: "jenis_kelamin", get: func(s *dapodikSiswa) string { return s.JenisKelamin }, set: func(s *dapodikSiswa, v string) error {
+			v = strings.ToUpper(v)
+			if v != "L" && v != "P" {
+				return errors.New("gender must be L or P")
+			}
+			s.JenisKelamin = v
+			return nil
+		}},
+		{judul: "NISN", get: func(s *dapodikSiswa) string { return s.NISN }},
+		kolomTeks("Tempat Lahir", "", "tempat_lahir", func(s *dapodikSiswa, _ bool) *string { return &s.TempatLahir }),
+		{judul: "Tanggal Lahir", field: "tanggal_lahir", get: func(s *dapodikSiswa) string { return s.TanggalLahir.Format("2006-01-02") }, set: func(s *dapodikSiswa, v string) error {
+			tanggal, err := time.Parse("2006-01-02", tanggalSpreadsheet(v))
+			if err != nil {
+				return errors.New("invalid date format, use YYYY-MM-DD")
+			}
+			s.TanggalLahir = tanggal
+			return nil
+		}},
+		{judul: "NIK"},
+		kolomTeks("Agama", "", "agama", func(s *dapodikSiswa, _ bool) *string { return &s.Agama }),
+		kolomTeks("Alamat", "", "alamat_lengkap", func(s *dapodikSiswa, buat bool) *string {
+			if a := s.alamat(buat); a != nil {
+				return &a.AlamatLengkap
+			}
+			return nil
+		}),
+		{judul: "RT"},
+		{judul: "RW"},
+		{judul: "Dusun"},
+		kolomTeks("Kelurahan", "", "alamat_kelurahan", func(s *dapodikSiswa, buat bool) *string {
+			if a := s.alamat(buat); a != nil {
+				return &a.Kelurahan
+			}
+			return nil
+		}),
+		kolomTeks("Kecamatan", "", "alamat_kecamatan", func(s *dapodikSiswa, buat bool) *string {
+			if a := s.alamat(buat); a != nil {
+				return &a.Kecamatan
+			}
+			return nil
+		}),
+		kolomTeks("Kode Pos", "", "alamat_kode_pos", func(s *dapodikSiswa, buat bool) *string {
+			if a := s.alamat(buat); a != nil {
+				return &a.KodePos
+			}
+			return nil
+		}),
+		kolomTeks("Jenis Tinggal", "", "alamat_tinggal_dengan", func(s *dapodikSiswa, buat bool) *string {
+			if a := s.alamat(buat); a != nil {
+				return &a.TinggalDengan
+			}
+			return nil
+		}),
+		kolomTeks("Alat Transportasi", "", "alamat_transportasi", func(s *dapodikSiswa, buat bool) *string {
+			if a := s.alamat(buat); a != nil {
+				return &a.Transportasi
+			}
+			return nil
+		}),
+		kolomTeks("Telepon", "", "alamat_no_telepon", func(s *dapodikSiswa, buat bool) *string {
+			if a := s.alamat(buat); a != nil {
+				return &a.NoTelepon
+			}
+			return nil
+		}),
+		{judul: "HP"},
+		{judul: "E-Mail"},
+		{judul: "SKHUN", field: "no_skhun", get: func(s *dapodikSiswa) string {
+			if p := s.pendidikanAwal(); p != nil {
+				return p.NoSKHUN
+			}
+			return ""
+		}},
+		{judul: "Penerima KPS"},
+		{judul: "No. KPS"},
+	},
+	kolomOrangTua("Data Ayah", "ayah"),
+	kolomOrangTua("Data Ibu", "ibu"),
+	kolomWali(),
+	[]dapodikKolom{
+		{judul: "Rombel Saat Ini", get: func(s *dapodikSiswa) string { return s.Rombel }},
+		{judul: "No Peserta Ujian Nasional"},
+		{judul: "No Seri Ijazah", field: "no_ijazah", get: func(s *dapodikSiswa) string {
+			if p := s.pendidikanAwal(); p != nil {
+				return p.NoIjazah
+			}
+			return ""
+		}},
+		{judul: "Penerima KIP"},
+		{judul: "Nomor KIP"},
+		{judul: "Nama di KIP"},
+		{judul: "Nomor KKS"},
+		{judul: "No Registrasi Akta Lahir"},
+		{judul: "Bank"},
+		{judul: "Nomor Rekening Bank"},
+		{judul: "Rekening Atas Nama"},
+		{judul: "Layak PIP (usulan dari sekolah)"},
+		{judul: "Alasan Layak PIP"},
+		{judul: "Kebutuhan Khusus"},
+		{judul: "Sekolah Asal", field: "asal_sekolah", get: func(s *dapodikSiswa) string {
+			if p := s.pendidikanAwal(); p != nil {
+				return p.AsalSekolah
+			}
+			return ""
+		}},
+		{judul: "Anak ke-berapa", field: "anak_ke", get: func(s *dapodikSiswa) string { return strconv.FormatUint(uint64(s.AnakKe), 10) }, set: func(s *dapodikSiswa, v string) error {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err != nil || n == 0 {
+				return errors.New("must be a whole number from 1")
+			}
+			s.AnakKe = uint(n)
+			return nil
+		}},
+		{judul: "Lintang"},
+		{judul: "Bujur"},
+		{judul: "No KK"},
+		{judul: "Berat Badan"},
+		{judul: "Tinggi Badan"},
+		{judul: "Lingkar Kepala"},
+		{judul: "Jml. Saudara Kandung", field: "jumlah_saudara", get: func(s *dapodikSiswa) string { return strconv.FormatUint(uint64(s.JumlahSaudara), 10) }, set: func(s *dapodikSiswa, v string) error {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				return errors.New("must be a whole number")
+			}
+			s.JumlahSaudara = uint(n)
+			return nil
+		}},
+		{judul: "Jarak Rumah ke Sekolah (KM)", field: "alamat_jarak_ke_sekolah", get: func(s *dapodikSiswa) string {
+			if s.Alamat == nil || s.Alamat.JarakKeSekolah == 0 {
+				return ""
+			}
+			return strconv.FormatFloat(s.Alamat.JarakKeSekolah, 'f', -1, 64)
+		}, set: func(s *dapodikSiswa, v string) error {
+			jarak, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", "."), 64)
+			if err != nil || jarak < 0 {
+				return errors.New("must be a number")
+			}
+			s.alamat(true).JarakKeSekolah = jarak
+			return nil
+		}},
+	},
+)
+
+// kolomOrangTua returns the Data Ayah or Data Ibu column group
+func kolomOrangTua(judul, tipe string) []dapodikKolom {
+	orangTua := func(s *dapodikSiswa, buat bool) *models.OrangTua {
+		for i := range s.OrangTua {
+			if s.OrangTua[i].Tipe == tipe {
+				return &s.OrangTua[i]
+			}
+		}
+		if !buat {
+			return nil
+		}
+		s.OrangTua = append(s.OrangTua, models.OrangTua{Tipe: tipe, MasihHidup: true})
+		return &s.OrangTua[len(s.OrangTua)-1]
+	}
+
+	return []dapodikKolom{
+		kolomTeks(judul, "Nama", tipe+"_nama", func(s *dapodikSiswa, buat bool) *string {
+			if o := orangTua(s, buat); o != nil {
+				return &o.Nama
+			}
+			return nil
+		}),
+		{judul: judul, sub: "Tahun Lahir", field: tipe + "_tahun_lahir", get: func(s *dapodikSiswa) string {
+			if o := orangTua(s, false); o != nil && o.TanggalLahir != nil {
+				return strconv.Itoa(o.TanggalLahir.Year())
+			}
+			return ""
+		}},
+		kolomTeks(judul, "Jenjang Pendidikan", tipe+"_pendidikan_terakhir", func(s *dapodikSiswa, buat bool) *string {
+			if o := orangTua(s, buat); o != nil {
+				return &o.PendidikanTerakhir
+			}
+			return nil
+		}),
+		kolomTeks(judul, "Pekerjaan", tipe+"_pekerjaan", func(s *dapodikSiswa, buat bool) *string {
+			if o := orangTua(s, buat); o != nil {
+				return &o.Pekerjaan
+			}
+			return nil
+		}),
+		{judul: judul, sub: "Penghasilan", field: tipe + "_penghasilan", get: func(s *dapodikSiswa) string {
+			if o := orangTua(s, false); o != nil {
+				return rentangPenghasilan(o.PenghasilanBulanan)
+			}
+			return ""
+		}},
+		{judul: judul, sub: "NIK"},
+	}
+}
+
+// kolomWali returns the Data Wali column group. Guardian values can only be
+// taken from Dapodik when the student already has guardian data, since
+// Dapodik does not record the guardian's gender.
+func kolomWali() []dapodikKolom {
+	const judul = "Data Wali"
+
+	return []dapodikKolom{
+		kolomTeks(judul, "Nama", "wali_nama", func(s *dapodikSiswa, _ bool) *string {
+			if s.Wali != nil {
+				return &s.Wali.Nama
+			}
+			return nil
+		}),
+		{judul: judul, sub: "Tahun Lahir", field: "wali_tahun_lahir", get: func(s *dapodikSiswa) string {
+			if s.Wali != nil && s.Wali.TanggalLahir != nil {
+				return strconv.Itoa(s.Wali.TanggalLahir.Year())
+			}
+			return ""
+		}},
+		kolomTeks(judul, "Jenjang Pendidikan", "wali_pendidikan_terakhir", func(s *dapodikSiswa, _ bool) *string {
+			if s.Wali != nil {
+				return &s.Wali.PendidikanTerakhir
+			}
+			return nil
+		}),
+		kolomTeks(judul, "Pekerjaan", "wali_pekerjaan", func(s *dapodikSiswa, _ bool) *string {
+			if s.Wali != nil {
+				return &s.Wali.Pekerjaan
+			}
+			return nil
+		}),
+		{judul: judul, sub: "Penghasilan", field: "wali_penghasilan", get: func(s *dapodikSiswa) string {
+			if s.Wali != nil {
+				return rentangPenghasilan(s.Wali.PenghasilanBulanan)
+			}
+			return ""
+		}},
+		{judul: judul, sub: "NIK"},
+	}
+}
+
+// kolomTeks builds a text column. field returns the model field, creating the
+// related record when buat is true; it returns nil when there is no record.
+func kolomTeks(judul, sub, name string, field func(s *dapodikSiswa, buat bool) *string) dapodikKolom {
+	return dapodikKolom{
+		judul: judul,
+		sub:   sub,
+		field: name,
+		get: func(s *dapodikSiswa) string {
+			if p := field(s, false); p != nil {
+				return *p
+			}
+			return ""
+		},
+		set: func(s *dapodikSiswa, v string) error {
+			p := field(s, true)
+			if p == nil {
+				return errors.New("student has no guardian data, add it first")
+			}
+			*p = utils.SanitizeString(v)
+			return nil
+		},
+	}
+}
+
+func concatKolom(groups ...[]dapodikKolom) []dapodikKolom {
+	var kolom []dapodikKolom
+	for _, g := range groups {
+		kolom = append(kolom, g...)
+	}
+	return kolom
+}
+
+// alamat returns the address of the student, creating an empty one when buat is true
+func (s *dapodikSiswa) alamat(buat bool) *models.AlamatSiswa {
+	if s.Alamat == nil && buat {
+		s.Alamat = &models.AlamatSiswa{}
+	}
+	return s.Alamat
+}
+
+// pendidikanAwal returns the earliest previous education record
+func (s *dapodikSiswa) pendidikanAwal() *models.PendidikanSebelumnya {
+	if len(s.PendidikanSebelumnya) == 0 {
+		return nil
+	}
+	return &s.PendidikanSebelumnya[0]
+}
+
+// rentangPenghasilan maps a monthly income to the Dapodik income range
+func rentangPenghasilan(penghasilan float64) string {
+	switch {
+	case penghasilan <= 0:
+		return ""
+	case penghasilan < 500000:
+		return "Kurang dari Rp. 500,000"
+	case penghasilan < 1000000:
+		return "Rp. 500,000 - Rp. 999,999"
+	case penghasilan < 2000000:
+		return "Rp. 1,000,000 - Rp. 1,999,999"
+	case penghasilan < 5000000:
+		return "Rp. 2,000,000 - Rp. 4,999,999"
+	case penghasilan <= 20000000:
+		return "Rp. 5,000,000 - Rp. 20,000,000"
+	}
+	return "Lebih dari Rp. 20,000,000"
+}
+
+// Export renders the students matching the student list search and filters in
+// the Dapodik peserta didik spreadsheet layout
+func (s *DapodikService) Export(search string, filter requests.SiswaFilterRequest) ([]byte, string, error) {
+	siswa, err := s.siswaRepo.FindAllWithRelations(search, toSiswaFilterMap(filter))
+	if err != nil {
+		return nil, "", err
+	}
+	rombel, err := s.rombelSaatIni()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Four title rows, then the column titles with the sub titles of the grouped columns below
+	jumlahKolom := len(dapodikKolomSiswa) + 1
+	akhir := utils.XLSXColumn(jumlahKolom - 1)
+	rows := [][]interface{}{
+		{"Daftar Peserta Didik"},
+		{configs.AppConfig.School.Name},
+		{configs.AppConfig.School.City},
+		{"Tanggal Unduh: " + time.Now().Format("2006-01-02 15:04:05")},
+	}
+	merges := []string{"A1:" + akhir + "1", "A2:" + akhir + "2", "A3:" + akhir + "3", "A4:" + akhir + "4", "A5:A6"}
+	judul := []interface{}{"No"}
+	sub := []interface{}{nil}
+	widths := []float64{5}
+	for i, k := range dapodikKolomSiswa {
+		col := utils.XLSXColumn(i + 1)
+		widths = append(widths, float64(max(len(k.judul), len(k.sub), 8)+2))
+		if k.sub == "" {
+			judul = append(judul, k.judul)
+			sub = append(sub, nil)
+			merges = append(merges, col+"5:"+col+"6")
+			continue
+		}
+		sub = append(sub, k.sub)
+		if i > 0 && dapodikKolomSiswa[i-1].judul == k.judul {
+			judul = append(judul, nil)
+			continue
+		}
+		judul = append(judul, k.judul)
+		n := 1
+		for n < len(dapodikKolomSiswa)-i && dapodikKolomSiswa[i+n].judul == k.judul {
+			n++
+		}
+		merges = append(merges, col+"5:"+utils.XLSXColumn(i+n)+"5")
+	}
+	rows = append(rows, judul, sub)
+
+	for i := range siswa {
+		data := &dapodikSiswa{Siswa: &siswa[i], Rombel: rombel[siswa[i].ID]}
+		row := []interface{}{i + 1}
+		for _, k := range dapodikKolomSiswa {
+			row = append(row, k.nilai(data))
+		}
+		rows = append(rows, row)
+	}
+
+	xlsx, err := utils.WriteXLSX(utils.XLSXSheet{
+		Name:       "Daftar Peserta Didik",
+		Rows:       rows,
+		HeaderRows: 6,
+		Widths:     widths,
+		Merges:     merges,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("daftar-peserta-didik-%s.xlsx", time.Now().Format("2006-01-02"))
+	return xlsx, filename, nil
+}
+
+// rombelSaatIni maps student IDs to their class group name in the active academic year
+func (s *DapodikService) rombelSaatIni() (map[uint]string, error) {
+	rombel := make(map[uint]string)
+	tahun, err := s.tahunRepo.FindActive()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rombel, nil
+		}
+		return nil, err
+	}
+	anggota, err := s.rombelRepo.FindAnggotaByTahun(tahun.Label, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range anggota {
+		if a.Rombel != nil {
+			rombel[a.SiswaID] = a.Rombel.Nama
+		}
+	}
+	return rombel, nil
+}
+
+// Compare matches the rows of a Dapodik peserta didik export to our students by
+// NISN and lists the fields that differ
+func (s *DapodikService) Compare(file *multipart.FileHeader) (*responses.DapodikCompareResponse, error) {
+	data, err := utils.ReadUploadedFile(file)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := utils.ReadSpreadsheet(file.Filename, data)
+	if err != nil {
+		return nil, err
+	}
+	kolom, mulai, err := bacaHeaderDapodik(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	kolomNISN, kolomNama := -1, -1
+	for i, k := range kolom {
+		switch {
+		case k == nil:
+		case k.judul == "NISN":
+			kolomNISN = i
+		case k.field == "nama_lengkap":
+			kolomNama = i
+		}
+	}
+	sel := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var nisn []string
+	for _, row := range rows[mulai:] {
+		if v := sel(row, kolomNISN); v != "" {
+			nisn = append(nisn, v)
+		}
+	}
+	siswa, err := s.siswaRepo.FindByNISNsWithRelations(nisn)
+	if err != nil {
+		return nil, err
+	}
+	byNISN := make(map[string]*models.Siswa, len(siswa))
+	for i := range siswa {
+		byNISN[siswa[i].NISN] = &siswa[i]
+	}
+
+	resp := &responses.DapodikCompareResponse{Siswa: []responses.DapodikSiswaResponse{}}
+	for i, row := range rows[mulai:] {
+		if barisKosong(row) {
+			continue
+		}
+		resp.TotalBaris++
+
+		item := responses.DapodikSiswaResponse{
+			Baris:     mulai + i + 1,
+			NISN:      sel(row, kolomNISN),
+			Nama:      sel(row, kolomNama),
+			Perbedaan: []responses.DapodikPerbedaanResponse{},
+		}
+		found, ok := byNISN[item.NISN]
+		if !ok {
+			item.Status = "tidak_ditemukan"
+			resp.TidakDitemukan++
+			resp.Siswa = append(resp.Siswa, item)
+			continue
+		}
+		item.SiswaID = &found.ID
+
+		data := &dapodikSiswa{Siswa: found}
+		for c, k := range kolom {
+			if k == nil || k.field == "" {
+				continue
+			}
+			sistem, dapodik := k.nilai(data), sel(row, c)
+			if k.field == "tanggal_lahir" {
+				dapodik = tanggalSpreadsheet(dapodik)
+			}
+			if samaDapodik(sistem, dapodik) {
+				continue
+			}
+			item.Perbedaan = append(item.Perbedaan, responses.DapodikPerbedaanResponse{
+				Field:           k.field,
+				Kolom:           k.label(),
+				NilaiSistem:     sistem,
+				NilaiDapodik:    dapodik,
+				DapatDiterapkan: k.set != nil && dapodik != "",
+			})
+		}
+		if len(item.Perbedaan) == 0 {
+			resp.Cocok++
+			continue
+		}
+		item.Status = "berbeda"
+		resp.Berbeda++
+		resp.Siswa = append(resp.Siswa, item)
+	}
+
+	return resp, nil
+}
+
+// Apply takes over the Dapodik values of the fields where Dapodik was chosen.
+// Fields where our value was chosen are left unchanged, so the next export
+// carries them to Dapodik. Every student is saved in one transaction.
+func (s *DapodikService) Apply(req requests.DapodikApplyRequest) (*responses.DapodikApplyResponse, error) {
+	nisn := make([]string, len(req.Siswa))
+	for i, item := range req.Siswa {
+		nisn[i] = item.NISN
+	}
+	siswa, err := s.siswaRepo.FindByNISNsWithRelations(nisn)
+	if err != nil {
+		return nil, err
+	}
+	byNISN := make(map[string]*models.Siswa, len(siswa))
+	for i := range siswa {
+		byNISN[siswa[i].NISN] = &siswa[i]
+	}
+	kolomByField := make(map[string]*dapodikKolom)
+	for i := range dapodikKolomSiswa {
+		if dapodikKolomSiswa[i].field != "" {
+			kolomByField[dapodikKolomSiswa[i].field] = &dapodikKolomSiswa[i]
+		}
+	}
+
+	resp := &responses.DapodikApplyResponse{}
+	var diperbarui []*models.Siswa
+	berubah := make(map[uint]bool)
+	for _, item := range req.Siswa {
+		found, ok := byNISN[item.NISN]
+		if !ok {
+			return nil, fmt.Errorf("student with NISN %s not found", item.NISN)
+		}
+
+		data := &dapodikSiswa{Siswa: found}
+		for _, pilihan := range item.Pilihan {
+			k, ok := kolomByField[pilihan.Field]
+			if !ok {
+				return nil, fmt.Errorf("NISN %s: unknown field %q", item.NISN, pilihan.Field)
+			}
+			if pilihan.Sumber == "sistem" {
+				resp.DipertahankanSistem++
+				continue
+			}
+
+			if k.set == nil {
+				return nil, fmt.Errorf("NISN %s: %s cannot be taken from Dapodik", item.NISN, pilihan.Field)
+			}
+			value := strings.TrimSpace(pilihan.NilaiDapodik)
+			if value == "" {
+				return nil, fmt.Errorf("NISN %s: %s: an empty Dapodik value cannot be applied", item.NISN, pilihan.Field)
+			}
+			if err := k.set(data, value); err != nil {
+				return nil, fmt.Errorf("NISN %s: %s: %w", item.NISN, pilihan.Field, err)
+			}
+			resp.DiambilDariDapodik++
+
+			if !berubah[found.ID] {
+				berubah[found.ID] = true
+				diperbarui = append(diperbarui, found)
+			}
+		}
+	}
+
+	if len(diperbarui) > 0 {
+		if err := s.siswaRepo.UpdateBatchWithRelations(diperbarui); err != nil {
+			return nil, err
+		}
+	}
+	resp.SiswaDiperbarui = len(diperbarui)
+
+	return resp, nil
+}
+
+// bacaHeaderDapodik finds the column title row of a Dapodik export (the row
+// with the NISN column) and maps each column to its definition. Grouped columns
+// are identified by the merged title above and the sub title below. Columns
+// that are not known are ignored. It returns the index of the first data row.
+func bacaHeaderDapodik(rows [][]string) ([]*dapodikKolom, int, error) {
+	kunci := func(judul, sub string) string {
+		return normalisasiDapodik(judul) + "|" + normalisasiDapodik(sub)
+	}
+	kolomByKunci := make(map[string]*dapodikKolom)
+	for i := range dapodikKolomSiswa {
+		k := &dapodikKolomSiswa[i]
+		kolomByKunci[kunci(k.judul, k.sub)] = k
+	}
+
+	for h, row := range rows {
+		adaNISN := false
+		for _, v := range row {
+			if strings.EqualFold(strings.TrimSpace(v), "NISN") {
+				adaNISN = true
+			}
+		}
+		if !adaNISN {
+			continue
+		}
+		if h+1 >= len(rows) {
+			break
+		}
+
+		sub := rows[h+1]
+		kolom := make([]*dapodikKolom, max(len(row), len(sub)))
+		judul := ""
+		for c := range kolom {
+			if c < len(row) && strings.TrimSpace(row[c]) != "" {
+				judul = row[c]
+			}
+			subJudul := ""
+			if c < len(sub) {
+				subJudul = sub[c]
+			}
+			if (c >= len(row) || strings.TrimSpace(row[c]) == "") && strings.TrimSpace(subJudul) == "" {
+				continue
+			}
+			kolom[c] = kolomByKunci[kunci(judul, subJudul)]
+		}
+		return kolom, h + 2, nil
+	}
+
+	return nil, 0, errors.New("not a Dapodik peserta didik export: NISN column not found")
+}
+
+// samaDapodik compares two values ignoring case and extra spaces; numbers are compared by value
+func samaDapodik(a, b string) bool {
+	a, b = normalisasiDapodik(a), normalisasiDapodik(b)
+	if a == b {
+		return true
+	}
+	x, errX := strconv.ParseFloat(strings.ReplaceAll(a, ",", "."), 64)
+	y, errY := strconv.ParseFloat(strings.ReplaceAll(b, ",", "."), 64)
+	return errX == nil && errY == nil && x == y
+}
+
+func normalisasiDapodik(v string) string {
+	return strings.ToLower(strings.Join(strings.Fields(html.UnescapeString(v)), " "))
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// JurusanService handles bidang, program and kompetensi keahlian business logic
+type JurusanService struct {
+	jurusanRepo *repositories.JurusanRepository
+	siswaRepo   *repositories.SiswaRepository
+}
+
+// NewJurusanService creates a new JurusanService
+func NewJurusanService(
+	jurusanRepo *repositories.JurusanRepository,
+	siswaRepo *repositories.SiswaRepository,
+) *JurusanService {
+	return &JurusanService{
+		jurusanRepo: jurusanRepo,
+		siswaRepo:   siswaRepo,
+	}
+}
+
+// CreateBidang creates a new bidang keahlian
+func (s *JurusanService) CreateBidang(req requests.CreateBidangKeahlianRequest) (*responses.BidangKeahlianResponse, error) {
+	kode := utils.SanitizeString(req.Kode)
+
+	exists, err := s.jurusanRepo.ExistsBidangByKode(kode, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("bidang keahlian code already exists")
+	}
+
+	bidang := &models.BidangKeahlian{
+		Kode: kode,
+		Nama: utils.SanitizeString(req.Nama),
+	}
+
+	if err := s.jurusanRepo.CreateBidang(bidang); err != nil {
+		return nil, err
+	}
+
+	return toBidangKeahlianResponse(bidang), nil
+}
+
+// FindAllBidang gets the full bidang > program > kompetensi keahlian tree
+func (s *JurusanService) FindAllBidang() ([]responses.BidangKeahlianResponse, error) {
+	bidangList, err := s.jurusanRepo.FindAllBidang()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.BidangKeahlianResponse
+	for i := range bidangList {
+		result = append(result, *toBidangKeahlianResponse(&bidangList[i]))
+	}
+	return result, nil
+}
+
+// UpdateBidang updates a bidang keahlian
+func (s *JurusanService) UpdateBidang(id uint, req requests.UpdateBidangKeahlianRequest) (*responses.BidangKeahlianResponse, error) {
+	bidang, err := s.findBidang(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Kode != "" {
+		kode := utils.SanitizeString(req.Kode)
+		exists, err := s.jurusanRepo.ExistsBidangByKode(kode, bidang.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.New("bidang keahlian code already exists")
+		}
+		bidang.Kode = kode
+	}
+	if req.Nama != "" {
+		bidang.Nama = utils.SanitizeString(req.Nama)
+	}
+
+	if err := s.jurusanRepo.UpdateBidang(bidang); err != nil {
+		return nil, err
+	}
+
+	return toBidangKeahlianResponse(bidang), nil
+}
+
+// DeleteBidang deletes a bidang keahlian without programs
+func (s *JurusanService) DeleteBidang(id uint) error {
+	if _, err := s.findBidang(id); err != nil {
+		return err
+	}
+
+	count, err := s.jurusanRepo.CountProgramByBidang(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("bidang keahlian still has program keahlian")
+	}
+
+	return s.jurusanRepo.DeleteBidang(id)
+}
+
+// CreateProgram creates a new program keahlian
+func (s *JurusanService) CreateProgram(req requests.CreateProgramKeahlianRequest) (*responses.ProgramKeahlianResponse, error) {
+	if _, err := s.findBidang(req.BidangKeahlianID); err != nil {
+		return nil, err
+	}
+
+	kode := utils.SanitizeString(req.Kode)
+	exists, err := s.jurusanRepo.ExistsProgramByKode(kode, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("program keahlian code already exists")
+	}
+
+	program := &models.ProgramKeahlian{
+		BidangKeahlianID: req.BidangKeahlianID,
+		Kode:             kode,
+		Nama:             utils.SanitizeString(req.Nama),
+	}
+
+	if err := s.jurusanRepo.CreateProgram(program); err != nil {
+		return nil, err
+	}
+
+	return toProgramKeahlianResponse(program), nil
+}
+
+// FindAllProgram gets program keahlian, optionally within one bidang keahlian
+func (s *JurusanService) FindAllProgram(bidangID uint) ([]responses.ProgramKeahlianResponse, error) {
+	programList, err := s.jurusanRepo.FindAllProgram(bidangID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.ProgramKeahlianResponse
+	for i := range programList {
+		result = append(result, *toProgramKeahlianResponse(&programList[i]))
+	}
+	return result, nil
+}
+
+// UpdateProgram updates a program keahlian
+func (s *JurusanService) UpdateProgram(id uint, req requests.UpdateProgramKeahlianRequest) (*responses.ProgramKeahlianResponse, error) {
+	program, err := s.findProgram(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BidangKeahlianID > 0 {
+		if _, err := s.findBidang(req.BidangKeahlianID); err != nil {
+			return nil, err
+		}
+		program.BidangKeahlianID = req.BidangKeahlianID
+	}
+	if req.Kode != "" {
+		kode := utils.SanitizeString(req.Kode)
+		exists, err := s.jurusanRepo.ExistsProgramByKode(kode, program.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.New("program keahlian code already exists")
+		}
+		program.Kode = kode
+	}
+	if req.Nama != "" {
+		program.Nama = utils.SanitizeString(req.Nama)
+	}
+
+	if err := s.jurusanRepo.UpdateProgram(program); err != nil {
+		return nil, err
+	}
+
+	return toProgramKeahlianResponse(program), nil
+}
+
+// DeleteProgram deletes a program keahlian without kompetensi keahlian
+func (s *JurusanService) DeleteProgram(id uint) error {
+	if _, err := s.findProgram(id); err != nil {
+		return err
+	}
+
+	count, err := s.jurusanRepo.CountKompetensiByProgram(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("program keahlian still has kompetensi keahlian")
+	}
+
+	return s.jurusanRepo.DeleteProgram(id)
+}
+
+// CreateKompetensi creates a new kompetensi keahlian
+func (s *JurusanService) CreateKompetensi(req requests.CreateKompetensiKeahlianRequest) (*responses.KompetensiKeahlianResponse, error) {
+	if _, err := s.findProgram(req.ProgramKeahlianID); err != nil {
+		return nil, err
+	}
+
+	kode := utils.SanitizeString(req.Kode)
+	exists, err := s.jurusanRepo.ExistsKompetensiByKode(kode, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("kompetensi keahlian code already exists")
+	}
+
+	kompetensi := &models.KompetensiKeahlian{
+		ProgramKeahlianID: req.ProgramKeahlianID,
+		Kode:              kode,
+		Nama:              utils.SanitizeString(req.Nama),
+		Singkatan:         utils.SanitizeString(req.Singkatan),
+	}
+
+	if err := s.jurusanRepo.CreateKompetensi(kompetensi); err != nil {
+		return nil, err
+	}
+
+	return s.FindKompetensiByID(kompetensi.ID)
+}
+
+// FindAllKompetensi gets kompetensi keahlian
+func (s *JurusanService) FindAllKompetensi(filter requests.KompetensiKeahlianFilterRequest) ([]responses.KompetensiKeahlianResponse, error) {
+	filterMap := make(map[string]interface{})
+	if filter.ProgramKeahlianID > 0 {
+		filterMap["program_keahlian_id"] = filter.ProgramKeahlianID
+	}
+	if filter.BidangKeahlianID > 0 {
+		filterMap["bidang_keahlian_id"] = filter.BidangKeahlianID
+	}
+
+	kompetensiList, err := s.jurusanRepo.FindAllKompetensi(filterMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.KompetensiKeahlianResponse
+	for i := range kompetensiList {
+		result = append(result, *toKompetensiKeahlianResponse(&kompetensiList[i]))
+	}
+	return result, nil
+}
+
+// FindKompetensiByID finds a kompetensi keahlian by ID
+func (s *JurusanService) FindKompetensiByID(id uint) (*responses.KompetensiKeahlianResponse, error) {
+	kompetensi, err := s.findKompetensi(id)
+	if err != nil {
+		return nil, err
+	}
+	return toKompetensiKeahlianResponse(kompetensi), nil
+}
+
+// UpdateKompetensi updates a kompetensi keahlian
+func (s *JurusanService) UpdateKompetensi(id uint, req requests.UpdateKompetensiKeahlianRequest) (*responses.KompetensiKeahlianResponse, error) {
+	kompetensi, err := s.findKompetensi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ProgramKeahlianID > 0 {
+		if _, err := s.findProgram(req.ProgramKeahlianID); err != nil {
+			return nil, err
+		}
+		kompetensi.ProgramKeahlianID = req.ProgramKeahlianID
+	}
+	if req.Kode != "" {
+		kode := utils.SanitizeString(req.Kode)
+		exists, err := s.jurusanRepo.ExistsKompetensiByKode(kode, kompetensi.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.New("kompetensi keahlian code already exists")
+		}
+		kompetensi.Kode = kode
+	}
+	if req.Nama != "" {
+		kompetensi.Nama = utils.SanitizeString(req.Nama)
+	}
+	if req.Singkatan != "" {
+		kompetensi.Singkatan = utils.SanitizeString(req.Singkatan)
+	}
+
+	if err := s.jurusanRepo.UpdateKompetensi(kompetensi); err != nil {
+		return nil, err
+	}
+
+	return s.FindKompetensiByID(kompetensi.ID)
+}
+
+// DeleteKompetensi deletes a kompetensi keahlian that no student, history or curriculum refers to
+func (s *JurusanService) DeleteKompetensi(id uint) error {
+	if _, err := s.findKompetensi(id); err != nil {
+		return err
+	}
+
+	inUse, err := s.jurusanRepo.IsKompetensiInUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("kompetensi keahlian is still in use")
+	}
+
+	return s.jurusanRepo.DeleteKompetensi(id)
+}
+
+// SetJurusanSiswa assigns a student to a kompetensi keahlian. A student who is
+// already assigned is moved, and the previous assignment is kept as history.
+func (s *JurusanService) SetJurusanSiswa(siswaID uint, req requests.SetJurusanSiswaRequest) ([]responses.RiwayatJurusanResponse, error) {
+	siswa, err := s.siswaRepo.FindByID(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	if _, err := s.findKompetensi(req.KompetensiKeahlianID); err != nil {
+		return nil, err
+	}
+	if siswa.KompetensiKeahlianID != nil && *siswa.KompetensiKeahlianID == req.KompetensiKeahlianID {
+		return nil, errors.New("student is already in this kompetensi keahlian")
+	}
+
+	tanggalMulai, err := time.Parse("2006-01-02", req.TanggalMulai)
+	if err != nil {
+		return nil, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+
+	aktif, err := s.jurusanRepo.FindRiwayatAktif(siswaID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if aktif != nil && tanggalMulai.Before(aktif.TanggalMulai) {
+		return nil, errors.New("tanggal_mulai must not be before the start of the current kompetensi keahlian")
+	}
+
+	riwayat := &models.RiwayatJurusan{
+		SiswaID:              siswaID,
+		KompetensiKeahlianID: req.KompetensiKeahlianID,
+		TanggalMulai:         tanggalMulai,
+		Keterangan:           utils.SanitizeString(req.Keterangan),
+	}
+	if err := s.jurusanRepo.AssignSiswa(riwayat); err != nil {
+		return nil, err
+	}
+
+	return s.GetRiwayatJurusan(siswaID)
+}
+
+// GetRiwayatJurusan gets the kompetensi keahlian history of a student
+func (s *JurusanService) GetRiwayatJurusan(siswaID uint) ([]responses.RiwayatJurusanResponse, error) {
+	if _, err := s.siswaRepo.FindByID(siswaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	riwayatList, err := s.jurusanRepo.FindRiwayatBySiswaID(siswaID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.RiwayatJurusanResponse
+	for _, r := range riwayatList {
+		resp := responses.RiwayatJurusanResponse{
+			ID:             r.ID,
+			TanggalMulai:   r.TanggalMulai,
+			TanggalSelesai: r.TanggalSelesai,
+			Keterangan:     r.Keterangan,
+		}
+		if r.KompetensiKeahlian != nil {
+			resp.KompetensiKeahlian = toKompetensiKeahlianResponse(r.KompetensiKeahlian)
+		}
+		result = append(result, resp)
+	}
+	return result, nil
+}
+
+func (s *JurusanService) findBidang(id uint) (*models.BidangKeahlian, error) {
+	bidang, err := s.jurusanRepo.FindBidangByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bidang keahlian not found")
+		}
+		return nil, err
+	}
+	return bidang, nil
+}
+
+func (s *JurusanService) findProgram(id uint) (*models.ProgramKeahlian, error) {
+	program, err := s.jurusanRepo.FindProgramByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("program keahlian not found")
+		}
+		return nil, err
+	}
+	return program, nil
+}
+
+func (s *JurusanService) findKompetensi(id uint) (*models.KompetensiKeahlian, error) {
+	kompetensi, err := s.jurusanRepo.FindKompetensiByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("kompetensi keahlian not found")
+		}
+		return nil, err
+	}
+	return kompetensi, nil
+}
+
+// toBidangKeahlianResponse converts a bidang keahlian and its loaded programs to DTO
+func toBidangKeahlianResponse(b *models.BidangKeahlian) *responses.BidangKeahlianResponse {
+	resp := &responses.BidangKeahlianResponse{
+		ID:   b.ID,
+		Kode: b.Kode,
+		Nama: b.Nama,
+	}
+	for i := range b.ProgramKeahlian {
+		resp.ProgramKeahlian = append(resp.ProgramKeahlian, *toProgramKeahlianResponse(&b.ProgramKeahlian[i]))
+	}
+	return resp
+}
+
+// toProgramKeahlianResponse converts a program keahlian and its loaded kompetensi keahlian to DTO
+func toProgramKeahlianResponse(p *models.ProgramKeahlian) *responses.ProgramKeahlianResponse {
+	resp := &responses.ProgramKeahlianResponse{
+		ID:               p.ID,
+		BidangKeahlianID: p.BidangKeahlianID,
+		Kode:             p.Kode,
+		Nama:             p.Nama,
+	}
+	for i := range p.KompetensiKeahlian {
+		resp.KompetensiKeahlian = append(resp.KompetensiKeahlian, *toKompetensiKeahlianResponse(&p.KompetensiKeahlian[i]))
+	}
+	return resp
+}
+
+// toKompetensiKeahlianResponse converts a kompetensi keahlian to DTO, including
+// its program and bidang names when they are loaded
+func toKompetensiKeahlianResponse(k *models.KompetensiKeahlian) *responses.KompetensiKeahlianResponse {
+	resp := &responses.KompetensiKeahlianResponse{
+		ID:                k.ID,
+		Kode:              k.Kode,
+		Nama:              k.Nama,
+		Singkatan:         k.Singkatan,
+		ProgramKeahlianID: k.ProgramKeahlianID,
+	}
+	if k.ProgramKeahlian != nil {
+		resp.ProgramKeahlian = k.ProgramKeahlian.Nama
+		resp.BidangKeahlianID = k.ProgramKeahlian.BidangKeahlianID
+		if k.ProgramKeahlian.BidangKeahlian != nil {
+			resp.BidangKeahlian = k.ProgramKeahlian.BidangKeahlian.Nama
+		}
+	}
+	return resp
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// KehadiranService handles attendance business logic
+type KehadiranService struct {
+	siswaRepo     *repositories.SiswaRepository
+	kehadiranRepo *repositories.KehadiranRepository
+	harianRepo    *repositories.KehadiranHarianRepository
+	rombelRepo    *repositories.RombelRepository
+	tahunRepo     *repositories.TahunPelajaranRepository
+}
+
+// NewKehadiranService creates a new KehadiranService
+func NewKehadiranService(
+	siswaRepo *repositories.SiswaRepository,
+	kehadiranRepo *repositories.KehadiranRepository,
+	harianRepo *repositories.KehadiranHarianRepository,
+	rombelRepo *repositories.RombelRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+) *KehadiranService {
+	return &KehadiranService{
+		siswaRepo:     siswaRepo,
+		kehadiranRepo: kehadiranRepo,
+		harianRepo:    harianRepo,
+		rombelRepo:    rombelRepo,
+		tahunRepo:     tahunRepo,
+	}
+}
+
+// Upsert creates or replaces a student's semester attendance summary
+func (s *KehadiranService) Upsert(siswaID uint, req requests.CreateKehadiranRequest) (*responses.KehadiranResponse, error) {
+	if err := s.checkSiswa(siswaID); err != nil {
+		return nil, err
+	}
+
+	// Summaries backed by the daily ledger are recomputed on every ledger change
+	count, err := s.harianRepo.CountByPeriode(siswaID, req.Kelas, req.Semester)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("attendance for this semester is computed from the daily attendance ledger")
+	}
+
+	if req.JumlahHariEfektif > 0 && req.JumlahHadir+req.JumlahSakit+req.JumlahIzin+req.JumlahAlpa > req.JumlahHariEfektif {
+		return nil, errors.New("attendance totals exceed the number of effective days")
+	}
+
+	persentase := req.PersentaseHadir
+	if persentase == 0 && req.JumlahHariEfektif > 0 {
+		persentase = math.Round(float64(req.JumlahHadir)*10000/float64(req.JumlahHariEfektif)) / 100
+	}
+
+	kehadiran := &models.Kehadiran{
+		SiswaID:           siswaID,
+		Kelas:             req.Kelas,
+		Semester:          req.Semester,
+		JumlahHadir:       req.JumlahHadir,
+		PersentaseHadir:   persentase,
+		JumlahSakit:       req.JumlahSakit,
+		JumlahIzin:        req.JumlahIzin,
+		JumlahAlpa:        req.JumlahAlpa,
+		JumlahHariEfektif: req.JumlahHariEfektif,
+	}
+
+	if err := s.kehadiranRepo.Upsert(kehadiran); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.kehadiranRepo.FindBySiswaIDAndKelas(siswaID, req.Kelas, req.Semester)
+	if err != nil {
+		return nil, err
+	}
+	return toKehadiranResponse(saved), nil
+}
+
+// RecordHarian records or corrects a student's attendance on one day
+func (s *KehadiranService) RecordHarian(siswaID uint, req requests.CreateKehadiranHarianRequest) (*responses.KehadiranHarianResponse, error) {
+	if err := s.checkSiswa(siswaID); err != nil {
+		return nil, err
+	}
+
+	tanggal, err := parseTanggalKehadiran(req.Tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	kelas, semester, err := s.resolvePeriode(siswaID, tanggal, req.Kelas, req.Semester)
+	if err != nil {
+		return nil, err
+	}
+
+	harian := models.KehadiranHarian{
+		SiswaID:    siswaID,
+		Tanggal:    tanggal,
+		Kelas:      kelas,
+		Semester:   semester,
+		Status:     req.Status,
+		Keterangan: utils.SanitizeString(req.Keterangan),
+	}
+
+	if err := s.harianRepo.Upsert([]models.KehadiranHarian{harian}); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.harianRepo.FindBySiswaIDAndTanggal([]uint{siswaID}, tanggal)
+	if err != nil {
+		return nil, err
+	}
+	if len(saved) == 0 {
+		return nil, errors.New("daily attendance not found")
+	}
+	return toKehadiranHarianResponse(&saved[0]), nil
+}
+
+// RecordHarianRombel records the attendance of a class group on one day
+func (s *KehadiranService) RecordHarianRombel(rombelID uint, req requests.BulkKehadiranHarianRequest) ([]responses.KehadiranHarianResponse, error) {
+	rombel, err := s.rombelRepo.FindByID(rombelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class group not found")
+		}
+		return nil, err
+	}
+
+	tanggal, err := parseTanggalKehadiran(req.Tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	tahun, err := s.tahunRepo.FindByLabel(rombel.TahunPelajaran)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("academic year %s is not registered", rombel.TahunPelajaran)
+		}
+		return nil, err
+	}
+	if !dalamTahunPelajaran(tahun, tanggal) {
+		return nil, fmt.Errorf("date is outside academic year %s", tahun.Label)
+	}
+
+	semester := req.Semester
+	if semester == 0 {
+		if !tahun.IsActive {
+			return nil, errors.New("semester is required for class groups outside the active academic year")
+		}
+		semester = tahun.SemesterAktif
+	}
+
+	memberIDs, err := s.rombelRepo.FindSiswaIDsByRombelID(rombel.ID)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[uint]bool)
+	for _, id := range memberIDs {
+		members[id] = true
+	}
+
+	seen := make(map[uint]bool)
+	var siswaIDs []uint
+	var entries []models.KehadiranHarian
+	for _, item := range req.Kehadiran {
+		if !members[item.SiswaID] {
+			return nil, fmt.Errorf("student %d is not a member of this class group", item.SiswaID)
+		}
+		if seen[item.SiswaID] {
+			return nil, fmt.Errorf("student %d is listed more than once", item.SiswaID)
+		}
+		seen[item.SiswaID] = true
+		siswaIDs = append(siswaIDs, item.SiswaID)
+
+		entries = append(entries, models.KehadiranHarian{
+			SiswaID:    item.SiswaID,
+			Tanggal:    tanggal,
+			Kelas:      rombel.Tingkat,
+			Semester:   semester,
+			Status:     item.Status,
+			Keterangan: utils.SanitizeString(item.Keterangan),
+		})
+	}
+
+	if err := s.harianRepo.Upsert(entries); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.harianRepo.FindBySiswaIDAndTanggal(siswaIDs, tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.KehadiranHarianResponse
+	for i := range saved {
+		result = append(result, *toKehadiranHarianResponse(&saved[i]))
+	}
+	return result, nil
+}
+
+// GetHarian lists a student's daily attendance with pagination
+func (s *KehadiranService) GetHarian(siswaID uint, req requests.PaginationRequest, filter requests.KehadiranHarianFilterRequest) ([]responses.KehadiranHarianResponse, utils.Pagination, error) {
+	if err := s.checkSiswa(siswaID); err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filterMap := make(map[string]interface{})
+	if filter.Kelas != "" {
+		filterMap["kelas"] = filter.Kelas
+	}
+	if filter.Semester > 0 {
+		filterMap["semester"] = filter.Semester
+	}
+	if filter.Status != "" {
+		filterMap["status"] = filter.Status
+	}
+	if filter.TanggalMulai != "" {
+		tanggal, err := time.Parse("2006-01-02", filter.TanggalMulai)
+		if err != nil {
+			return nil, utils.Pagination{}, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		filterMap["tanggal_mulai"] = tanggal
+	}
+	if filter.TanggalSelesai != "" {
+		tanggal, err := time.Parse("2006-01-02", filter.TanggalSelesai)
+		if err != nil {
+			return nil, utils.Pagination{}, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		filterMap["tanggal_selesai"] = tanggal
+	}
+
+	harianList, total, err := s.harianRepo.FindBySiswaIDPaginated(siswaID, filterMap, req.Page, req.PageSize)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var result []responses.KehadiranHarianResponse
+	for i := range harianList {
+		result = append(result, *toKehadiranHarianResponse(&harianList[i]))
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+
+	return result, pagination, nil
+}
+
+// DeleteHarian deletes a daily attendance entry
+func (s *KehadiranService) DeleteHarian(id uint) error {
+	harian, err := s.harianRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("daily attendance not found")
+		}
+		return err
+	}
+
+	return s.harianRepo.Delete(harian)
+}
+
+// checkSiswa checks that a student exists
+func (s *KehadiranService) checkSiswa(siswaID uint) error {
+	if _, err := s.siswaRepo.FindByID(siswaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("student not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// resolvePeriode determines the kelas/semester a daily entry belongs to.
+// Missing values are taken from the active academic year and the student's
+// class group in it.
+func (s *KehadiranService) resolvePeriode(siswaID uint, tanggal time.Time, kelas string, semester uint8) (string, uint8, error) {
+	if kelas != "" && semester > 0 {
+		return kelas, semester, nil
+	}
+
+	aktif, err := s.tahunRepo.FindActive()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, errors.New("no active academic year, kelas and semester are required")
+		}
+		return "", 0, err
+	}
+	if !dalamTahunPelajaran(aktif, tanggal) {
+		return "", 0, errors.New("date is outside the active academic year, kelas and semester are required")
+	}
+
+	if semester == 0 {
+		semester = aktif.SemesterAktif
+	}
+	if kelas == "" {
+		anggota, err := s.rombelRepo.FindAnggotaBySiswaAndTahun(siswaID, aktif.Label)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", 0, fmt.Errorf("student is not enrolled in a class group for %s, kelas is required", aktif.Label)
+			}
+			return "", 0, err
+		}
+		kelas = anggota.Rombel.Tingkat
+	}
+	return kelas, semester, nil
+}
+
+// parseTanggalKehadiran parses an attendance date, which cannot be in the future
+func parseTanggalKehadiran(value string) (time.Time, error) {
+	tanggal, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+	if tanggal.Format("2006-01-02") > time.Now().Format("2006-01-02") {
+		return time.Time{}, errors.New("attendance date cannot be in the future")
+	}
+	return tanggal, nil
+}
+
+// dalamTahunPelajaran reports whether a date falls within an academic year
+func dalamTahunPelajaran(tahun *models.TahunPelajaran, tanggal time.Time) bool {
+	hari := tanggal.Format("2006-01-02")
+	return hari >= tahun.TanggalMulai.Format("2006-01-02") && hari <= tahun.TanggalSelesai.Format("2006-01-02")
+}
+
+// toKehadiranResponse converts a semester attendance summary to DTO
+func toKehadiranResponse(k *models.Kehadiran) *responses.KehadiranResponse {
+	return &responses.KehadiranResponse{
+		ID:                k.ID,
+		Kelas:             k.Kelas,
+		Semester:          k.Semester,
+		JumlahHadir:       k.JumlahHadir,
+		PersentaseHadir:   k.PersentaseHadir,
+		JumlahSakit:       k.JumlahSakit,
+		JumlahIzin:        k.JumlahIzin,
+		JumlahAlpa:        k.JumlahAlpa,
+		JumlahHariEfektif: k.JumlahHariEfektif,
+	}
+}
+
+// toKehadiranHarianResponse converts a daily attendance entry to DTO
+func toKehadiranHarianResponse(h *models.KehadiranHarian) *responses.KehadiranHarianResponse {
+	return &responses.KehadiranHarianResponse{
+		ID:         h.ID,
+		SiswaID:    h.SiswaID,
+		Tanggal:    h.Tanggal,
+		Kelas:      h.Kelas,
+		Semester:   h.Semester,
+		Status:     h.Status,
+		Keterangan: h.Keterangan,
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// tingkatBerikutnya maps a grade to the grade a promoted student moves to.
+// Grade XII is not listed because finishing it is handled as graduation.
+var tingkatBerikutnya = map[string]string{
+	"X":  "XI",
+	"XI": "XII",
+}
+
+// KenaikanKelasService handles grade promotion business logic
+type KenaikanKelasService struct {
+	kenaikanRepo  *repositories.KenaikanKelasRepository
+	rombelRepo    *repositories.RombelRepository
+	nilaiRepo     *repositories.NilaiSemesterRepository
+	kehadiranRepo *repositories.KehadiranRepository
+	tahunRepo     *repositories.TahunPelajaranRepository
+}
+
+// NewKenaikanKelasService creates a new KenaikanKelasService
+func NewKenaikanKelasService(
+	kenaikanRepo *repositories.KenaikanKelasRepository,
+	rombelRepo *repositories.RombelRepository,
+	nilaiRepo *repositories.NilaiSemesterRepository,
+	kehadiranRepo *repositories.KehadiranRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+) *KenaikanKelasService {
+	return &KenaikanKelasService{
+		kenaikanRepo:  kenaikanRepo,
+		rombelRepo:    rombelRepo,
+		nilaiRepo:     nilaiRepo,
+		kehadiranRepo: kehadiranRepo,
+		tahunRepo:     tahunRepo,
+	}
+}
+
+// Preview evaluates promotion decisions without saving them
+func (s *KenaikanKelasService) Preview(req requests.KenaikanKelasRequest) (*responses.KenaikanKelasSummaryResponse, error) {
+	req.TahunPelajaran = utils.SanitizeString(req.TahunPelajaran)
+	hasil, err := s.evaluate(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.toSummary(req.TahunPelajaran, hasil), nil
+}
+
+// Proses evaluates promotion decisions, applies overrides and saves them in one transaction
+func (s *KenaikanKelasService) Proses(req requests.ProsesKenaikanKelasRequest) (*responses.KenaikanKelasSummaryResponse, error) {
+	req.TahunPelajaran = utils.SanitizeString(req.TahunPelajaran)
+	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
+	if err != nil {
+		return nil, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+
+	hasil, err := s.evaluate(req.KenaikanKelasRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[uint]int, len(hasil))
+	for i, h := range hasil {
+		index[h.SiswaID] = i
+	}
+
+	// Apply manual decisions on top of the evaluated ones
+	for _, o := range req.Override {
+		i, ok := index[o.SiswaID]
+		if !ok {
+			return nil, fmt.Errorf("student %d is not a promotion candidate for %s", o.SiswaID, req.TahunPelajaran)
+		}
+		hasil[i].Keputusan = o.Keputusan
+		hasil[i].TingkatTujuan = tingkatTujuan(hasil[i].TingkatAsal, o.Keputusan)
+		hasil[i].Override = true
+		hasil[i].Catatan = utils.SanitizeString(o.Catatan)
+	}
+
+	var kenaikan []models.KenaikanKelas
+	for _, h := range hasil {
+		catatan := h.Catatan
+		if catatan == "" && len(h.Alasan) > 0 {
+			catatan = strings.Join(h.Alasan, "; ")
+		}
+		rombelID := h.RombelID
+		kenaikan = append(kenaikan, models.KenaikanKelas{
+			SiswaID:        h.SiswaID,
+			TahunPelajaran: req.TahunPelajaran,
+			RombelID:       &rombelID,
+			TingkatAsal:    h.TingkatAsal,
+			TingkatTujuan:  h.TingkatTujuan,
+			Keputusan:      h.Keputusan,
+			Catatan:        catatan,
+			Tanggal:        tanggal,
+		})
+	}
+
+	if err := s.kenaikanRepo.CreateBatch(kenaikan); err != nil {
+		return nil, err
+	}
+
+	return s.toSummary(req.TahunPelajaran, hasil), nil
+}
+
+// GetBySiswaID gets the promotion history of a student
+func (s *KenaikanKelasService) GetBySiswaID(siswaID uint) ([]responses.KenaikanKelasResponse, error) {
+	kenaikan, err := s.kenaikanRepo.FindBySiswaID(siswaID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.KenaikanKelasResponse
+	for _, k := range kenaikan {
+		result = append(result, toKenaikanKelasResponse(k))
+	}
+	return result, nil
+}
+
+// evaluate builds the promotion decision of every unprocessed student
+// enrolled in grade X or XI during the academic year
+func (s *KenaikanKelasService) evaluate(req requests.KenaikanKelasRequest) ([]responses.KenaikanKelasPreviewResponse, error) {
+	tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RombelID > 0 {
+		rombel, err := s.rombelRepo.FindByID(req.RombelID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("class group not found")
+			}
+			return nil, err
+		}
+		if rombel.TahunPelajaran != tahunPelajaran {
+			return nil, errors.New("class group does not belong to the academic year")
+		}
+	}
+
+	anggota, err := s.rombelRepo.FindAnggotaByTahun(tahunPelajaran, req.RombelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var kandidat []models.AnggotaRombel
+	var siswaIDs []uint
+	for _, a := range anggota {
+		if a.Siswa == nil || a.Rombel == nil {
+			continue
+		}
+		if _, ok := tingkatBerikutnya[a.Rombel.Tingkat]; !ok {
+			continue
+		}
+		kandidat = append(kandidat, a)
+		siswaIDs = append(siswaIDs, a.SiswaID)
+	}
+
+	// Students that already have a decision are left out so the process can be re-run
+	processed, err := s.kenaikanRepo.FindProcessedSiswaIDs(siswaIDs, tahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+	sudahDiproses := make(map[uint]bool, len(processed))
+	for _, id := range processed {
+		sudahDiproses[id] = true
+	}
+
+	kehadiran := make(map[uint][]models.Kehadiran)
+	if req.MinPersentaseHadir > 0 {
+		list, err := s.kehadiranRepo.FindBySiswaIDs(siswaIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range list {
+			kehadiran[k.SiswaID] = append(kehadiran[k.SiswaID], k)
+		}
+	}
+
+	tidakTuntas := make(map[uint]int64)
+	if req.NilaiMinimum > 0 {
+		tidakTuntas, err = s.nilaiRepo.CountTidakTuntasBySiswaIDs(siswaIDs, tahunPelajaran, req.NilaiMinimum)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var hasil []responses.KenaikanKelasPreviewResponse
+	for _, a := range kandidat {
+		if sudahDiproses[a.SiswaID] {
+			continue
+		}
+
+		h := responses.KenaikanKelasPreviewResponse{
+			SiswaID:           a.SiswaID,
+			NoInduk:           a.Siswa.NoInduk,
+			NamaLengkap:       a.Siswa.NamaLengkap,
+			RombelID:          a.RombelID,
+			Rombel:            a.Rombel.Nama,
+			TingkatAsal:       a.Rombel.Tingkat,
+			JumlahTidakTuntas: tidakTuntas[a.SiswaID],
+		}
+
+		if req.MinPersentaseHadir > 0 {
+			if persentase, ok := persentaseHadir(kehadiran[a.SiswaID], a.Rombel.Tingkat); ok {
+				h.PersentaseHadir = &persentase
+				if persentase < req.MinPersentaseHadir {
+					h.Alasan = append(h.Alasan, fmt.Sprintf("attendance %.2f%% is below the minimum of %.2f%%", persentase, req.MinPersentaseHadir))
+				}
+			}
+		}
+		if h.JumlahTidakTuntas > 0 {
+			h.Alasan = append(h.Alasan, fmt.Sprintf("%d grades below the minimum score of %d", h.JumlahTidakTuntas, req.NilaiMinimum))
+		}
+
+		h.Keputusan = "Naik"
+		if len(h.Alasan) > 0 {
+			h.Keputusan = "Tinggal"
+		}
+		h.TingkatTujuan = tingkatTujuan(h.TingkatAsal, h.Keputusan)
+
+		hasil = append(hasil, h)
+	}
+
+	if len(hasil) == 0 {
+		return nil, errors.New("no students left to evaluate for this academic year")
+	}
+
+	return hasil, nil
+}
+
+// toSummary wraps evaluated decisions with their totals
+func (s *KenaikanKelasService) toSummary(tahunPelajaran string, hasil []responses.KenaikanKelasPreviewResponse) *responses.KenaikanKelasSummaryResponse {
+	summary := &responses.KenaikanKelasSummaryResponse{
+		TahunPelajaran: tahunPelajaran,
+		JumlahSiswa:    len(hasil),
+		Siswa:          hasil,
+	}
+	for _, h := range hasil {
+		if h.Keputusan == "Naik" {
+			summary.JumlahNaik++
+		} else {
+			summary.JumlahTinggal++
+		}
+	}
+	return summary
+}
+
+// tingkatTujuan returns the grade a student ends up in for a decision
+func tingkatTujuan(tingkatAsal, keputusan string) string {
+	if keputusan == "Naik" {
+		return tingkatBerikutnya[tingkatAsal]
+	}
+	return tingkatAsal
+}
+
+// persentaseHadir computes the attendance percentage of a grade across its semesters.
+// It reports false when there is no attendance data for the grade.
+func persentaseHadir(kehadiran []models.Kehadiran, kelas string) (float64, bool) {
+	var hadir, hariEfektif uint
+	var totalPersentase float64
+	var jumlah int
+	for _, k := range kehadiran {
+		if k.Kelas != kelas {
+			continue
+		}
+		hadir += k.JumlahHadir
+		hariEfektif += k.JumlahHariEfektif
+		totalPersentase += k.PersentaseHadir
+		jumlah++
+	}
+
+	if jumlah == 0 {
+		return 0, false
+	}
+	if hariEfektif > 0 {
+		return float64(hadir) / float64(hariEfektif) * 100, true
+	}
+	return totalPersentase / float64(jumlah), true
+}
+
+// toKenaikanKelasResponse converts to DTO
+func toKenaikanKelasResponse(k models.KenaikanKelas) responses.KenaikanKelasResponse {
+	return responses.KenaikanKelasResponse{
+		ID:             k.ID,
+		TahunPelajaran: k.TahunPelajaran,
+		TingkatAsal:    k.TingkatAsal,
+		TingkatTujuan:  k.TingkatTujuan,
+		Keputusan:      k.Keputusan,
+		Catatan:        k.Catatan,
+		Tanggal:        k.Tanggal,
+	}
+}
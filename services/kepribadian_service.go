@@ -15,11 +15,12 @@ import (
 type KepribadianService struct {
 	siswaRepo       *repositories.SiswaRepository
 	kepribadianRepo *repositories.KepribadianRepository
+	tahunRepo       *repositories.TahunPelajaranRepository
 }
 
 // NewKepribadianService creates a new KepribadianService
-func NewKepribadianService(siswaRepo *repositories.SiswaRepository, kepribadianRepo *repositories.KepribadianRepository) *KepribadianService {
-	return &KepribadianService{siswaRepo: siswaRepo, kepribadianRepo: kepribadianRepo}
+func NewKepribadianService(siswaRepo *repositories.SiswaRepository, kepribadianRepo *repositories.KepribadianRepository, tahunRepo *repositories.TahunPelajaranRepository) *KepribadianService {
+	return &KepribadianService{siswaRepo: siswaRepo, kepribadianRepo: kepribadianRepo, tahunRepo: tahunRepo}
 }
 
 // Add adds personality record to a student
@@ -33,11 +34,16 @@ func (s *KepribadianService) Add(siswaID uint, req requests.CreateKepribadianReq
 		return nil, err
 	}
 
+	tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
 	kepribadian := &models.Kepribadian{
 		SiswaID:        siswaID,
 		Aspek:          utils.SanitizeString(req.Aspek),
 		Nilai:          req.Nilai,
-		TahunPelajaran: utils.SanitizeString(req.TahunPelajaran),
+		TahunPelajaran: tahunPelajaran,
 	}
 
 	if err := s.kepribadianRepo.Create(kepribadian); err != nil {
@@ -47,11 +53,97 @@ func (s *KepribadianService) Add(siswaID uint, req requests.CreateKepribadianReq
 	return s.toResponse(kepribadian), nil
 }
 
+// FindAll gets personality records with pagination, optionally limited to one student
+func (s *KepribadianService) FindAll(siswaID uint, req requests.PaginationRequest, filter requests.KepribadianFilterRequest) ([]responses.KepribadianResponse, utils.Pagination, error) {
+	if siswaID > 0 {
+		if _, err := s.siswaRepo.FindByID(siswaID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, utils.Pagination{}, errors.New("student not found")
+			}
+			return nil, utils.Pagination{}, err
+		}
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filterMap := map[string]interface{}{
+		"siswa_id":        siswaID,
+		"tahun_pelajaran": utils.SanitizeString(filter.TahunPelajaran),
+		"aspek":           utils.SanitizeString(filter.Aspek),
+		"nilai":           filter.Nilai,
+	}
+
+	kepribadianList, total, err := s.kepribadianRepo.FindAll(req.Page, req.PageSize, filterMap)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var response []responses.KepribadianResponse
+	for i := range kepribadianList {
+		response = append(response, *s.toResponse(&kepribadianList[i]))
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+
+	return response, pagination, nil
+}
+
+// Update updates personality record
+func (s *KepribadianService) Update(id uint, req requests.UpdateKepribadianRequest) (*responses.KepribadianResponse, error) {
+	kepribadian, err := s.kepribadianRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("personality record not found")
+		}
+		return nil, err
+	}
+
+	if req.Aspek != "" {
+		kepribadian.Aspek = utils.SanitizeString(req.Aspek)
+	}
+	if req.Nilai != "" {
+		kepribadian.Nilai = req.Nilai
+	}
+	if req.TahunPelajaran != "" {
+		tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+		if err != nil {
+			return nil, err
+		}
+		kepribadian.TahunPelajaran = tahunPelajaran
+	}
+
+	if err := s.kepribadianRepo.Update(kepribadian); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(kepribadian), nil
+}
+
 // Delete deletes personality record
 func (s *KepribadianService) Delete(id uint) error {
-	// Currently repo doesn't have FindByID, so we trust ID exists or handle error from Delete
-	// But standard practice is to check existence.
-	// I should probably add FindByID to KepribadianRepo too.
+	_, err := s.kepribadianRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("personality record not found")
+		}
+		return err
+	}
+
 	return s.kepribadianRepo.Delete(id)
 }
 
@@ -62,5 +154,6 @@ func (s *KepribadianService) toResponse(k *models.Kepribadian) *responses.Keprib
 		Aspek:          k.Aspek,
 		Nilai:          k.Nilai,
 		TahunPelajaran: k.TahunPelajaran,
+		Siswa:          toSiswaRingkasResponse(k.Siswa),
 	}
 }
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"gorm.io/gorm"
+)
+
+// KurikulumService handles curriculum business logic
+type KurikulumService struct {
+	kurikulumRepo *repositories.KurikulumRepository
+	mapelRepo     *repositories.MataPelajaranRepository
+	jurusanRepo   *repositories.JurusanRepository
+}
+
+// NewKurikulumService creates a new KurikulumService
+func NewKurikulumService(
+	kurikulumRepo *repositories.KurikulumRepository,
+	mapelRepo *repositories.MataPelajaranRepository,
+	jurusanRepo *repositories.JurusanRepository,
+) *KurikulumService {
+	return &KurikulumService{
+		kurikulumRepo: kurikulumRepo,
+		mapelRepo:     mapelRepo,
+		jurusanRepo:   jurusanRepo,
+	}
+}
+
+// Create adds a subject to the curriculum of a kompetensi keahlian, tingkat and semester
+func (s *KurikulumService) Create(req requests.CreateKurikulumRequest) (*responses.KurikulumResponse, error) {
+	kompetensi, err := s.jurusanRepo.FindKompetensiByID(req.KompetensiKeahlianID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("kompetensi keahlian not found")
+		}
+		return nil, err
+	}
+
+	mapel, err := s.mapelRepo.FindByID(req.MataPelajaranID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("subject not found")
+		}
+		return nil, err
+	}
+	if !mapel.Aktif {
+		return nil, errors.New("subject is inactive")
+	}
+
+	exists, err := s.kurikulumRepo.Exists(req.KompetensiKeahlianID, req.Tingkat, req.Semester, req.MataPelajaranID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("subject is already in this curriculum")
+	}
+
+	kurikulum := &models.Kurikulum{
+		KompetensiKeahlianID: req.KompetensiKeahlianID,
+		Tingkat:              req.Tingkat,
+		Semester:             req.Semester,
+		MataPelajaranID:      req.MataPelajaranID,
+		Urutan:               req.Urutan,
+		MenitPerMinggu:       req.MenitPerMinggu,
+	}
+
+	if err := s.kurikulumRepo.Create(kurikulum); err != nil {
+		return nil, err
+	}
+
+	kurikulum.KompetensiKeahlian = kompetensi
+	kurikulum.MataPelajaran = mapel
+	return s.toResponse(kurikulum), nil
+}
+
+// FindAll gets curriculum entries in report order
+func (s *KurikulumService) FindAll(filter requests.KurikulumFilterRequest) ([]responses.KurikulumResponse, error) {
+	filterMap := make(map[string]interface{})
+	if filter.KompetensiKeahlianID > 0 {
+		filterMap["kompetensi_keahlian_id"] = filter.KompetensiKeahlianID
+	}
+	if filter.Tingkat != "" {
+		filterMap["tingkat"] = filter.Tingkat
+	}
+	if filter.Semester > 0 {
+		filterMap["semester"] = filter.Semester
+	}
+	if filter.MataPelajaranID > 0 {
+		filterMap["mata_pelajaran_id"] = filter.MataPelajaranID
+	}
+
+	kurikulumList, err := s.kurikulumRepo.FindAll(filterMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.KurikulumResponse
+	for i := range kurikulumList {
+		result = append(result, *s.toResponse(&kurikulumList[i]))
+	}
+	return result, nil
+}
+
+// FindByID finds a curriculum entry by ID
+func (s *KurikulumService) FindByID(id uint) (*responses.KurikulumResponse, error) {
+	kurikulum, err := s.kurikulumRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("curriculum entry not found")
+		}
+		return nil, err
+	}
+	return s.toResponse(kurikulum), nil
+}
+
+// Update updates the ordering and weekly minutes of a curriculum entry
+func (s *KurikulumService) Update(id uint, req requests.UpdateKurikulumRequest) (*responses.KurikulumResponse, error) {
+	kurikulum, err := s.kurikulumRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("curriculum entry not found")
+		}
+		return nil, err
+	}
+
+	if req.Urutan > 0 {
+		kurikulum.Urutan = req.Urutan
+	}
+	if req.MenitPerMinggu > 0 {
+		kurikulum.MenitPerMinggu = req.MenitPerMinggu
+	}
+
+	if err := s.kurikulumRepo.Update(kurikulum); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(kurikulum), nil
+}
+
+// Delete removes a subject from a curriculum
+func (s *KurikulumService) Delete(id uint) error {
+	if _, err := s.kurikulumRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("curriculum entry not found")
+		}
+		return err
+	}
+
+	return s.kurikulumRepo.Delete(id)
+}
+
+// toResponse converts to DTO
+func (s *KurikulumService) toResponse(k *models.Kurikulum) *responses.KurikulumResponse {
+	resp := &responses.KurikulumResponse{
+		ID:             k.ID,
+		Tingkat:        k.Tingkat,
+		Semester:       k.Semester,
+		Urutan:         k.Urutan,
+		MenitPerMinggu: k.MenitPerMinggu,
+	}
+
+	if k.KompetensiKeahlian != nil {
+		resp.KompetensiKeahlian = toKompetensiKeahlianResponse(k.KompetensiKeahlian)
+	}
+	if k.MataPelajaran != nil {
+		resp.MataPelajaran = toMataPelajaranResponse(k.MataPelajaran)
+	}
+
+	return resp
+}
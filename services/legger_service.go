@@ -0,0 +1,222 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// LeggerService builds the grade matrix (legger) of a class group
+type LeggerService struct {
+	rombelRepo       *repositories.RombelRepository
+	nilaiRepo        *repositories.NilaiSemesterRepository
+	peringkatService *PeringkatService
+}
+
+// NewLeggerService creates a new LeggerService
+func NewLeggerService(
+	rombelRepo *repositories.RombelRepository,
+	nilaiRepo *repositories.NilaiSemesterRepository,
+	peringkatService *PeringkatService,
+) *LeggerService {
+	return &LeggerService{
+		rombelRepo:       rombelRepo,
+		nilaiRepo:        nilaiRepo,
+		peringkatService: peringkatService,
+	}
+}
+
+// Get builds the legger of a class group for a semester of its academic year.
+// Students are listed by name; ranks in the class and in the tingkat come from
+// the ranking service, and students without grades are not ranked.
+func (s *LeggerService) Get(rombelID uint, semester uint8) (*responses.LeggerResponse, error) {
+	rombel, err := s.rombelRepo.FindByID(rombelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class group not found")
+		}
+		return nil, err
+	}
+
+	anggota, err := s.rombelRepo.FindAnggotaByTahun(rombel.TahunPelajaran, rombel.ID)
+	if err != nil {
+		return nil, err
+	}
+	nilai, err := s.nilaiRepo.FindLegger(rombel.ID, rombel.Tingkat, semester, rombel.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+	peringkat, err := s.peringkatService.hitung(rombel.Tingkat, semester, rombel.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
+	// Columns: every subject graded in this class, in report order
+	mapel := make(map[uint]*models.MataPelajaran)
+	nilaiSiswa := make(map[uint]map[uint]models.NilaiSemester)
+	for _, n := range nilai {
+		if n.MataPelajaran != nil {
+			mapel[n.MataPelajaranID] = n.MataPelajaran
+		}
+		if nilaiSiswa[n.SiswaID] == nil {
+			nilaiSiswa[n.SiswaID] = make(map[uint]models.NilaiSemester)
+		}
+		nilaiSiswa[n.SiswaID][n.MataPelajaranID] = n
+	}
+	kolom := make([]*models.MataPelajaran, 0, len(mapel))
+	for _, m := range mapel {
+		kolom = append(kolom, m)
+	}
+	sort.Slice(kolom, func(i, j int) bool {
+		a, b := kolom[i], kolom[j]
+		if a.Kelompok != b.Kelompok {
+			return a.Kelompok < b.Kelompok
+		}
+		if a.SubKelompok != b.SubKelompok {
+			return a.SubKelompok < b.SubKelompok
+		}
+		return a.Kode < b.Kode
+	})
+
+	resp := &responses.LeggerResponse{
+		RombelID:       rombel.ID,
+		Rombel:         rombel.Nama,
+		Kelas:          rombel.Tingkat,
+		Semester:       semester,
+		TahunPelajaran: rombel.TahunPelajaran,
+		MataPelajaran:  []responses.MataPelajaranResponse{},
+		Siswa:          []responses.LeggerSiswaResponse{},
+	}
+	for _, m := range kolom {
+		resp.MataPelajaran = append(resp.MataPelajaran, *toMataPelajaranResponse(m))
+	}
+
+	for _, a := range anggota {
+		// Soft-deleted students are not preloaded
+		if a.Siswa == nil {
+			continue
+		}
+
+		row := responses.LeggerSiswaResponse{
+			SiswaID:     a.Siswa.ID,
+			NoInduk:     a.Siswa.NoInduk,
+			NISN:        a.Siswa.NISN,
+			NamaLengkap: a.Siswa.NamaLengkap,
+			Nilai:       make([]responses.LeggerNilaiResponse, len(kolom)),
+		}
+		jumlahMapel := 0
+		for i, m := range kolom {
+			row.Nilai[i].MataPelajaranID = m.ID
+			n, ok := nilaiSiswa[a.SiswaID][m.ID]
+			if !ok {
+				continue
+			}
+			pengetahuan, keterampilan := n.NilaiPengetahuan, n.NilaiKeterampilan
+			row.Nilai[i].NilaiPengetahuan = &pengetahuan
+			row.Nilai[i].NilaiKeterampilan = &keterampilan
+			row.JumlahPengetahuan += pengetahuan
+			row.JumlahKeterampilan += keterampilan
+			jumlahMapel++
+		}
+		row.Jumlah = row.JumlahPengetahuan + row.JumlahKeterampilan
+		if jumlahMapel > 0 {
+			row.RataRataPengetahuan = rataRata(row.JumlahPengetahuan, jumlahMapel)
+			row.RataRataKeterampilan = rataRata(row.JumlahKeterampilan, jumlahMapel)
+			row.RataRata = rataRata(row.Jumlah, 2*jumlahMapel)
+		}
+		if p, ok := peringkat[a.SiswaID]; ok {
+			row.NilaiPeringkat = p.rataRata
+			row.Peringkat = p.peringkatKelas
+			row.PeringkatTingkat = p.peringkatTingkat
+		}
+
+		resp.Siswa = append(resp.Siswa, row)
+	}
+
+	sort.SliceStable(resp.Siswa, func(i, j int) bool {
+		return resp.Siswa[i].NamaLengkap < resp.Siswa[j].NamaLengkap
+	})
+
+	return resp, nil
+}
+
+// GenerateXLSX renders the legger of a class group as a spreadsheet
+func (s *LeggerService) GenerateXLSX(rombelID uint, semester uint8) ([]byte, string, error) {
+	legger, err := s.Get(rombelID, semester)
+	if err != nil {
+		return nil, "", err
+	}
+	// Stored text is HTML-escaped; the spreadsheet holds plain text
+	utils.UnescapeStrings(legger)
+
+	// Two header rows: subject names spanning a P/K column pair, then P/K
+	jumlahKolom := 4 + 2*len(legger.MataPelajaran) + 9
+	judul := []interface{}{fmt.Sprintf("LEGGER NILAI KELAS %s - SEMESTER %d - TAHUN PELAJARAN %s", legger.Rombel, legger.Semester, legger.TahunPelajaran)}
+	atas := []interface{}{"No", "NIS", "NISN", "Nama Siswa"}
+	bawah := []interface{}{nil, nil, nil, nil}
+	merges := []string{"A1:" + utils.XLSXColumn(jumlahKolom-1) + "1"}
+	for i := 0; i < 4; i++ {
+		merges = append(merges, fmt.Sprintf("%s3:%s4", utils.XLSXColumn(i), utils.XLSXColumn(i)))
+	}
+	widths := []float64{5, 12, 14, 30}
+	for i, m := range legger.MataPelajaran {
+		col := 4 + 2*i
+		atas = append(atas, m.Nama, nil)
+		bawah = append(bawah, "P", "K")
+		merges = append(merges, fmt.Sprintf("%s3:%s3", utils.XLSXColumn(col), utils.XLSXColumn(col+1)))
+		widths = append(widths, 6, 6)
+	}
+	for i, label := range []string{"Jumlah P", "Jumlah K", "Jumlah", "Rata-rata P", "Rata-rata K", "Rata-rata", "Nilai Peringkat", "Peringkat Kelas", "Peringkat Tingkat"} {
+		col := 4 + 2*len(legger.MataPelajaran) + i
+		atas = append(atas, label)
+		bawah = append(bawah, nil)
+		merges = append(merges, fmt.Sprintf("%s3:%s4", utils.XLSXColumn(col), utils.XLSXColumn(col)))
+		widths = append(widths, 11)
+	}
+
+	rows := [][]interface{}{judul, {}, atas, bawah}
+	for i, siswa := range legger.Siswa {
+		row := []interface{}{i + 1, siswa.NoInduk, siswa.NISN, siswa.NamaLengkap}
+		for _, n := range siswa.Nilai {
+			if n.NilaiPengetahuan == nil {
+				row = append(row, nil, nil)
+				continue
+			}
+			row = append(row, *n.NilaiPengetahuan, *n.NilaiKeterampilan)
+		}
+		// Students without grades are left unranked
+		var nilaiPeringkat, peringkat, peringkatTingkat interface{}
+		if siswa.Peringkat > 0 {
+			nilaiPeringkat, peringkat, peringkatTingkat = siswa.NilaiPeringkat, siswa.Peringkat, siswa.PeringkatTingkat
+		}
+		row = append(row, siswa.JumlahPengetahuan, siswa.JumlahKeterampilan, siswa.Jumlah,
+			siswa.RataRataPengetahuan, siswa.RataRataKeterampilan, siswa.RataRata, nilaiPeringkat, peringkat, peringkatTingkat)
+		rows = append(rows, row)
+	}
+
+	data, err := utils.WriteXLSX(utils.XLSXSheet{
+		Name:       "Legger " + legger.Rombel,
+		Rows:       rows,
+		HeaderRows: 4,
+		Widths:     widths,
+		Merges:     merges,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("legger-%s-%s-%d.xlsx", legger.Rombel, legger.TahunPelajaran, legger.Semester)
+	return data, sanitizeFilename(filename), nil
+}
+
+// rataRata returns total / n rounded to two decimals
+func rataRata(total uint, n int) float64 {
+	return math.Round(float64(total)*100/float64(n)) / 100
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kampunk/api-siswa/configs"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// maxLoginDelay caps the progressive delay between failed logins
+const maxLoginDelay = 30 * time.Second
+
+// LoginBlockedError is returned while a username or IP address may not try to
+// log in. RetryAfter tells the client how long to wait.
+type LoginBlockedError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return e.Message
+}
+
+// LoginAttemptService guards the login against password guessing and keeps
+// the login history
+type LoginAttemptService struct {
+	userRepo    *repositories.UserRepository
+	attemptRepo *repositories.LoginAttemptRepository
+}
+
+// NewLoginAttemptService creates a new LoginAttemptService
+func NewLoginAttemptService(userRepo *repositories.UserRepository, attemptRepo *repositories.LoginAttemptRepository) *LoginAttemptService {
+	return &LoginAttemptService{userRepo: userRepo, attemptRepo: attemptRepo}
+}
+
+// Check returns a LoginBlockedError when the IP address has failed too often,
+// the account is locked, or the progressive delay after its last failure has
+// not passed. user is nil for an unknown username. The check runs before the
+// password is verified, so a blocked attempt reveals nothing about it.
+func (s *LoginAttemptService) Check(user *models.User, ip string) error {
+	cfg := configs.AppConfig.Login
+	now := time.Now()
+
+	if cfg.IPMaxFailures > 0 {
+		window := time.Duration(cfg.IPWindowMinutes) * time.Minute
+		failures, oldest, err := s.attemptRepo.FailuresByIP(ip, now.Add(-window))
+		if err != nil {
+			return err
+		}
+		if failures >= int64(cfg.IPMaxFailures) && oldest != nil {
+			return &LoginBlockedError{
+				Message:    "too many failed login attempts from this address, try again later",
+				RetryAfter: oldest.Add(window).Sub(now),
+			}
+		}
+	}
+
+	if user == nil {
+		return nil
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		return &LoginBlockedError{
+			Message:    "account is temporarily locked after too many failed login attempts",
+			RetryAfter: user.LockedUntil.Sub(now),
+		}
+	}
+	if user.LastFailedLoginAt != nil {
+		if wait := user.LastFailedLoginAt.Add(loginDelay(user.FailedLoginCount)).Sub(now); wait > 0 {
+			return &LoginBlockedError{
+				Message:    fmt.Sprintf("too many failed login attempts, try again in %d seconds", int(math.Ceil(wait.Seconds()))),
+				RetryAfter: wait,
+			}
+		}
+	}
+	return nil
+}
+
+// Blocked records an attempt rejected by Check. It does not count as a failure.
+func (s *LoginAttemptService) Blocked(user *models.User, username, ip, userAgent string) error {
+	return s.record(user, username, ip, userAgent, false, models.LoginReasonBlocked)
+}
+
+// Failure records a failed login and, for a known user, counts it towards the
+// lockout. The attempt that locks the account gets a LoginBlockedError.
+func (s *LoginAttemptService) Failure(user *models.User, username, ip, userAgent, reason string) error {
+	if err := s.record(user, username, ip, userAgent, false, reason); err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	cfg := configs.AppConfig.Login
+	lockedUntil := time.Now().Add(time.Duration(cfg.LockoutMinutes) * time.Minute)
+	failures, err := s.userRepo.RecordLoginFailure(user.ID, cfg.MaxFailures, lockedUntil)
+	if err != nil {
+		return err
+	}
+	if cfg.MaxFailures > 0 && failures >= cfg.MaxFailures {
+		return &LoginBlockedError{
+			Message:    "account is temporarily locked after too many failed login attempts",
+			RetryAfter: time.Until(lockedUntil),
+		}
+	}
+	return nil
+}
+
+// Success records a successful login and clears the user's failed logins
+func (s *LoginAttemptService) Success(user *models.User, ip, userAgent string) error {
+	if err := s.record(user, user.Username, ip, userAgent, true, ""); err != nil {
+		return err
+	}
+	if user.FailedLoginCount == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	return s.userRepo.ResetLoginFailures(user.ID)
+}
+
+// Unlock clears the failed logins and lock of a user. Blocks of IP addresses
+// expire on their own.
+func (s *LoginAttemptService) Unlock(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	return s.userRepo.ResetLoginFailures(user.ID)
+}
+
+// History returns the login history of a user, newest first
+func (s *LoginAttemptService) History(userID uint, page, pageSize int) ([]responses.LoginAttemptResponse, utils.Pagination, error) {
+	attempts, total, err := s.attemptRepo.FindByUserIDPaginated(userID, page, pageSize)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	result := []responses.LoginAttemptResponse{}
+	for _, a := range attempts {
+		result = append(result, responses.LoginAttemptResponse{
+			ID:        a.ID,
+			Username:  a.Username,
+			IPAddress: a.IPAddress,
+			UserAgent: a.UserAgent,
+			Success:   a.Success,
+			Reason:    a.Reason,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+	return result, pagination, nil
+}
+
+func (s *LoginAttemptService) record(user *models.User, username, ip, userAgent string, success bool, reason string) error {
+	if len(username) > 50 {
+		username = username[:50]
+	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	attempt := &models.LoginAttempt{
+		Username:  username,
+		IPAddress: ip,
+		UserAgent: userAgent,
+		Success:   success,
+		Reason:    reason,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	return s.attemptRepo.Create(attempt)
+}
+
+// loginDelay is the wait after the last of a user's consecutive failed logins:
+// none after the first, then 1, 2, 4... seconds up to maxLoginDelay
+func loginDelay(failures int) time.Duration {
+	if failures < 2 {
+		return 0
+	}
+	if failures > 7 {
+		return maxLoginDelay
+	}
+	delay := time.Second << (failures - 2)
+	if delay > maxLoginDelay {
+		return maxLoginDelay
+	}
+	return delay
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// MataPelajaranService handles subject business logic
+type MataPelajaranService struct {
+	mapelRepo *repositories.MataPelajaranRepository
+}
+
+// NewMataPelajaranService creates a new MataPelajaranService
+func NewMataPelajaranService(mapelRepo *repositories.MataPelajaranRepository) *MataPelajaranService {
+	return &MataPelajaranService{mapelRepo: mapelRepo}
+}
+
+// Create creates a new subject
+func (s *MataPelajaranService) Create(req requests.CreateMataPelajaranRequest) (*responses.MataPelajaranResponse, error) {
+	kode := utils.SanitizeString(req.Kode)
+
+	exists, err := s.mapelRepo.ExistsByKode(kode, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("subject code already exists")
+	}
+
+	mapel := &models.MataPelajaran{
+		Kode:        kode,
+		Nama:        utils.SanitizeString(req.Nama),
+		Kelompok:    req.Kelompok,
+		SubKelompok: utils.SanitizeString(req.SubKelompok),
+		Aktif:       true,
+	}
+
+	if err := s.mapelRepo.Create(mapel); err != nil {
+		return nil, err
+	}
+
+	return toMataPelajaranResponse(mapel), nil
+}
+
+// FindAll gets subjects. Only active subjects are listed unless another status is requested.
+func (s *MataPelajaranService) FindAll(filter requests.MataPelajaranFilterRequest) ([]responses.MataPelajaranResponse, error) {
+	filterMap := make(map[string]interface{})
+	switch filter.Status {
+	case "", "aktif":
+		filterMap["aktif"] = true
+	case "nonaktif":
+		filterMap["aktif"] = false
+	}
+	if filter.Kelompok != "" {
+		filterMap["kelompok"] = filter.Kelompok
+	}
+
+	mapelList, err := s.mapelRepo.FindAll(utils.SanitizeString(filter.Search), filterMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.MataPelajaranResponse
+	for i := range mapelList {
+		result = append(result, *toMataPelajaranResponse(&mapelList[i]))
+	}
+	return result, nil
+}
+
+// FindByID finds a subject by ID
+func (s *MataPelajaranService) FindByID(id uint) (*responses.MataPelajaranResponse, error) {
+	mapel, err := s.findByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toMataPelajaranResponse(mapel), nil
+}
+
+// Update updates a subject
+func (s *MataPelajaranService) Update(id uint, req requests.UpdateMataPelajaranRequest) (*responses.MataPelajaranResponse, error) {
+	mapel, err := s.findByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Kode != "" {
+		kode := utils.SanitizeString(req.Kode)
+		exists, err := s.mapelRepo.ExistsByKode(kode, mapel.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.New("subject code already exists")
+		}
+		mapel.Kode = kode
+	}
+	if req.Nama != "" {
+		mapel.Nama = utils.SanitizeString(req.Nama)
+	}
+	if req.Kelompok != "" {
+		mapel.Kelompok = req.Kelompok
+	}
+	if req.SubKelompok != "" {
+		mapel.SubKelompok = utils.SanitizeString(req.SubKelompok)
+	}
+
+	if err := s.mapelRepo.Update(mapel); err != nil {
+		return nil, err
+	}
+
+	return toMataPelajaranResponse(mapel), nil
+}
+
+// SetAktif activates or deactivates a subject. Inactive subjects are hidden
+// from the default listing but keep their grades.
+func (s *MataPelajaranService) SetAktif(id uint, aktif bool) (*responses.MataPelajaranResponse, error) {
+	mapel, err := s.findByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	mapel.Aktif = aktif
+	if err := s.mapelRepo.Update(mapel); err != nil {
+		return nil, err
+	}
+
+	return toMataPelajaranResponse(mapel), nil
+}
+
+// Delete deletes a subject that is not referenced by any grade or curriculum
+func (s *MataPelajaranService) Delete(id uint) error {
+	if _, err := s.findByID(id); err != nil {
+		return err
+	}
+
+	inUse, err := s.mapelRepo.IsInUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("subject is still in use, deactivate it instead")
+	}
+
+	return s.mapelRepo.Delete(id)
+}
+
+// findByID finds a subject and maps a missing record to a friendly error
+func (s *MataPelajaranService) findByID(id uint) (*models.MataPelajaran, error) {
+	mapel, err := s.mapelRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("subject not found")
+		}
+		return nil, err
+	}
+	return mapel, nil
+}
+
+// toMataPelajaranResponse converts a subject to DTO
+func toMataPelajaranResponse(m *models.MataPelajaran) *responses.MataPelajaranResponse {
+	return &responses.MataPelajaranResponse{
+		ID:          m.ID,
+		Kode:        m.Kode,
+		Nama:        m.Nama,
+		Kelompok:    m.Kelompok,
+		SubKelompok: m.SubKelompok,
+		Aktif:       m.Aktif,
+	}
+}
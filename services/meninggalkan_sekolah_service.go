@@ -0,0 +1,253 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// MeninggalkanSekolahService handles leaving school business logic
+type MeninggalkanSekolahService struct {
+	siswaRepo  *repositories.SiswaRepository
+	keluarRepo *repositories.MeninggalkanSekolahRepository
+	rombelRepo *repositories.RombelRepository
+	tahunRepo  *repositories.TahunPelajaranRepository
+}
+
+// NewMeninggalkanSekolahService creates a new MeninggalkanSekolahService
+func NewMeninggalkanSekolahService(
+	siswaRepo *repositories.SiswaRepository,
+	keluarRepo *repositories.MeninggalkanSekolahRepository,
+	rombelRepo *repositories.RombelRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+) *MeninggalkanSekolahService {
+	return &MeninggalkanSekolahService{
+		siswaRepo:  siswaRepo,
+		keluarRepo: keluarRepo,
+		rombelRepo: rombelRepo,
+		tahunRepo:  tahunRepo,
+	}
+}
+
+// Create records that a student left school
+func (s *MeninggalkanSekolahService) Create(siswaID uint, req requests.CreateMeninggalkanSekolahRequest) (*responses.MeninggalkanSekolahResponse, error) {
+	// Validate student exists
+	_, err := s.siswaRepo.FindByID(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	// Check if record already exists
+	_, err = s.keluarRepo.FindBySiswaID(siswaID)
+	if err == nil {
+		return nil, errors.New("student already has a leaving school record")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	keluar := &models.MeninggalkanSekolah{SiswaID: siswaID}
+	if err := s.apply(keluar, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.keluarRepo.Create(keluar); err != nil {
+		return nil, err
+	}
+
+	return toMeninggalkanSekolahResponse(keluar), nil
+}
+
+// GetBySiswaID gets the leaving school record of a student
+func (s *MeninggalkanSekolahService) GetBySiswaID(siswaID uint) (*responses.MeninggalkanSekolahResponse, error) {
+	keluar, err := s.keluarRepo.FindBySiswaID(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leaving school record not found")
+		}
+		return nil, err
+	}
+	return toMeninggalkanSekolahResponse(keluar), nil
+}
+
+// Update updates the leaving school record of a student
+func (s *MeninggalkanSekolahService) Update(siswaID uint, req requests.CreateMeninggalkanSekolahRequest) (*responses.MeninggalkanSekolahResponse, error) {
+	keluar, err := s.keluarRepo.FindBySiswaID(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leaving school record not found")
+		}
+		return nil, err
+	}
+
+	if err := s.apply(keluar, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.keluarRepo.Update(keluar); err != nil {
+		return nil, err
+	}
+
+	return toMeninggalkanSekolahResponse(keluar), nil
+}
+
+// Delete removes the leaving school record, making the student active again
+func (s *MeninggalkanSekolahService) Delete(siswaID uint) error {
+	if _, err := s.keluarRepo.FindBySiswaID(siswaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("leaving school record not found")
+		}
+		return err
+	}
+	return s.keluarRepo.DeleteBySiswaID(siswaID)
+}
+
+// Luluskan graduates every active grade XII student of an academic year
+func (s *MeninggalkanSekolahService) Luluskan(req requests.KelulusanRequest) (*responses.KelulusanResponse, error) {
+	tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
+	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
+	if err != nil {
+		return nil, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+
+	if req.RombelID > 0 {
+		rombel, err := s.rombelRepo.FindByID(req.RombelID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("class group not found")
+			}
+			return nil, err
+		}
+		if rombel.TahunPelajaran != tahunPelajaran || rombel.Tingkat != "XII" {
+			return nil, errors.New("class group is not a grade XII class of the academic year")
+		}
+	}
+
+	anggota, err := s.rombelRepo.FindAnggotaByTahun(tahunPelajaran, req.RombelID)
+	if err != nil {
+		return nil, err
+	}
+
+	kecuali := make(map[uint]bool, len(req.Kecuali))
+	for _, id := range req.Kecuali {
+		kecuali[id] = true
+	}
+
+	var kohort []models.AnggotaRombel
+	var siswaIDs []uint
+	for _, a := range anggota {
+		if a.Siswa == nil || a.Rombel == nil || a.Rombel.Tingkat != "XII" || kecuali[a.SiswaID] {
+			continue
+		}
+		kohort = append(kohort, a)
+		siswaIDs = append(siswaIDs, a.SiswaID)
+	}
+
+	// Students who already left (e.g. transferred mid-year) are not graduated
+	keluarIDs, err := s.keluarRepo.FindSiswaIDsBySiswaIDs(siswaIDs)
+	if err != nil {
+		return nil, err
+	}
+	sudahKeluar := make(map[uint]bool, len(keluarIDs))
+	for _, id := range keluarIDs {
+		sudahKeluar[id] = true
+	}
+
+	inKohort := make(map[uint]bool, len(siswaIDs))
+	for _, id := range siswaIDs {
+		if !sudahKeluar[id] {
+			inKohort[id] = true
+		}
+	}
+
+	noIjazah := make(map[uint]string, len(req.NoIjazah))
+	for _, n := range req.NoIjazah {
+		if !inKohort[n.SiswaID] {
+			return nil, fmt.Errorf("student %d is not part of the graduating cohort", n.SiswaID)
+		}
+		noIjazah[n.SiswaID] = utils.SanitizeString(n.NoIjazah)
+	}
+
+	result := &responses.KelulusanResponse{
+		TahunPelajaran: tahunPelajaran,
+		Tanggal:        tanggal,
+	}
+	var keluar []models.MeninggalkanSekolah
+	for _, a := range kohort {
+		if !inKohort[a.SiswaID] {
+			continue
+		}
+		keluar = append(keluar, models.MeninggalkanSekolah{
+			SiswaID:  a.SiswaID,
+			Tipe:     "tamat",
+			Tanggal:  tanggal,
+			NoIjazah: noIjazah[a.SiswaID],
+		})
+		result.Siswa = append(result.Siswa, responses.KelulusanSiswaResponse{
+			SiswaID:     a.SiswaID,
+			NoInduk:     a.Siswa.NoInduk,
+			NamaLengkap: a.Siswa.NamaLengkap,
+			Rombel:      a.Rombel.Nama,
+			NoIjazah:    noIjazah[a.SiswaID],
+		})
+	}
+
+	if len(keluar) == 0 {
+		return nil, errors.New("no students left to graduate for this academic year")
+	}
+
+	if err := s.keluarRepo.CreateBatch(keluar); err != nil {
+		return nil, err
+	}
+
+	result.JumlahLulus = len(keluar)
+	return result, nil
+}
+
+// apply copies request data onto a leaving school record
+func (s *MeninggalkanSekolahService) apply(keluar *models.MeninggalkanSekolah, req requests.CreateMeninggalkanSekolahRequest) error {
+	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
+	if err != nil {
+		return errors.New("invalid date format, use YYYY-MM-DD")
+	}
+
+	sekolahTujuan := utils.SanitizeString(req.SekolahTujuan)
+	if req.Tipe == "pindah" && sekolahTujuan == "" {
+		return errors.New("destination school is required for transferred students")
+	}
+
+	keluar.Tipe = req.Tipe
+	keluar.Tanggal = tanggal
+	keluar.SekolahTujuan = sekolahTujuan
+	keluar.AlamatSekolahTujuan = utils.SanitizeString(req.AlamatSekolahTujuan)
+	keluar.NoIjazah = utils.SanitizeString(req.NoIjazah)
+	keluar.Alasan = utils.SanitizeString(req.Alasan)
+	return nil
+}
+
+// toMeninggalkanSekolahResponse converts to DTO
+func toMeninggalkanSekolahResponse(k *models.MeninggalkanSekolah) *responses.MeninggalkanSekolahResponse {
+	return &responses.MeninggalkanSekolahResponse{
+		ID:                  k.ID,
+		Tipe:                k.Tipe,
+		Tanggal:             k.Tanggal,
+		SekolahTujuan:       k.SekolahTujuan,
+		AlamatSekolahTujuan: k.AlamatSekolahTujuan,
+		NoIjazah:            k.NoIjazah,
+		Alasan:              k.Alasan,
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/kampunk/api-siswa/dtos/requests"
@@ -22,6 +23,9 @@ type NilaiService struct {
 	catatanRepo   *repositories.CatatanRepository
 	ijazahRepo    *repositories.NilaiIjazahRepository
 	kehadiranRepo *repositories.KehadiranRepository
+	tahunRepo     *repositories.TahunPelajaranRepository
+	skemaRepo     *repositories.SkemaPenilaianRepository
+	kurikulumRepo *repositories.KurikulumRepository
 }
 
 // NewNilaiService creates a new NilaiService
@@ -33,6 +37,9 @@ func NewNilaiService(
 	catatanRepo *repositories.CatatanRepository,
 	ijazahRepo *repositories.NilaiIjazahRepository,
 	kehadiranRepo *repositories.KehadiranRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+	skemaRepo *repositories.SkemaPenilaianRepository,
+	kurikulumRepo *repositories.KurikulumRepository,
 ) *NilaiService {
 	return &NilaiService{
 		siswaRepo:     siswaRepo,
@@ -42,33 +49,16 @@ func NewNilaiService(
 		catatanRepo:   catatanRepo,
 		ijazahRepo:    ijazahRepo,
 		kehadiranRepo: kehadiranRepo,
+		tahunRepo:     tahunRepo,
+		skemaRepo:     skemaRepo,
+		kurikulumRepo: kurikulumRepo,
 	}
 }
 
-// GetAllMataPelajaran gets all subjects
-func (s *NilaiService) GetAllMataPelajaran() ([]responses.MataPelajaranResponse, error) {
-	mapel, err := s.mapelRepo.FindAll()
-	if err != nil {
-		return nil, err
-	}
-
-	var result []responses.MataPelajaranResponse
-	for _, m := range mapel {
-		result = append(result, responses.MataPelajaranResponse{
-			ID:          m.ID,
-			Kode:        m.Kode,
-			Nama:        m.Nama,
-			Kelompok:    m.Kelompok,
-			SubKelompok: m.SubKelompok,
-		})
-	}
-	return result, nil
-}
-
 // CreateNilaiSemester creates a semester grade
 func (s *NilaiService) CreateNilaiSemester(siswaID uint, req requests.CreateNilaiSemesterRequest) (*responses.NilaiSemesterResponse, error) {
 	// Validate student exists
-	_, err := s.siswaRepo.FindByID(siswaID)
+	siswa, err := s.siswaRepo.FindByID(siswaID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("student not found")
@@ -85,13 +75,18 @@ func (s *NilaiService) CreateNilaiSemester(siswaID uint, req requests.CreateNila
 		return nil, err
 	}
 
+	tahunPelajaran, semester, err := s.resolvePeriode(req.TahunPelajaran, req.Semester)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create nilai
 	nilai := &models.NilaiSemester{
 		SiswaID:               siswaID,
 		MataPelajaranID:       req.MataPelajaranID,
 		Kelas:                 req.Kelas,
-		Semester:              req.Semester,
-		TahunPelajaran:        utils.SanitizeString(req.TahunPelajaran),
+		Semester:              semester,
+		TahunPelajaran:        tahunPelajaran,
 		NilaiPengetahuan:      req.NilaiPengetahuan,
 		PredikatPengetahuan:   req.PredikatPengetahuan,
 		DeskripsiPengetahuan:  utils.SanitizeString(req.DeskripsiPengetahuan),
@@ -100,19 +95,20 @@ func (s *NilaiService) CreateNilaiSemester(siswaID uint, req requests.CreateNila
 		DeskripsiKeterampilan: utils.SanitizeString(req.DeskripsiKeterampilan),
 	}
 
+	if err := s.validateKurikulum(siswa, nilai, mapel); err != nil {
+		return nil, err
+	}
+	if err := s.terapkanPredikat(nilai); err != nil {
+		return nil, err
+	}
+
 	if err := s.nilaiRepo.Create(nilai); err != nil {
 		return nil, err
 	}
 
 	return &responses.NilaiSemesterResponse{
-		ID: nilai.ID,
-		MataPelajaran: &responses.MataPelajaranResponse{
-			ID:          mapel.ID,
-			Kode:        mapel.Kode,
-			Nama:        mapel.Nama,
-			Kelompok:    mapel.Kelompok,
-			SubKelompok: mapel.SubKelompok,
-		},
+		ID:                    nilai.ID,
+		MataPelajaran:         toMataPelajaranResponse(mapel),
 		Kelas:                 nilai.Kelas,
 		Semester:              nilai.Semester,
 		TahunPelajaran:        nilai.TahunPelajaran,
@@ -128,7 +124,7 @@ func (s *NilaiService) CreateNilaiSemester(siswaID uint, req requests.CreateNila
 // BatchCreateNilaiSemester creates multiple semester grades
 func (s *NilaiService) BatchCreateNilaiSemester(siswaID uint, req requests.BatchNilaiSemesterRequest) ([]responses.NilaiSemesterResponse, error) {
 	// Validate student exists
-	_, err := s.siswaRepo.FindByID(siswaID)
+	siswa, err := s.siswaRepo.FindByID(siswaID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("student not found")
@@ -139,7 +135,7 @@ func (s *NilaiService) BatchCreateNilaiSemester(siswaID uint, req requests.Batch
 	var nilaiList []models.NilaiSemester
 	for _, n := range req.Nilai {
 		// Validate subject exists
-		_, err := s.mapelRepo.FindByID(n.MataPelajaranID)
+		mapel, err := s.mapelRepo.FindByID(n.MataPelajaranID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, errors.New("subject not found")
@@ -147,19 +143,32 @@ func (s *NilaiService) BatchCreateNilaiSemester(siswaID uint, req requests.Batch
 			return nil, err
 		}
 
-		nilaiList = append(nilaiList, models.NilaiSemester{
+		tahunPelajaran, semester, err := s.resolvePeriode(n.TahunPelajaran, n.Semester)
+		if err != nil {
+			return nil, err
+		}
+
+		nilai := models.NilaiSemester{
 			SiswaID:               siswaID,
 			MataPelajaranID:       n.MataPelajaranID,
 			Kelas:                 n.Kelas,
-			Semester:              n.Semester,
-			TahunPelajaran:        utils.SanitizeString(n.TahunPelajaran),
+			Semester:              semester,
+			TahunPelajaran:        tahunPelajaran,
 			NilaiPengetahuan:      n.NilaiPengetahuan,
 			PredikatPengetahuan:   n.PredikatPengetahuan,
 			DeskripsiPengetahuan:  utils.SanitizeString(n.DeskripsiPengetahuan),
 			NilaiKeterampilan:     n.NilaiKeterampilan,
 			PredikatKeterampilan:  n.PredikatKeterampilan,
 			DeskripsiKeterampilan: utils.SanitizeString(n.DeskripsiKeterampilan),
-		})
+		}
+		if err := s.validateKurikulum(siswa, &nilai, mapel); err != nil {
+			return nil, err
+		}
+		if err := s.terapkanPredikat(&nilai); err != nil {
+			return nil, err
+		}
+
+		nilaiList = append(nilaiList, nilai)
 	}
 
 	if err := s.nilaiRepo.CreateBatch(nilaiList); err != nil {
@@ -198,13 +207,7 @@ func (s *NilaiService) GetNilaiSemester(siswaID uint, filter requests.NilaiFilte
 		}
 
 		if n.MataPelajaran != nil {
-			resp.MataPelajaran = &responses.MataPelajaranResponse{
-				ID:          n.MataPelajaran.ID,
-				Kode:        n.MataPelajaran.Kode,
-				Nama:        n.MataPelajaran.Nama,
-				Kelompok:    n.MataPelajaran.Kelompok,
-				SubKelompok: n.MataPelajaran.SubKelompok,
-			}
+			resp.MataPelajaran = toMataPelajaranResponse(n.MataPelajaran)
 		}
 
 		result = append(result, resp)
@@ -257,18 +260,12 @@ func (s *NilaiService) CreateNilaiIjazah(siswaID uint, req requests.CreateNilaiI
 	}
 
 	return &responses.NilaiIjazahResponse{
-		ID: nilai.ID,
-		MataPelajaran: &responses.MataPelajaranResponse{
-			ID:          mapel.ID,
-			Kode:        mapel.Kode,
-			Nama:        mapel.Nama,
-			Kelompok:    mapel.Kelompok,
-			SubKelompok: mapel.SubKelompok,
-		},
-		NilaiAkhir:   nilai.NilaiAkhir,
-		TahunLulus:   nilai.TahunLulus,
-		NoIjazah:     nilai.NoIjazah,
-		TanggalLulus: nilai.TanggalLulus,
+		ID:            nilai.ID,
+		MataPelajaran: toMataPelajaranResponse(mapel),
+		NilaiAkhir:    nilai.NilaiAkhir,
+		TahunLulus:    nilai.TahunLulus,
+		NoIjazah:      nilai.NoIjazah,
+		TanggalLulus:  nilai.TanggalLulus,
 	}, nil
 }
 
@@ -290,13 +287,7 @@ func (s *NilaiService) GetNilaiIjazah(siswaID uint) ([]responses.NilaiIjazahResp
 		}
 
 		if n.MataPelajaran != nil {
-			resp.MataPelajaran = &responses.MataPelajaranResponse{
-				ID:          n.MataPelajaran.ID,
-				Kode:        n.MataPelajaran.Kode,
-				Nama:        n.MataPelajaran.Nama,
-				Kelompok:    n.MataPelajaran.Kelompok,
-				SubKelompok: n.MataPelajaran.SubKelompok,
-			}
+			resp.MataPelajaran = toMataPelajaranResponse(n.MataPelajaran)
 		}
 
 		result = append(result, resp)
@@ -305,118 +296,86 @@ func (s *NilaiService) GetNilaiIjazah(siswaID uint) ([]responses.NilaiIjazahResp
 	return result, nil
 }
 
-// CreateCatatanSemester creates semester notes
-func (s *NilaiService) CreateCatatanSemester(siswaID uint, req requests.CreateCatatanSemesterRequest) (*responses.CatatanSemesterResponse, error) {
-	// Validate student exists
-	_, err := s.siswaRepo.FindByID(siswaID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("student not found")
-		}
-		return nil, err
-	}
-
-	catatan := &models.CatatanAkhirSemester{
-		SiswaID:  siswaID,
-		Kelas:    req.Kelas,
-		Semester: req.Semester,
+// validateKurikulum rejects grades for inactive subjects and for subjects
+// outside the curriculum of the student's kompetensi keahlian. Students without
+// a kompetensi keahlian, and kompetensi keahlian without a curriculum for the
+// kelas and semester, are not checked against a curriculum.
+func (s *NilaiService) validateKurikulum(siswa *models.Siswa, nilai *models.NilaiSemester, mapel *models.MataPelajaran) error {
+	if !mapel.Aktif {
+		return fmt.Errorf("subject %s is inactive", mapel.Nama)
 	}
-
-	if err := s.catatanRepo.Create(catatan); err != nil {
-		return nil, err
+	if siswa.KompetensiKeahlianID == nil {
+		return nil
 	}
 
-	return &responses.CatatanSemesterResponse{
-		ID:       catatan.ID,
-		Kelas:    catatan.Kelas,
-		Semester: catatan.Semester,
-	}, nil
-}
-
-// GetCatatanSemester gets semester notes for a student
-func (s *NilaiService) GetCatatanSemester(siswaID uint) ([]responses.CatatanSemesterResponse, error) {
-	catatanList, err := s.catatanRepo.FindBySiswaID(siswaID)
+	mapelIDs, err := s.kurikulumRepo.FindMataPelajaranIDs(*siswa.KompetensiKeahlianID, nilai.Kelas, nilai.Semester)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if len(mapelIDs) == 0 {
+		return nil
 	}
 
-	var result []responses.CatatanSemesterResponse
-	for _, c := range catatanList {
-		resp := responses.CatatanSemesterResponse{
-			ID:       c.ID,
-			Kelas:    c.Kelas,
-			Semester: c.Semester,
-		}
-
-		for _, pkl := range c.PKL {
-			resp.PKL = append(resp.PKL, responses.PKLResponse{
-				ID:         pkl.ID,
-				NamaDUDI:   pkl.NamaDUDI,
-				Lokasi:     pkl.Lokasi,
-				LamaBulan:  pkl.LamaBulan,
-				Keterangan: pkl.Keterangan,
-			})
-		}
-
-		for _, eks := range c.Ekstrakurikuler {
-			resp.Ekstrakurikuler = append(resp.Ekstrakurikuler, responses.EkstrakurikulerResponse{
-				ID:           eks.ID,
-				NamaKegiatan: eks.NamaKegiatan,
-				Keterangan:   eks.Keterangan,
-			})
-		}
-
-		for _, pres := range c.PrestasiSemester {
-			resp.PrestasiSemester = append(resp.PrestasiSemester, responses.PrestasiSemesterResponse{
-				ID:            pres.ID,
-				JenisPrestasi: pres.JenisPrestasi,
-				Keterangan:    pres.Keterangan,
-			})
-		}
-
-		if c.Ketidakhadiran != nil {
-			resp.Ketidakhadiran = &responses.KetidakhadiranResponse{
-				ID:              c.Ketidakhadiran.ID,
-				KarenaSakit:     c.Ketidakhadiran.KarenaSakit,
-				DenganIzin:      c.Ketidakhadiran.DenganIzin,
-				TanpaKeterangan: c.Ketidakhadiran.TanpaKeterangan,
-			}
+	for _, id := range mapelIDs {
+		if id == mapel.ID {
+			return nil
 		}
-
-		result = append(result, resp)
 	}
-
-	return result, nil
+	return fmt.Errorf("subject %s is not in the curriculum of the student's kompetensi keahlian for kelas %s semester %d", mapel.Nama, nilai.Kelas, nilai.Semester)
 }
 
-// AddPKL adds internship to semester notes
-func (s *NilaiService) AddPKL(catatanID uint, req requests.CreatePKLRequest) (*responses.PKLResponse, error) {
-	// Validate catatan exists
-	_, err := s.catatanRepo.FindByID(catatanID)
+// terapkanPredikat fills in missing predikat from the grading scheme of the
+// grade's subject and academic year, and rejects predikat that contradict the
+// score. Grades without an applicable scheme are stored as submitted.
+func (s *NilaiService) terapkanPredikat(nilai *models.NilaiSemester) error {
+	skema, err := s.skemaRepo.FindForMataPelajaran(nilai.TahunPelajaran, nilai.MataPelajaranID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("semester notes not found")
+			return nil
 		}
-		return nil, err
+		return err
 	}
 
-	pkl := &models.PraktikKerjaLapangan{
-		CatatanID:  catatanID,
-		NamaDUDI:   utils.SanitizeString(req.NamaDUDI),
-		Lokasi:     utils.SanitizeString(req.Lokasi),
-		LamaBulan:  req.LamaBulan,
-		Keterangan: utils.SanitizeString(req.Keterangan),
+	expected := predikatNilai(skema, nilai.NilaiPengetahuan)
+	if nilai.PredikatPengetahuan == "" {
+		nilai.PredikatPengetahuan = expected
+	} else if nilai.PredikatPengetahuan != expected {
+		return fmt.Errorf("predikat_pengetahuan %s does not match nilai_pengetahuan %d, expected %s", nilai.PredikatPengetahuan, nilai.NilaiPengetahuan, expected)
 	}
 
-	if err := s.catatanRepo.AddPKL(pkl); err != nil {
-		return nil, err
+	expected = predikatNilai(skema, nilai.NilaiKeterampilan)
+	if nilai.PredikatKeterampilan == "" {
+		nilai.PredikatKeterampilan = expected
+	} else if nilai.PredikatKeterampilan != expected {
+		return fmt.Errorf("predikat_keterampilan %s does not match nilai_keterampilan %d, expected %s", nilai.PredikatKeterampilan, nilai.NilaiKeterampilan, expected)
 	}
 
-	return &responses.PKLResponse{
-		ID:         pkl.ID,
-		NamaDUDI:   pkl.NamaDUDI,
-		Lokasi:     pkl.Lokasi,
-		LamaBulan:  pkl.LamaBulan,
-		Keterangan: pkl.Keterangan,
-	}, nil
+	return nil
+}
+
+// resolvePeriode validates the academic year and semester of a grade,
+// defaulting to the active academic year when the client omits them
+func (s *NilaiService) resolvePeriode(tahunPelajaran string, semester uint8) (string, uint8, error) {
+	tahunPelajaran = utils.SanitizeString(tahunPelajaran)
+	if tahunPelajaran == "" || semester == 0 {
+		aktif, err := s.tahunRepo.FindActive()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", 0, errors.New("no active academic year, tahun_pelajaran and semester are required")
+			}
+			return "", 0, err
+		}
+		if tahunPelajaran == "" {
+			tahunPelajaran = aktif.Label
+		}
+		if semester == 0 {
+			semester = aktif.SemesterAktif
+		}
+	}
+
+	tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, tahunPelajaran)
+	if err != nil {
+		return "", 0, err
+	}
+	return tahunPelajaran, semester, nil
 }
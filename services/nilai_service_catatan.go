@@ -0,0 +1,389 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// CreateCatatanSemester creates semester notes. Notes are unique per
+// kelas/semester, so re-submitting returns the existing notes with created=false.
+func (s *NilaiService) CreateCatatanSemester(siswaID uint, req requests.CreateCatatanSemesterRequest) (*responses.CatatanSemesterResponse, bool, error) {
+	// Validate student exists
+	_, err := s.siswaRepo.FindByID(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, errors.New("student not found")
+		}
+		return nil, false, err
+	}
+
+	existing, err := s.findCatatanByPeriode(siswaID, req.Kelas, req.Semester)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return toCatatanSemesterResponse(existing), false, nil
+	}
+
+	catatan := &models.CatatanAkhirSemester{
+		SiswaID:  siswaID,
+		Kelas:    req.Kelas,
+		Semester: req.Semester,
+	}
+
+	if err := s.catatanRepo.Create(catatan); err != nil {
+		// Lost a race against a concurrent request for the same semester
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			existing, err := s.findCatatanByPeriode(siswaID, req.Kelas, req.Semester)
+			if err != nil {
+				return nil, false, err
+			}
+			if existing != nil {
+				return toCatatanSemesterResponse(existing), false, nil
+			}
+		}
+		return nil, false, err
+	}
+
+	// Start the absence notes from the semester attendance summary, if recorded
+	kehadiran, err := s.kehadiranRepo.FindBySiswaIDAndKelas(siswaID, req.Kelas, req.Semester)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if kehadiran != nil {
+		catatan.Ketidakhadiran = &models.KetidakhadiranCatatan{
+			CatatanID:       catatan.ID,
+			KarenaSakit:     kehadiran.JumlahSakit,
+			DenganIzin:      kehadiran.JumlahIzin,
+			TanpaKeterangan: kehadiran.JumlahAlpa,
+		}
+		if err := s.catatanRepo.SetKetidakhadiran(catatan.Ketidakhadiran); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return toCatatanSemesterResponse(catatan), true, nil
+}
+
+// GetCatatanSemester gets semester notes for a student
+func (s *NilaiService) GetCatatanSemester(siswaID uint) ([]responses.CatatanSemesterResponse, error) {
+	catatanList, err := s.catatanRepo.FindBySiswaID(siswaID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.CatatanSemesterResponse
+	for i := range catatanList {
+		result = append(result, *toCatatanSemesterResponse(&catatanList[i]))
+	}
+
+	return result, nil
+}
+
+// AddPKL adds internship to semester notes
+func (s *NilaiService) AddPKL(catatanID uint, req requests.CreatePKLRequest) (*responses.PKLResponse, error) {
+	if err := s.checkCatatan(catatanID); err != nil {
+		return nil, err
+	}
+
+	pkl := &models.PraktikKerjaLapangan{
+		CatatanID:  catatanID,
+		NamaDUDI:   utils.SanitizeString(req.NamaDUDI),
+		Lokasi:     utils.SanitizeString(req.Lokasi),
+		LamaBulan:  req.LamaBulan,
+		Keterangan: utils.SanitizeString(req.Keterangan),
+	}
+
+	if err := s.catatanRepo.AddPKL(pkl); err != nil {
+		return nil, err
+	}
+
+	return toPKLResponse(pkl), nil
+}
+
+// UpdatePKL updates an internship record
+func (s *NilaiService) UpdatePKL(id uint, req requests.UpdatePKLRequest) (*responses.PKLResponse, error) {
+	pkl, err := s.catatanRepo.FindPKLByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("PKL not found")
+		}
+		return nil, err
+	}
+
+	if req.NamaDUDI != "" {
+		pkl.NamaDUDI = utils.SanitizeString(req.NamaDUDI)
+	}
+	if req.Lokasi != "" {
+		pkl.Lokasi = utils.SanitizeString(req.Lokasi)
+	}
+	if req.LamaBulan > 0 {
+		pkl.LamaBulan = req.LamaBulan
+	}
+	if req.Keterangan != "" {
+		pkl.Keterangan = utils.SanitizeString(req.Keterangan)
+	}
+
+	if err := s.catatanRepo.UpdatePKL(pkl); err != nil {
+		return nil, err
+	}
+
+	return toPKLResponse(pkl), nil
+}
+
+// DeletePKL deletes an internship record
+func (s *NilaiService) DeletePKL(id uint) error {
+	if _, err := s.catatanRepo.FindPKLByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("PKL not found")
+		}
+		return err
+	}
+
+	return s.catatanRepo.DeletePKL(id)
+}
+
+// AddEkstrakurikuler adds an extracurricular activity to semester notes
+func (s *NilaiService) AddEkstrakurikuler(catatanID uint, req requests.CreateEkstrakurikulerRequest) (*responses.EkstrakurikulerResponse, error) {
+	if err := s.checkCatatan(catatanID); err != nil {
+		return nil, err
+	}
+
+	ekskul := &models.Ekstrakurikuler{
+		CatatanID:    catatanID,
+		NamaKegiatan: utils.SanitizeString(req.NamaKegiatan),
+		Keterangan:   utils.SanitizeString(req.Keterangan),
+	}
+
+	if err := s.catatanRepo.AddEkstrakurikuler(ekskul); err != nil {
+		return nil, err
+	}
+
+	return toEkstrakurikulerResponse(ekskul), nil
+}
+
+// UpdateEkstrakurikuler updates an extracurricular activity
+func (s *NilaiService) UpdateEkstrakurikuler(id uint, req requests.UpdateEkstrakurikulerRequest) (*responses.EkstrakurikulerResponse, error) {
+	ekskul, err := s.catatanRepo.FindEkstrakurikulerByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("extracurricular not found")
+		}
+		return nil, err
+	}
+
+	if req.NamaKegiatan != "" {
+		ekskul.NamaKegiatan = utils.SanitizeString(req.NamaKegiatan)
+	}
+	if req.Keterangan != "" {
+		ekskul.Keterangan = utils.SanitizeString(req.Keterangan)
+	}
+
+	if err := s.catatanRepo.UpdateEkstrakurikuler(ekskul); err != nil {
+		return nil, err
+	}
+
+	return toEkstrakurikulerResponse(ekskul), nil
+}
+
+// DeleteEkstrakurikuler deletes an extracurricular activity
+func (s *NilaiService) DeleteEkstrakurikuler(id uint) error {
+	if _, err := s.catatanRepo.FindEkstrakurikulerByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("extracurricular not found")
+		}
+		return err
+	}
+
+	return s.catatanRepo.DeleteEkstrakurikuler(id)
+}
+
+// AddPrestasiSemester adds an achievement to semester notes
+func (s *NilaiService) AddPrestasiSemester(catatanID uint, req requests.CreatePrestasiSemesterRequest) (*responses.PrestasiSemesterResponse, error) {
+	if err := s.checkCatatan(catatanID); err != nil {
+		return nil, err
+	}
+
+	prestasi := &models.PrestasiSemester{
+		CatatanID:     catatanID,
+		JenisPrestasi: utils.SanitizeString(req.JenisPrestasi),
+		Keterangan:    utils.SanitizeString(req.Keterangan),
+	}
+
+	if err := s.catatanRepo.AddPrestasiSemester(prestasi); err != nil {
+		return nil, err
+	}
+
+	return toPrestasiSemesterResponse(prestasi), nil
+}
+
+// UpdatePrestasiSemester updates a semester achievement
+func (s *NilaiService) UpdatePrestasiSemester(id uint, req requests.UpdatePrestasiSemesterRequest) (*responses.PrestasiSemesterResponse, error) {
+	prestasi, err := s.catatanRepo.FindPrestasiSemesterByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("semester achievement not found")
+		}
+		return nil, err
+	}
+
+	if req.JenisPrestasi != "" {
+		prestasi.JenisPrestasi = utils.SanitizeString(req.JenisPrestasi)
+	}
+	if req.Keterangan != "" {
+		prestasi.Keterangan = utils.SanitizeString(req.Keterangan)
+	}
+
+	if err := s.catatanRepo.UpdatePrestasiSemester(prestasi); err != nil {
+		return nil, err
+	}
+
+	return toPrestasiSemesterResponse(prestasi), nil
+}
+
+// DeletePrestasiSemester deletes a semester achievement
+func (s *NilaiService) DeletePrestasiSemester(id uint) error {
+	if _, err := s.catatanRepo.FindPrestasiSemesterByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("semester achievement not found")
+		}
+		return err
+	}
+
+	return s.catatanRepo.DeletePrestasiSemester(id)
+}
+
+// SetKetidakhadiran sets the absence counts of semester notes. Semesters with an
+// attendance summary keep their absence in sync with it and cannot be edited here.
+func (s *NilaiService) SetKetidakhadiran(catatanID uint, req requests.SetKetidakhadiranRequest) (*responses.KetidakhadiranResponse, error) {
+	catatan, err := s.catatanRepo.FindByID(catatanID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("semester notes not found")
+		}
+		return nil, err
+	}
+
+	if _, err := s.kehadiranRepo.FindBySiswaIDAndKelas(catatan.SiswaID, catatan.Kelas, catatan.Semester); err == nil {
+		return nil, errors.New("absence is taken from the attendance summary of this semester")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	ketidakhadiran := catatan.Ketidakhadiran
+	if ketidakhadiran == nil {
+		ketidakhadiran = &models.KetidakhadiranCatatan{CatatanID: catatan.ID}
+	}
+	ketidakhadiran.KarenaSakit = req.KarenaSakit
+	ketidakhadiran.DenganIzin = req.DenganIzin
+	ketidakhadiran.TanpaKeterangan = req.TanpaKeterangan
+
+	if err := s.catatanRepo.SetKetidakhadiran(ketidakhadiran); err != nil {
+		return nil, err
+	}
+
+	return toKetidakhadiranResponse(ketidakhadiran), nil
+}
+
+// DeleteKetidakhadiran removes the absence counts of semester notes
+func (s *NilaiService) DeleteKetidakhadiran(catatanID uint) error {
+	affected, err := s.catatanRepo.DeleteKetidakhadiran(catatanID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("absence notes not found")
+	}
+	return nil
+}
+
+// checkCatatan checks that semester notes exist
+func (s *NilaiService) checkCatatan(catatanID uint) error {
+	if _, err := s.catatanRepo.FindByID(catatanID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("semester notes not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// findCatatanByPeriode returns a student's notes for a kelas/semester, or nil if none exist
+func (s *NilaiService) findCatatanByPeriode(siswaID uint, kelas string, semester uint8) (*models.CatatanAkhirSemester, error) {
+	catatan, err := s.catatanRepo.FindBySiswaKelasSemester(siswaID, kelas, semester)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return catatan, nil
+}
+
+// toCatatanSemesterResponse converts semester notes and their children to DTO
+func toCatatanSemesterResponse(c *models.CatatanAkhirSemester) *responses.CatatanSemesterResponse {
+	resp := &responses.CatatanSemesterResponse{
+		ID:       c.ID,
+		Kelas:    c.Kelas,
+		Semester: c.Semester,
+	}
+
+	for i := range c.PKL {
+		resp.PKL = append(resp.PKL, *toPKLResponse(&c.PKL[i]))
+	}
+	for i := range c.Ekstrakurikuler {
+		resp.Ekstrakurikuler = append(resp.Ekstrakurikuler, *toEkstrakurikulerResponse(&c.Ekstrakurikuler[i]))
+	}
+	for i := range c.PrestasiSemester {
+		resp.PrestasiSemester = append(resp.PrestasiSemester, *toPrestasiSemesterResponse(&c.PrestasiSemester[i]))
+	}
+	if c.Ketidakhadiran != nil {
+		resp.Ketidakhadiran = toKetidakhadiranResponse(c.Ketidakhadiran)
+	}
+
+	return resp
+}
+
+// toPKLResponse converts an internship record to DTO
+func toPKLResponse(pkl *models.PraktikKerjaLapangan) *responses.PKLResponse {
+	return &responses.PKLResponse{
+		ID:         pkl.ID,
+		NamaDUDI:   pkl.NamaDUDI,
+		Lokasi:     pkl.Lokasi,
+		LamaBulan:  pkl.LamaBulan,
+		Keterangan: pkl.Keterangan,
+	}
+}
+
+// toEkstrakurikulerResponse converts an extracurricular activity to DTO
+func toEkstrakurikulerResponse(e *models.Ekstrakurikuler) *responses.EkstrakurikulerResponse {
+	return &responses.EkstrakurikulerResponse{
+		ID:           e.ID,
+		NamaKegiatan: e.NamaKegiatan,
+		Keterangan:   e.Keterangan,
+	}
+}
+
+// toPrestasiSemesterResponse converts a semester achievement to DTO
+func toPrestasiSemesterResponse(p *models.PrestasiSemester) *responses.PrestasiSemesterResponse {
+	return &responses.PrestasiSemesterResponse{
+		ID:            p.ID,
+		JenisPrestasi: p.JenisPrestasi,
+		Keterangan:    p.Keterangan,
+	}
+}
+
+// toKetidakhadiranResponse converts absence notes to DTO
+func toKetidakhadiranResponse(k *models.KetidakhadiranCatatan) *responses.KetidakhadiranResponse {
+	return &responses.KetidakhadiranResponse{
+		ID:              k.ID,
+		KarenaSakit:     k.KarenaSakit,
+		DenganIzin:      k.DenganIzin,
+		TanpaKeterangan: k.TanpaKeterangan,
+	}
+}
@@ -41,13 +41,7 @@ func (s *NilaiService) GetNilaiSemesterPaginated(siswaID uint, filter requests.N
 		}
 
 		if n.MataPelajaran != nil {
-			resp.MataPelajaran = &responses.MataPelajaranResponse{
-				ID:          n.MataPelajaran.ID,
-				Kode:        n.MataPelajaran.Kode,
-				Nama:        n.MataPelajaran.Nama,
-				Kelompok:    n.MataPelajaran.Kelompok,
-				SubKelompok: n.MataPelajaran.SubKelompok,
-			}
+			resp.MataPelajaran = toMataPelajaranResponse(n.MataPelajaran)
 		}
 
 		result = append(result, resp)
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// NilaiSikapService handles attitude grade business logic
+type NilaiSikapService struct {
+	siswaRepo  *repositories.SiswaRepository
+	sikapRepo  *repositories.NilaiSikapRepository
+	rombelRepo *repositories.RombelRepository
+}
+
+// NewNilaiSikapService creates a new NilaiSikapService
+func NewNilaiSikapService(
+	siswaRepo *repositories.SiswaRepository,
+	sikapRepo *repositories.NilaiSikapRepository,
+	rombelRepo *repositories.RombelRepository,
+) *NilaiSikapService {
+	return &NilaiSikapService{
+		siswaRepo:  siswaRepo,
+		sikapRepo:  sikapRepo,
+		rombelRepo: rombelRepo,
+	}
+}
+
+// CreateOrUpdate creates or updates a student's attitude grade for a semester
+func (s *NilaiSikapService) CreateOrUpdate(siswaID uint, req requests.CreateNilaiSikapRequest) (*responses.NilaiSikapResponse, error) {
+	// Validate student exists
+	_, err := s.siswaRepo.FindByID(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	sikap := models.NilaiSikap{
+		SiswaID:            siswaID,
+		Kelas:              req.Kelas,
+		Semester:           req.Semester,
+		DeskripsiSpiritual: utils.SanitizeString(req.DeskripsiSpiritual),
+		DeskripsiSosial:    utils.SanitizeString(req.DeskripsiSosial),
+	}
+
+	if err := s.sikapRepo.Upsert([]models.NilaiSikap{sikap}); err != nil {
+		return nil, err
+	}
+
+	return s.GetBySemester(siswaID, req.Kelas, req.Semester)
+}
+
+// GetBySemester gets a student's attitude grade for a semester
+func (s *NilaiSikapService) GetBySemester(siswaID uint, kelas string, semester uint8) (*responses.NilaiSikapResponse, error) {
+	sikap, err := s.sikapRepo.FindBySiswaIDAndSemester(siswaID, kelas, semester)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attitude grade not found")
+		}
+		return nil, err
+	}
+	return s.toResponse(sikap), nil
+}
+
+// GetBySiswaID gets all attitude grades of a student
+func (s *NilaiSikapService) GetBySiswaID(siswaID uint) ([]responses.NilaiSikapResponse, error) {
+	sikapList, err := s.sikapRepo.FindBySiswaID(siswaID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.NilaiSikapResponse
+	for i := range sikapList {
+		result = append(result, *s.toResponse(&sikapList[i]))
+	}
+	return result, nil
+}
+
+// BulkCreateOrUpdate creates or updates attitude grades for members of a class group
+func (s *NilaiSikapService) BulkCreateOrUpdate(rombelID uint, req requests.BulkNilaiSikapRequest) ([]responses.NilaiSikapResponse, error) {
+	rombel, err := s.rombelRepo.FindByID(rombelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class group not found")
+		}
+		return nil, err
+	}
+
+	anggotaIDs, err := s.rombelRepo.FindSiswaIDsByRombelID(rombelID)
+	if err != nil {
+		return nil, err
+	}
+	anggota := make(map[uint]bool, len(anggotaIDs))
+	for _, id := range anggotaIDs {
+		anggota[id] = true
+	}
+
+	seen := make(map[uint]bool, len(req.Nilai))
+	var sikapList []models.NilaiSikap
+	for _, n := range req.Nilai {
+		if !anggota[n.SiswaID] {
+			return nil, fmt.Errorf("student %d is not a member of the class group", n.SiswaID)
+		}
+		if seen[n.SiswaID] {
+			return nil, fmt.Errorf("student %d is listed more than once", n.SiswaID)
+		}
+		seen[n.SiswaID] = true
+
+		sikapList = append(sikapList, models.NilaiSikap{
+			SiswaID:            n.SiswaID,
+			Kelas:              rombel.Tingkat,
+			Semester:           req.Semester,
+			DeskripsiSpiritual: utils.SanitizeString(n.DeskripsiSpiritual),
+			DeskripsiSosial:    utils.SanitizeString(n.DeskripsiSosial),
+		})
+	}
+
+	if err := s.sikapRepo.Upsert(sikapList); err != nil {
+		return nil, err
+	}
+
+	var siswaIDs []uint
+	for id := range seen {
+		siswaIDs = append(siswaIDs, id)
+	}
+	saved, err := s.sikapRepo.FindBySiswaIDsAndSemester(siswaIDs, rombel.Tingkat, req.Semester)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.NilaiSikapResponse
+	for i := range saved {
+		result = append(result, *s.toResponse(&saved[i]))
+	}
+	return result, nil
+}
+
+// toResponse converts to DTO
+func (s *NilaiSikapService) toResponse(n *models.NilaiSikap) *responses.NilaiSikapResponse {
+	return &responses.NilaiSikapResponse{
+		ID:                 n.ID,
+		SiswaID:            n.SiswaID,
+		Kelas:              n.Kelas,
+		Semester:           n.Semester,
+		DeskripsiSpiritual: n.DeskripsiSpiritual,
+		DeskripsiSosial:    n.DeskripsiSosial,
+	}
+}
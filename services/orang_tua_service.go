@@ -34,6 +34,34 @@ func (s *OrangTuaService) Create(siswaID uint, req requests.CreateOrangTuaReques
 		return nil, err
 	}
 
+	orangTua, err := newOrangTua(req)
+	if err != nil {
+		return nil, err
+	}
+	orangTua.SiswaID = siswaID
+
+	if err := s.orangTuaRepo.Create(orangTua); err != nil {
+		return nil, err
+	}
+
+	return &responses.OrangTuaResponse{
+		ID:                 orangTua.ID,
+		Tipe:               orangTua.Tipe,
+		Nama:               orangTua.Nama,
+		TempatLahir:        orangTua.TempatLahir,
+		TanggalLahir:       orangTua.TanggalLahir,
+		Kewarganegaraan:    orangTua.Kewarganegaraan,
+		PendidikanTerakhir: orangTua.PendidikanTerakhir,
+		Pekerjaan:          orangTua.Pekerjaan,
+		PenghasilanBulanan: &orangTua.PenghasilanBulanan,
+		Alamat:             orangTua.Alamat,
+		NoTelepon:          orangTua.NoTelepon,
+		MasihHidup:         orangTua.MasihHidup,
+	}, nil
+}
+
+// newOrangTua builds a parent from a create request
+func newOrangTua(req requests.CreateOrangTuaRequest) (*models.OrangTua, error) {
 	// Parse date
 	var tanggalLahir *time.Time
 	if req.TanggalLahir != "" {
@@ -44,8 +72,7 @@ func (s *OrangTuaService) Create(siswaID uint, req requests.CreateOrangTuaReques
 		tanggalLahir = &parsed
 	}
 
-	orangTua := &models.OrangTua{
-		SiswaID:            siswaID,
+	return &models.OrangTua{
 		Tipe:               req.Tipe,
 		Nama:               utils.SanitizeString(req.Nama),
 		TempatLahir:        utils.SanitizeString(req.TempatLahir),
@@ -57,25 +84,6 @@ func (s *OrangTuaService) Create(siswaID uint, req requests.CreateOrangTuaReques
 		Alamat:             utils.SanitizeString(req.Alamat),
 		NoTelepon:          utils.SanitizeString(req.NoTelepon),
 		MasihHidup:         req.MasihHidup,
-	}
-
-	if err := s.orangTuaRepo.Create(orangTua); err != nil {
-		return nil, err
-	}
-
-	return &responses.OrangTuaResponse{
-		ID:                 orangTua.ID,
-		Tipe:               orangTua.Tipe,
-		Nama:               orangTua.Nama,
-		TempatLahir:        orangTua.TempatLahir,
-		TanggalLahir:       orangTua.TanggalLahir,
-		Kewarganegaraan:    orangTua.Kewarganegaraan,
-		PendidikanTerakhir: orangTua.PendidikanTerakhir,
-		Pekerjaan:          orangTua.Pekerjaan,
-		PenghasilanBulanan: orangTua.PenghasilanBulanan,
-		Alamat:             orangTua.Alamat,
-		NoTelepon:          orangTua.NoTelepon,
-		MasihHidup:         orangTua.MasihHidup,
 	}, nil
 }
 
@@ -140,7 +148,7 @@ func (s *OrangTuaService) Update(id uint, req requests.UpdateOrangTuaRequest) (*
 		Kewarganegaraan:    orangTua.Kewarganegaraan,
 		PendidikanTerakhir: orangTua.PendidikanTerakhir,
 		Pekerjaan:          orangTua.Pekerjaan,
-		PenghasilanBulanan: orangTua.PenghasilanBulanan,
+		PenghasilanBulanan: &orangTua.PenghasilanBulanan,
 		Alamat:             orangTua.Alamat,
 		NoTelepon:          orangTua.NoTelepon,
 		MasihHidup:         orangTua.MasihHidup,
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kampunk/api-siswa/configs"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// PasswordService handles password changes and resets
+type PasswordService struct {
+	userRepo    *repositories.UserRepository
+	sessionRepo *repositories.SessionRepository
+	resetRepo   *repositories.PasswordResetRepository
+	mailer      utils.Mailer
+}
+
+// NewPasswordService creates a new PasswordService
+func NewPasswordService(
+	userRepo *repositories.UserRepository,
+	sessionRepo *repositories.SessionRepository,
+	resetRepo *repositories.PasswordResetRepository,
+	mailer utils.Mailer,
+) *PasswordService {
+	return &PasswordService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		resetRepo:   resetRepo,
+		mailer:      mailer,
+	}
+}
+
+// Change changes the password of the current user after checking the current
+// one. The user's other sessions are signed out; the current one stays.
+func (s *PasswordService) Change(userID, sessionID uint, req requests.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, user.PasswordHash) {
+		return errors.New("current password is incorrect")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword, false); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeOthers(user.ID, sessionID)
+}
+
+// Forgot emails a reset link to the user with the given email. Unknown and
+// deactivated accounts get no email but the same answer, so the endpoint does
+// not reveal which emails are registered. For the same reason a failed email
+// is only logged.
+func (s *PasswordService) Forgot(req requests.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return errors.New("failed to generate token")
+	}
+	cfg := configs.AppConfig.Mail
+	expiry := time.Duration(cfg.ResetExpiryMinutes) * time.Minute
+	if err := s.resetRepo.Create(&models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(expiry),
+	}); err != nil {
+		return err
+	}
+
+	link := cfg.ResetURL
+	if strings.Contains(link, "?") {
+		link += "&token=" + token
+	} else {
+		link += "?token=" + token
+	}
+	body := fmt.Sprintf("Halo %s,\n\n"+
+		"Kami menerima permintaan reset password untuk akun Anda. Buka tautan berikut untuk membuat password baru:\n\n"+
+		"%s\n\n"+
+		"Tautan ini berlaku %d menit dan hanya bisa dipakai sekali. Abaikan email ini bila Anda tidak meminta reset password.\n",
+		user.Username, link, cfg.ResetExpiryMinutes)
+	if err := s.mailer.Send(user.Email, "Reset Password API Siswa Induk", body); err != nil {
+		log.Printf("Failed to send reset email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// Reset sets a new password with an emailed reset token and signs the user
+// out of every session
+func (s *PasswordService) Reset(req requests.ResetPasswordRequest) error {
+	reset, err := s.resetRepo.FindValid(utils.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid or expired reset token")
+		}
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(reset.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid or expired reset token")
+		}
+		return err
+	}
+	if !user.IsActive {
+		return errors.New("account is deactivated")
+	}
+
+	used, err := s.resetRepo.MarkUsed(reset.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.New("invalid or expired reset token")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword, false); err != nil {
+		return err
+	}
+
+	_, err = s.sessionRepo.RevokeAllByUserID(user.ID)
+	return err
+}
+
+// AdminReset replaces a user's password with a temporary one that must be
+// changed at the next login, and signs the user out of every session
+func (s *PasswordService) AdminReset(userID uint) (*responses.TemporaryPasswordResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	password, err := utils.GenerateTemporaryPassword()
+	if err != nil {
+		return nil, errors.New("failed to generate password")
+	}
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword, true); err != nil {
+		return nil, err
+	}
+	if _, err := s.sessionRepo.RevokeAllByUserID(user.ID); err != nil {
+		return nil, err
+	}
+
+	return &responses.TemporaryPasswordResponse{TemporaryPassword: password}, nil
+}
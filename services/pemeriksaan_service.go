@@ -0,0 +1,177 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// PemeriksaanService handles book inspection business logic
+type PemeriksaanService struct {
+	pemeriksaanRepo *repositories.PemeriksaanRepository
+}
+
+// NewPemeriksaanService creates a new PemeriksaanService
+func NewPemeriksaanService(pemeriksaanRepo *repositories.PemeriksaanRepository) *PemeriksaanService {
+	return &PemeriksaanService{pemeriksaanRepo: pemeriksaanRepo}
+}
+
+// Create records a book inspection
+func (s *PemeriksaanService) Create(req requests.CreatePemeriksaanRequest) (*responses.PemeriksaanResponse, error) {
+	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
+	if err != nil {
+		return nil, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+
+	pemeriksaan := &models.PemeriksaanBuku{
+		Tanggal:       tanggal,
+		NamaPemeriksa: utils.SanitizeString(req.NamaPemeriksa),
+		Jabatan:       utils.SanitizeString(req.Jabatan),
+		Keterangan:    utils.SanitizeString(req.Keterangan),
+	}
+
+	if err := s.pemeriksaanRepo.Create(pemeriksaan); err != nil {
+		return nil, err
+	}
+
+	return toPemeriksaanResponse(pemeriksaan), nil
+}
+
+// FindAll gets book inspections with pagination
+func (s *PemeriksaanService) FindAll(req requests.PaginationRequest, filter requests.PemeriksaanFilterRequest) ([]responses.PemeriksaanResponse, utils.Pagination, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filterMap, err := pemeriksaanFilterMap(filter)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	pemeriksaanList, total, err := s.pemeriksaanRepo.FindAll(req.Page, req.PageSize, filterMap)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var response []responses.PemeriksaanResponse
+	for i := range pemeriksaanList {
+		response = append(response, *toPemeriksaanResponse(&pemeriksaanList[i]))
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+
+	return response, pagination, nil
+}
+
+// FindByID finds a book inspection by ID
+func (s *PemeriksaanService) FindByID(id uint) (*responses.PemeriksaanResponse, error) {
+	pemeriksaan, err := s.pemeriksaanRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("book inspection not found")
+		}
+		return nil, err
+	}
+	return toPemeriksaanResponse(pemeriksaan), nil
+}
+
+// Update updates a book inspection
+func (s *PemeriksaanService) Update(id uint, req requests.UpdatePemeriksaanRequest) (*responses.PemeriksaanResponse, error) {
+	pemeriksaan, err := s.pemeriksaanRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("book inspection not found")
+		}
+		return nil, err
+	}
+
+	pindahTahun := false
+	if req.Tanggal != "" {
+		tanggal, err := time.Parse("2006-01-02", req.Tanggal)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		pindahTahun = tanggal.Year() != pemeriksaan.Tanggal.Year()
+		pemeriksaan.Tanggal = tanggal
+	}
+	if req.NamaPemeriksa != "" {
+		pemeriksaan.NamaPemeriksa = utils.SanitizeString(req.NamaPemeriksa)
+	}
+	if req.Jabatan != "" {
+		pemeriksaan.Jabatan = utils.SanitizeString(req.Jabatan)
+	}
+	if req.Keterangan != "" {
+		pemeriksaan.Keterangan = utils.SanitizeString(req.Keterangan)
+	}
+
+	if err := s.pemeriksaanRepo.Update(pemeriksaan, pindahTahun); err != nil {
+		return nil, err
+	}
+
+	return toPemeriksaanResponse(pemeriksaan), nil
+}
+
+// Delete deletes a book inspection
+func (s *PemeriksaanService) Delete(id uint) error {
+	if _, err := s.pemeriksaanRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("book inspection not found")
+		}
+		return err
+	}
+	return s.pemeriksaanRepo.Delete(id)
+}
+
+// pemeriksaanFilterMap converts the filter request into repository filters
+func pemeriksaanFilterMap(filter requests.PemeriksaanFilterRequest) (map[string]interface{}, error) {
+	filterMap := make(map[string]interface{})
+	if filter.TanggalMulai != "" {
+		tanggal, err := time.Parse("2006-01-02", filter.TanggalMulai)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		filterMap["tanggal_mulai"] = tanggal
+	}
+	if filter.TanggalSelesai != "" {
+		tanggal, err := time.Parse("2006-01-02", filter.TanggalSelesai)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		filterMap["tanggal_selesai"] = tanggal
+	}
+	if filter.Tahun > 0 {
+		filterMap["tahun"] = filter.Tahun
+	}
+	return filterMap, nil
+}
+
+// toPemeriksaanResponse converts to DTO
+func toPemeriksaanResponse(p *models.PemeriksaanBuku) *responses.PemeriksaanResponse {
+	return &responses.PemeriksaanResponse{
+		ID:            p.ID,
+		NoUrut:        p.NoUrut,
+		Tanggal:       p.Tanggal,
+		NamaPemeriksa: p.NamaPemeriksa,
+		Jabatan:       p.Jabatan,
+		Keterangan:    p.Keterangan,
+	}
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/kampunk/api-siswa/configs"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// PeringkatService ranks students by their weighted semester average, within
+// their class group and within the whole tingkat
+type PeringkatService struct {
+	rombelRepo *repositories.RombelRepository
+	nilaiRepo  *repositories.NilaiSemesterRepository
+	tahunRepo  *repositories.TahunPelajaranRepository
+}
+
+// NewPeringkatService creates a new PeringkatService
+func NewPeringkatService(
+	rombelRepo *repositories.RombelRepository,
+	nilaiRepo *repositories.NilaiSemesterRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+) *PeringkatService {
+	return &PeringkatService{
+		rombelRepo: rombelRepo,
+		nilaiRepo:  nilaiRepo,
+		tahunRepo:  tahunRepo,
+	}
+}
+
+// hasilPeringkat is the ranking of one student in a semester. Ranks are 0 when
+// the student has no grades; jumlahKelas and jumlahTingkat count ranked students.
+type hasilPeringkat struct {
+	anggota          models.AnggotaRombel
+	rataRata         float64
+	jumlahMapel      int
+	peringkatKelas   int
+	jumlahKelas      int
+	peringkatTingkat int
+	jumlahTingkat    int
+}
+
+// bobotPeringkat returns the configured ranking weights. Without a positive
+// pengetahuan or keterampilan weight both count equally.
+func bobotPeringkat() configs.RankingConfig {
+	bobot := configs.AppConfig.Ranking
+	if bobot.WeightPengetahuan < 0 || bobot.WeightKeterampilan < 0 || bobot.WeightPengetahuan+bobot.WeightKeterampilan <= 0 {
+		bobot.WeightPengetahuan, bobot.WeightKeterampilan = 1, 1
+	}
+	if bobot.WeightKelompok == nil {
+		bobot.WeightKelompok = map[string]float64{"A": 1, "B": 1, "C": 1}
+	}
+	return bobot
+}
+
+// rataRataPeringkat computes the weighted average of a student's grades: the
+// subject score weighs pengetahuan against keterampilan, then subjects are
+// averaged with the weight of their group. Groups weighted 0 are left out.
+func rataRataPeringkat(nilai []models.NilaiSemester, bobot configs.RankingConfig) (float64, int) {
+	var total, jumlahBobot float64
+	jumlahMapel := 0
+	for _, n := range nilai {
+		if n.MataPelajaran == nil {
+			continue
+		}
+		w := bobot.WeightKelompok[n.MataPelajaran.Kelompok]
+		if w <= 0 {
+			continue
+		}
+		skor := (bobot.WeightPengetahuan*float64(n.NilaiPengetahuan) + bobot.WeightKeterampilan*float64(n.NilaiKeterampilan)) /
+			(bobot.WeightPengetahuan + bobot.WeightKeterampilan)
+		total += w * skor
+		jumlahBobot += w
+		jumlahMapel++
+	}
+	if jumlahBobot == 0 {
+		return 0, 0
+	}
+	return math.Round(total/jumlahBobot*100) / 100, jumlahMapel
+}
+
+// hitung ranks every student enrolled in a class group of a tingkat for a
+// semester of an academic year, keyed by student ID
+func (s *PeringkatService) hitung(tingkat string, semester uint8, tahunPelajaran string) (map[uint]*hasilPeringkat, error) {
+	anggota, err := s.rombelRepo.FindAnggotaByTahun(tahunPelajaran, 0)
+	if err != nil {
+		return nil, err
+	}
+	nilai, err := s.nilaiRepo.FindByTingkat(tingkat, semester, tahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+	nilaiSiswa := make(map[uint][]models.NilaiSemester)
+	for _, n := range nilai {
+		nilaiSiswa[n.SiswaID] = append(nilaiSiswa[n.SiswaID], n)
+	}
+
+	bobot := bobotPeringkat()
+	hasil := make(map[uint]*hasilPeringkat)
+	var tingkatRank []*hasilPeringkat
+	kelasRank := make(map[uint][]*hasilPeringkat)
+	for _, a := range anggota {
+		// Soft-deleted students are not preloaded
+		if a.Siswa == nil || a.Rombel == nil || a.Rombel.Tingkat != tingkat {
+			continue
+		}
+		h := &hasilPeringkat{anggota: a}
+		h.rataRata, h.jumlahMapel = rataRataPeringkat(nilaiSiswa[a.SiswaID], bobot)
+		hasil[a.SiswaID] = h
+		if h.jumlahMapel > 0 {
+			tingkatRank = append(tingkatRank, h)
+			kelasRank[a.RombelID] = append(kelasRank[a.RombelID], h)
+		}
+	}
+
+	for i, rank := range urutkanPeringkat(tingkatRank) {
+		tingkatRank[i].peringkatTingkat = rank
+		tingkatRank[i].jumlahTingkat = len(tingkatRank)
+	}
+	for _, kelas := range kelasRank {
+		for i, rank := range urutkanPeringkat(kelas) {
+			kelas[i].peringkatKelas = rank
+			kelas[i].jumlahKelas = len(kelas)
+		}
+	}
+
+	return hasil, nil
+}
+
+// urutkanPeringkat ranks by descending average. Equal averages share a rank and
+// the next rank is skipped (1, 2, 2, 4). It returns the rank of each entry.
+func urutkanPeringkat(hasil []*hasilPeringkat) []int {
+	urutan := make([]int, len(hasil))
+	for i := range urutan {
+		urutan[i] = i
+	}
+	sort.SliceStable(urutan, func(a, b int) bool {
+		return hasil[urutan[a]].rataRata > hasil[urutan[b]].rataRata
+	})
+
+	rank := make([]int, len(hasil))
+	for pos, i := range urutan {
+		if pos > 0 && hasil[i].rataRata == hasil[urutan[pos-1]].rataRata {
+			rank[i] = rank[urutan[pos-1]]
+		} else {
+			rank[i] = pos + 1
+		}
+	}
+	return rank
+}
+
+// Rombel ranks the members of a class group for a semester of its academic
+// year, with their rank in the whole tingkat
+func (s *PeringkatService) Rombel(rombelID uint, semester uint8) (*responses.PeringkatResponse, error) {
+	rombel, err := s.rombelRepo.FindByID(rombelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class group not found")
+		}
+		return nil, err
+	}
+
+	hasil, err := s.hitung(rombel.Tingkat, semester, rombel.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.toResponse(rombel.Tingkat, semester, rombel.TahunPelajaran, hasil, rombel.ID)
+	resp.RombelID = &rombel.ID
+	resp.Rombel = rombel.Nama
+	return resp, nil
+}
+
+// Tingkat ranks all students of a tingkat for a semester. Without an academic
+// year the active one is used.
+func (s *PeringkatService) Tingkat(tingkat string, semester uint8, tahunPelajaran string) (*responses.PeringkatResponse, error) {
+	tahunPelajaran = utils.SanitizeString(tahunPelajaran)
+	if tahunPelajaran == "" {
+		aktif, err := s.tahunRepo.FindActive()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("no active academic year, set tahun_pelajaran")
+			}
+			return nil, err
+		}
+		tahunPelajaran = aktif.Label
+	}
+
+	hasil, err := s.hitung(tingkat, semester, tahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(tingkat, semester, tahunPelajaran, hasil, 0), nil
+}
+
+// toResponse lists the ranked students first by rank, then the others by name,
+// optionally limited to one class group
+func (s *PeringkatService) toResponse(tingkat string, semester uint8, tahunPelajaran string, hasil map[uint]*hasilPeringkat, rombelID uint) *responses.PeringkatResponse {
+	bobot := bobotPeringkat()
+	resp := &responses.PeringkatResponse{
+		Tingkat:        tingkat,
+		Semester:       semester,
+		TahunPelajaran: tahunPelajaran,
+		Bobot: responses.BobotPeringkatResponse{
+			Pengetahuan:  bobot.WeightPengetahuan,
+			Keterampilan: bobot.WeightKeterampilan,
+			Kelompok:     bobot.WeightKelompok,
+		},
+		Siswa: []responses.PeringkatSiswaResponse{},
+	}
+
+	for _, h := range hasil {
+		if rombelID > 0 && h.anggota.RombelID != rombelID {
+			continue
+		}
+		resp.Siswa = append(resp.Siswa, responses.PeringkatSiswaResponse{
+			SiswaID:          h.anggota.SiswaID,
+			NoInduk:          h.anggota.Siswa.NoInduk,
+			NISN:             h.anggota.Siswa.NISN,
+			NamaLengkap:      h.anggota.Siswa.NamaLengkap,
+			RombelID:         h.anggota.RombelID,
+			Rombel:           h.anggota.Rombel.Nama,
+			JumlahMapel:      h.jumlahMapel,
+			RataRata:         h.rataRata,
+			PeringkatKelas:   h.peringkatKelas,
+			JumlahKelas:      h.jumlahKelas,
+			PeringkatTingkat: h.peringkatTingkat,
+			JumlahTingkat:    h.jumlahTingkat,
+		})
+	}
+
+	peringkat := func(p responses.PeringkatSiswaResponse) int {
+		if rombelID > 0 {
+			return p.PeringkatKelas
+		}
+		return p.PeringkatTingkat
+	}
+	sort.Slice(resp.Siswa, func(i, j int) bool {
+		a, b := peringkat(resp.Siswa[i]), peringkat(resp.Siswa[j])
+		if (a == 0) != (b == 0) {
+			return a != 0
+		}
+		if a != b {
+			return a < b
+		}
+		return resp.Siswa[i].NamaLengkap < resp.Siswa[j].NamaLengkap
+	})
+
+	return resp
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// PortalService handles the portal accounts of students and parents and the
+// data they may read
+type PortalService struct {
+	userRepo     *repositories.UserRepository
+	siswaRepo    *repositories.SiswaRepository
+	orangTuaRepo *repositories.OrangTuaRepository
+	waliRepo     *repositories.WaliRepository
+	siswaService *SiswaService
+}
+
+// NewPortalService creates a new PortalService
+func NewPortalService(
+	userRepo *repositories.UserRepository,
+	siswaRepo *repositories.SiswaRepository,
+	orangTuaRepo *repositories.OrangTuaRepository,
+	waliRepo *repositories.WaliRepository,
+	siswaService *SiswaService,
+) *PortalService {
+	return &PortalService{
+		userRepo:     userRepo,
+		siswaRepo:    siswaRepo,
+		orangTuaRepo: orangTuaRepo,
+		waliRepo:     waliRepo,
+		siswaService: siswaService,
+	}
+}
+
+// CreateAccount creates a student or parent portal account linked to its students
+func (s *PortalService) CreateAccount(req requests.CreatePortalAccountRequest) (*responses.UserResponse, error) {
+	links, err := s.buildLinks(req.Tipe, req.SetPortalLinksRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := newUser(s.userRepo, req.Username, req.Email, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	user.Roles = []models.UserRole{{Role: req.Tipe}}
+	user.PortalLinks = links
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+// SetLinks replaces the students a portal account is linked to
+func (s *PortalService) SetLinks(userID uint, req requests.SetPortalLinksRequest) (*responses.UserResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	tipe := ""
+	for _, role := range roleNames(user) {
+		if utils.IsPortalRole(role) {
+			tipe = role
+		}
+	}
+	if tipe == "" {
+		return nil, errors.New("user is not a portal account")
+	}
+
+	links, err := s.buildLinks(tipe, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetPortalLinks(user.ID, links); err != nil {
+		return nil, err
+	}
+
+	user, err = s.userRepo.FindByID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserResponse(user), nil
+}
+
+// buildLinks validates the students of a portal account. A student account is
+// linked to itself by siswa_id; a parent account to each child through the
+// child's orang tua or wali record.
+func (s *PortalService) buildLinks(tipe string, req requests.SetPortalLinksRequest) ([]models.PortalLink, error) {
+	if tipe == models.RoleSiswa {
+		if req.SiswaID == 0 || len(req.OrangTuaIDs) > 0 || len(req.WaliIDs) > 0 {
+			return nil, errors.New("a student account is linked by siswa_id only")
+		}
+		if _, err := s.siswaRepo.FindByID(req.SiswaID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("student not found")
+			}
+			return nil, err
+		}
+		return []models.PortalLink{{SiswaID: req.SiswaID}}, nil
+	}
+
+	if req.SiswaID != 0 {
+		return nil, errors.New("a parent account is linked by orang_tua_ids or wali_ids, not siswa_id")
+	}
+	if len(req.OrangTuaIDs) == 0 && len(req.WaliIDs) == 0 {
+		return nil, errors.New("orang_tua_ids or wali_ids is required")
+	}
+
+	var links []models.PortalLink
+	linked := make(map[uint]bool)
+	add := func(link models.PortalLink) error {
+		if linked[link.SiswaID] {
+			return fmt.Errorf("student %d is linked more than once", link.SiswaID)
+		}
+		linked[link.SiswaID] = true
+		links = append(links, link)
+		return nil
+	}
+
+	for _, id := range req.OrangTuaIDs {
+		orangTua, err := s.orangTuaRepo.FindByID(id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("parent %d not found", id)
+			}
+			return nil, err
+		}
+		if err := add(models.PortalLink{SiswaID: orangTua.SiswaID, OrangTuaID: &orangTua.ID}); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range req.WaliIDs {
+		wali, err := s.waliRepo.FindByID(id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("guardian %d not found", id)
+			}
+			return nil, err
+		}
+		if err := add(models.PortalLink{SiswaID: wali.SiswaID, WaliID: &wali.ID}); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+// Siswa lists the students linked to a portal account
+func (s *PortalService) Siswa(userID uint) ([]responses.PortalSiswaResponse, error) {
+	links, err := s.userRepo.FindPortalLinks(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []responses.PortalSiswaResponse{}
+	for _, link := range links {
+		// Soft-deleted students are not preloaded
+		if link.Siswa == nil {
+			continue
+		}
+		hubungan := models.RoleSiswa
+		switch {
+		case link.OrangTua != nil:
+			hubungan = link.OrangTua.Tipe
+		case link.Wali != nil:
+			hubungan = "wali"
+		}
+		result = append(result, responses.PortalSiswaResponse{
+			ID:          link.Siswa.ID,
+			NoInduk:     link.Siswa.NoInduk,
+			NISN:        link.Siswa.NISN,
+			NamaLengkap: link.Siswa.NamaLengkap,
+			FotoPath:    link.Siswa.FotoPath,
+			Hubungan:    hubungan,
+		})
+	}
+	return result, nil
+}
+
+// Profil returns the profile of a linked student. Student accounts do not see
+// the monthly income of their parents and guardian.
+func (s *PortalService) Profil(siswaID uint, roles []string) (*responses.SiswaDetailResponse, error) {
+	resp, err := s.siswaService.FindByID(siswaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasRole(roles, models.RoleSiswa) {
+		for i := range resp.OrangTua {
+			resp.OrangTua[i].PenghasilanBulanan = nil
+		}
+		if resp.Wali != nil {
+			resp.Wali.PenghasilanBulanan = nil
+		}
+	}
+	return resp, nil
+}
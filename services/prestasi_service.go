@@ -48,6 +48,86 @@ func (s *PrestasiService) Add(siswaID uint, req requests.CreatePrestasiRequest)
 	return s.toResponse(prestasi), nil
 }
 
+// FindAll gets achievement records with pagination, optionally limited to one student
+func (s *PrestasiService) FindAll(siswaID uint, req requests.PaginationRequest, filter requests.PrestasiFilterRequest) ([]responses.PrestasiResponse, utils.Pagination, error) {
+	if siswaID > 0 {
+		if _, err := s.siswaRepo.FindByID(siswaID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, utils.Pagination{}, errors.New("student not found")
+			}
+			return nil, utils.Pagination{}, err
+		}
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filterMap := map[string]interface{}{
+		"siswa_id": siswaID,
+		"bidang":   filter.Bidang,
+		"tingkat":  filter.Tingkat,
+		"tahun":    filter.Tahun,
+	}
+
+	prestasiList, total, err := s.prestasiRepo.FindAll(req.Page, req.PageSize, filterMap)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var response []responses.PrestasiResponse
+	for i := range prestasiList {
+		response = append(response, *s.toResponse(&prestasiList[i]))
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+
+	return response, pagination, nil
+}
+
+// Update updates achievement record
+func (s *PrestasiService) Update(id uint, req requests.UpdatePrestasiRequest) (*responses.PrestasiResponse, error) {
+	prestasi, err := s.prestasiRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("achievement not found")
+		}
+		return nil, err
+	}
+
+	if req.Bidang != "" {
+		prestasi.Bidang = req.Bidang
+	}
+	if req.Keterangan != "" {
+		prestasi.Keterangan = utils.SanitizeString(req.Keterangan)
+	}
+	if req.Tahun > 0 {
+		prestasi.Tahun = req.Tahun
+	}
+	if req.Tingkat != "" {
+		prestasi.Tingkat = req.Tingkat
+	}
+
+	if err := s.prestasiRepo.Update(prestasi); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(prestasi), nil
+}
+
 // Delete deletes achievement record
 func (s *PrestasiService) Delete(id uint) error {
 	_, err := s.prestasiRepo.FindByID(id)
@@ -69,5 +149,6 @@ func (s *PrestasiService) toResponse(p *models.Prestasi) *responses.PrestasiResp
 		Keterangan: p.Keterangan,
 		Tahun:      p.Tahun,
 		Tingkat:    p.Tingkat,
+		Siswa:      toSiswaRingkasResponse(p.Siswa),
 	}
 }
@@ -0,0 +1,526 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kampunk/api-siswa/configs"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// RaporService renders report cards (rapor) as PDF
+type RaporService struct {
+	siswaRepo        *repositories.SiswaRepository
+	nilaiRepo        *repositories.NilaiSemesterRepository
+	sikapRepo        *repositories.NilaiSikapRepository
+	catatanRepo      *repositories.CatatanRepository
+	kehadiranRepo    *repositories.KehadiranRepository
+	rombelRepo       *repositories.RombelRepository
+	jurusanRepo      *repositories.JurusanRepository
+	kurikulumRepo    *repositories.KurikulumRepository
+	skemaRepo        *repositories.SkemaPenilaianRepository
+	peringkatService *PeringkatService
+}
+
+// NewRaporService creates a new RaporService
+func NewRaporService(
+	siswaRepo *repositories.SiswaRepository,
+	nilaiRepo *repositories.NilaiSemesterRepository,
+	sikapRepo *repositories.NilaiSikapRepository,
+	catatanRepo *repositories.CatatanRepository,
+	kehadiranRepo *repositories.KehadiranRepository,
+	rombelRepo *repositories.RombelRepository,
+	jurusanRepo *repositories.JurusanRepository,
+	kurikulumRepo *repositories.KurikulumRepository,
+	skemaRepo *repositories.SkemaPenilaianRepository,
+	peringkatService *PeringkatService,
+) *RaporService {
+	return &RaporService{
+		siswaRepo:        siswaRepo,
+		nilaiRepo:        nilaiRepo,
+		sikapRepo:        sikapRepo,
+		catatanRepo:      catatanRepo,
+		kehadiranRepo:    kehadiranRepo,
+		rombelRepo:       rombelRepo,
+		jurusanRepo:      jurusanRepo,
+		kurikulumRepo:    kurikulumRepo,
+		skemaRepo:        skemaRepo,
+		peringkatService: peringkatService,
+	}
+}
+
+// namaKelompok holds the K13 SMK labels of the subject groups
+var namaKelompok = map[string]string{
+	"A": "A. Muatan Nasional",
+	"B": "B. Muatan Kewilayahan",
+	"C": "C. Muatan Peminatan Kejuruan",
+}
+
+// GenerateSiswa renders the report card of one student for a kelas and semester.
+// Without tahun_pelajaran, the latest academic year with grades for that kelas is used.
+func (s *RaporService) GenerateSiswa(siswaID uint, req requests.RaporRequest) ([]byte, string, error) {
+	siswa, err := s.siswaRepo.FindByID(siswaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("student not found")
+		}
+		return nil, "", err
+	}
+
+	tahunPelajaran := utils.SanitizeString(req.TahunPelajaran)
+	nilai, err := s.nilaiRepo.FindBySiswaIDFiltered(siswaID, req.Kelas, req.Semester, tahunPelajaran)
+	if err != nil {
+		return nil, "", err
+	}
+	if tahunPelajaran == "" {
+		if len(nilai) == 0 {
+			return nil, "", fmt.Errorf("no grades found for kelas %s semester %d", req.Kelas, req.Semester)
+		}
+		// A student who repeated the kelas has grades in more than one year
+		for _, n := range nilai {
+			if n.TahunPelajaran > tahunPelajaran {
+				tahunPelajaran = n.TahunPelajaran
+			}
+		}
+		nilai = filterNilaiTahun(nilai, tahunPelajaran)
+	}
+
+	peringkat, err := s.peringkatService.hitung(req.Kelas, req.Semester, tahunPelajaran)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pdf := utils.NewPDF()
+	if err := s.render(pdf, siswa, req.Kelas, req.Semester, tahunPelajaran, nilai, peringkat[siswa.ID]); err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("rapor-%s-%s-%d.pdf", siswa.NoInduk, req.Kelas, req.Semester)
+	return pdf.Bytes(), sanitizeFilename(filename), nil
+}
+
+// GenerateRombel renders the report cards of all members of a class group into one PDF
+func (s *RaporService) GenerateRombel(rombelID uint, req requests.RaporRombelRequest) ([]byte, string, error) {
+	rombel, err := s.rombelRepo.FindByID(rombelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("class group not found")
+		}
+		return nil, "", err
+	}
+
+	anggota, err := s.rombelRepo.FindAnggotaByTahun(rombel.TahunPelajaran, rombel.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Slice(anggota, func(i, j int) bool {
+		if anggota[i].Siswa == nil || anggota[j].Siswa == nil {
+			return anggota[j].Siswa == nil && anggota[i].Siswa != nil
+		}
+		return anggota[i].Siswa.NamaLengkap < anggota[j].Siswa.NamaLengkap
+	})
+
+	peringkat, err := s.peringkatService.hitung(rombel.Tingkat, req.Semester, rombel.TahunPelajaran)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pdf := utils.NewPDF()
+	jumlah := 0
+	for _, a := range anggota {
+		// Soft-deleted students are not preloaded
+		if a.Siswa == nil {
+			continue
+		}
+
+		nilai, err := s.nilaiRepo.FindBySiswaIDFiltered(a.SiswaID, rombel.Tingkat, req.Semester, rombel.TahunPelajaran)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := s.render(pdf, a.Siswa, rombel.Tingkat, req.Semester, rombel.TahunPelajaran, nilai, peringkat[a.SiswaID]); err != nil {
+			return nil, "", err
+		}
+		jumlah++
+	}
+	if jumlah == 0 {
+		return nil, "", errors.New("class group has no students")
+	}
+
+	filename := fmt.Sprintf("rapor-%s-%s-%d.pdf", rombel.Nama, rombel.TahunPelajaran, req.Semester)
+	return pdf.Bytes(), sanitizeFilename(filename), nil
+}
+
+// render appends the report card of one student, starting on a new page.
+// The rank is printed below the grades when the student is ranked.
+func (s *RaporService) render(pdf *utils.PDF, siswa *models.Siswa, kelas string, semester uint8, tahunPelajaran string, nilai []models.NilaiSemester, peringkat *hasilPeringkat) error {
+	namaRombel := kelas
+	waliKelas := ""
+	if anggota, err := s.rombelRepo.FindAnggotaBySiswaAndTahun(siswa.ID, tahunPelajaran); err == nil {
+		rombel, err := s.rombelRepo.FindByID(anggota.RombelID)
+		if err != nil {
+			return err
+		}
+		namaRombel = rombel.Nama
+		if rombel.WaliKelas != nil {
+			waliKelas = rombel.WaliKelas.Username
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var kompetensi *models.KompetensiKeahlian
+	if siswa.KompetensiKeahlianID != nil {
+		k, err := s.jurusanRepo.FindKompetensiByID(*siswa.KompetensiKeahlianID)
+		if err != nil {
+			return err
+		}
+		kompetensi = k
+	}
+
+	sikap, err := s.sikapRepo.FindBySiswaIDAndSemester(siswa.ID, kelas, semester)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	catatan, err := s.catatanRepo.FindBySiswaKelasSemester(siswa.ID, kelas, semester)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err := s.urutkanNilai(nilai, kompetensi, kelas, semester); err != nil {
+		return err
+	}
+
+	// Stored text is HTML-escaped; the PDF prints it as is
+	utils.UnescapeStrings(siswa, &namaRombel, &waliKelas, kompetensi, sikap, catatan, nilai)
+
+	school := configs.AppConfig.School
+	pdf.AddPage()
+	kopLaporan(pdf, "LAPORAN HASIL BELAJAR PESERTA DIDIK")
+
+	// Identity
+	jurusan, program := "-", "-"
+	if kompetensi != nil {
+		jurusan = kompetensi.Nama
+		if kompetensi.ProgramKeahlian != nil {
+			program = kompetensi.ProgramKeahlian.Nama
+		}
+	}
+	identitas := []float64{105, 165, 95, 150}
+	pdf.SetFont(false, 9)
+	pdf.Row(identitas, []string{"Nama Peserta Didik", ": " + siswa.NamaLengkap, "Kelas", ": " + namaRombel}, utils.PDFRowStyle{})
+	pdf.Row(identitas, []string{"NIS / NISN", ": " + siswa.NoInduk + " / " + siswa.NISN, "Semester", fmt.Sprintf(": %d (%s)", semester, namaSemester(semester))}, utils.PDFRowStyle{})
+	pdf.Row(identitas, []string{"Nama Sekolah", ": " + school.Name, "Tahun Pelajaran", ": " + tahunPelajaran}, utils.PDFRowStyle{})
+	pdf.Row(identitas, []string{"Program Keahlian", ": " + program, "Kompetensi Keahlian", ": " + jurusan}, utils.PDFRowStyle{})
+	pdf.Ln(8)
+
+	// A. Sikap
+	judulBagian(pdf, "A. Sikap")
+	spiritual, sosial := "-", "-"
+	if sikap != nil {
+		spiritual = teksAtauStrip(sikap.DeskripsiSpiritual)
+		sosial = teksAtauStrip(sikap.DeskripsiSosial)
+	}
+	pdf.SetFont(false, 9)
+	pdf.Row([]float64{100, 415}, []string{"Sikap Spiritual", spiritual}, utils.PDFRowStyle{Border: true})
+	pdf.Row([]float64{100, 415}, []string{"Sikap Sosial", sosial}, utils.PDFRowStyle{Border: true})
+	pdf.Ln(8)
+
+	// B. Pengetahuan & Keterampilan, grouped by kelompok and sub kelompok
+	judulBagian(pdf, "B. Pengetahuan dan Keterampilan")
+	kolom := []float64{22, 140, 30, 38, 32, 38, 32, 183}
+	tengah := []utils.PDFAlign{utils.AlignCenter, utils.AlignLeft, utils.AlignCenter, utils.AlignCenter, utils.AlignCenter, utils.AlignCenter, utils.AlignCenter, utils.AlignLeft}
+	pdf.SetFont(true, 8)
+	pdf.Row(kolom, []string{"No", "Mata Pelajaran", "KKM", "Nilai\nPeng.", "Pred.", "Nilai\nKetr.", "Pred.", "Deskripsi"},
+		utils.PDFRowStyle{Border: true, Fill: true, Aligns: tengah})
+
+	kkm := make(map[uint]string)
+	kelompok, subKelompok := "", ""
+	no := 0
+	for _, n := range nilai {
+		if n.MataPelajaran == nil {
+			continue
+		}
+		if n.MataPelajaran.Kelompok != kelompok {
+			kelompok, subKelompok = n.MataPelajaran.Kelompok, ""
+			label, ok := namaKelompok[kelompok]
+			if !ok {
+				label = "Kelompok " + kelompok
+			}
+			pdf.SetFont(true, 8)
+			pdf.Row([]float64{pdf.ContentWidth()}, []string{label}, utils.PDFRowStyle{Border: true})
+		}
+		if n.MataPelajaran.SubKelompok != "" && n.MataPelajaran.SubKelompok != subKelompok {
+			subKelompok = n.MataPelajaran.SubKelompok
+			pdf.SetFont(true, 8)
+			pdf.Row([]float64{pdf.ContentWidth()}, []string{"    " + subKelompok}, utils.PDFRowStyle{Border: true})
+		}
+
+		if _, ok := kkm[n.MataPelajaranID]; !ok {
+			kkm[n.MataPelajaranID] = "-"
+			skema, err := s.skemaRepo.FindForMataPelajaran(tahunPelajaran, n.MataPelajaranID)
+			if err == nil {
+				kkm[n.MataPelajaranID] = strconv.FormatUint(uint64(skema.KKM), 10)
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		deskripsi := ""
+		if n.DeskripsiPengetahuan != "" {
+			deskripsi = "Pengetahuan: " + n.DeskripsiPengetahuan
+		}
+		if n.DeskripsiKeterampilan != "" {
+			if deskripsi != "" {
+				deskripsi += "\n"
+			}
+			deskripsi += "Keterampilan: " + n.DeskripsiKeterampilan
+		}
+
+		no++
+		pdf.SetFont(false, 8)
+		pdf.Row(kolom, []string{
+			strconv.Itoa(no),
+			n.MataPelajaran.Nama,
+			kkm[n.MataPelajaranID],
+			strconv.FormatUint(uint64(n.NilaiPengetahuan), 10),
+			n.PredikatPengetahuan,
+			strconv.FormatUint(uint64(n.NilaiKeterampilan), 10),
+			n.PredikatKeterampilan,
+			deskripsi,
+		}, utils.PDFRowStyle{Border: true, Aligns: tengah})
+	}
+	if no == 0 {
+		pdf.SetFont(false, 8)
+		pdf.Row([]float64{pdf.ContentWidth()}, []string{"Belum ada nilai"}, utils.PDFRowStyle{Border: true, Aligns: []utils.PDFAlign{utils.AlignCenter}})
+	}
+	if peringkat != nil && peringkat.peringkatKelas > 0 {
+		pdf.SetFont(false, 9)
+		pdf.Row([]float64{150, 150}, []string{"Nilai Rata-rata", strconv.FormatFloat(peringkat.rataRata, 'f', 2, 64)}, utils.PDFRowStyle{Border: true})
+		pdf.Row([]float64{150, 150}, []string{"Peringkat Kelas", fmt.Sprintf("%d dari %d siswa", peringkat.peringkatKelas, peringkat.jumlahKelas)}, utils.PDFRowStyle{Border: true})
+		pdf.Row([]float64{150, 150}, []string{"Peringkat Tingkat " + kelas, fmt.Sprintf("%d dari %d siswa", peringkat.peringkatTingkat, peringkat.jumlahTingkat)}, utils.PDFRowStyle{Border: true})
+	}
+	pdf.Ln(8)
+
+	// C-E. Catatan akhir semester
+	var pklRows, ekskulRows, prestasiRows [][]string
+	if catatan != nil {
+		for i, p := range catatan.PKL {
+			lama := "-"
+			if p.LamaBulan > 0 {
+				lama = fmt.Sprintf("%d bulan", p.LamaBulan)
+			}
+			pklRows = append(pklRows, []string{strconv.Itoa(i + 1), p.NamaDUDI, teksAtauStrip(p.Lokasi), lama, teksAtauStrip(p.Keterangan)})
+		}
+		for i, e := range catatan.Ekstrakurikuler {
+			ekskulRows = append(ekskulRows, []string{strconv.Itoa(i + 1), e.NamaKegiatan, teksAtauStrip(e.Keterangan)})
+		}
+		for i, p := range catatan.PrestasiSemester {
+			prestasiRows = append(prestasiRows, []string{strconv.Itoa(i + 1), p.JenisPrestasi, teksAtauStrip(p.Keterangan)})
+		}
+	}
+	tabelCatatan(pdf, "C. Praktik Kerja Lapangan", []float64{22, 150, 120, 60, 163},
+		[]string{"No", "Mitra DU/DI", "Lokasi", "Lama", "Keterangan"}, pklRows)
+	tabelCatatan(pdf, "D. Ekstrakurikuler", []float64{22, 200, 293},
+		[]string{"No", "Kegiatan Ekstrakurikuler", "Keterangan"}, ekskulRows)
+	tabelCatatan(pdf, "E. Prestasi", []float64{22, 200, 293},
+		[]string{"No", "Jenis Prestasi", "Keterangan"}, prestasiRows)
+
+	// F. Ketidakhadiran, falling back to the attendance summary
+	var sakit, izin, alpa uint
+	if catatan != nil && catatan.Ketidakhadiran != nil {
+		sakit, izin, alpa = catatan.Ketidakhadiran.KarenaSakit, catatan.Ketidakhadiran.DenganIzin, catatan.Ketidakhadiran.TanpaKeterangan
+	} else if kehadiran, err := s.kehadiranRepo.FindBySiswaIDAndKelas(siswa.ID, kelas, semester); err == nil {
+		sakit, izin, alpa = kehadiran.JumlahSakit, kehadiran.JumlahIzin, kehadiran.JumlahAlpa
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	judulBagian(pdf, "F. Ketidakhadiran")
+	pdf.SetFont(false, 9)
+	pdf.Row([]float64{150, 80}, []string{"Sakit", fmt.Sprintf("%d hari", sakit)}, utils.PDFRowStyle{Border: true})
+	pdf.Row([]float64{150, 80}, []string{"Izin", fmt.Sprintf("%d hari", izin)}, utils.PDFRowStyle{Border: true})
+	pdf.Row([]float64{150, 80}, []string{"Tanpa Keterangan", fmt.Sprintf("%d hari", alpa)}, utils.PDFRowStyle{Border: true})
+	pdf.Ln(16)
+
+	// Signatures
+	tandaTangan(pdf, []string{"Orang Tua/Wali", "Wali Kelas", "Kepala Sekolah"},
+		[]string{"", waliKelas, school.Principal}, []string{"", "", school.PrincipalNIP})
+
+	return nil
+}
+
+// urutkanNilai orders grades for printing: by kelompok and sub kelompok, then by
+// the curriculum order of the student's kompetensi keahlian, then by subject code
+func (s *RaporService) urutkanNilai(nilai []models.NilaiSemester, kompetensi *models.KompetensiKeahlian, kelas string, semester uint8) error {
+	urutan := make(map[uint]int)
+	if kompetensi != nil {
+		ids, err := s.kurikulumRepo.FindMataPelajaranIDs(kompetensi.ID, kelas, semester)
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			urutan[id] = i + 1
+		}
+	}
+
+	rank := func(id uint) int {
+		if r, ok := urutan[id]; ok {
+			return r
+		}
+		return len(urutan) + 1
+	}
+	sort.SliceStable(nilai, func(i, j int) bool {
+		a, b := nilai[i].MataPelajaran, nilai[j].MataPelajaran
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		if a.Kelompok != b.Kelompok {
+			return a.Kelompok < b.Kelompok
+		}
+		if a.SubKelompok != b.SubKelompok {
+			return a.SubKelompok < b.SubKelompok
+		}
+		if rank(a.ID) != rank(b.ID) {
+			return rank(a.ID) < rank(b.ID)
+		}
+		return a.Kode < b.Kode
+	})
+	return nil
+}
+
+// filterNilaiTahun keeps the grades of one academic year
+func filterNilaiTahun(nilai []models.NilaiSemester, tahunPelajaran string) []models.NilaiSemester {
+	var result []models.NilaiSemester
+	for _, n := range nilai {
+		if n.TahunPelajaran == tahunPelajaran {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// kopLaporan writes the school letterhead and the document title
+func kopLaporan(pdf *utils.PDF, judul string) {
+	school := configs.AppConfig.School
+	pdf.SetFont(true, 14)
+	pdf.Paragraph(school.Name, utils.AlignCenter)
+	pdf.SetFont(false, 9)
+	alamat := school.Address
+	if school.NPSN != "" {
+		alamat += " - NPSN " + school.NPSN
+	}
+	pdf.Paragraph(alamat, utils.AlignCenter)
+	pdf.Ln(4)
+	pdf.HLine()
+	pdf.Ln(8)
+	pdf.SetFont(true, 12)
+	pdf.Paragraph(judul, utils.AlignCenter)
+	pdf.Ln(8)
+}
+
+// judulBagian writes a section heading
+func judulBagian(pdf *utils.PDF, judul string) {
+	pdf.EnsureSpace(40)
+	pdf.SetFont(true, 10)
+	pdf.Paragraph(judul, utils.AlignLeft)
+	pdf.Ln(2)
+}
+
+// tabelCatatan writes a titled table, or a single dash row when it has no rows
+func tabelCatatan(pdf *utils.PDF, judul string, kolom []float64, header []string, rows [][]string) {
+	judulBagian(pdf, judul)
+	pdf.SetFont(true, 8)
+	pdf.Row(kolom, header, utils.PDFRowStyle{Border: true, Fill: true})
+	pdf.SetFont(false, 8)
+	if len(rows) == 0 {
+		kosong := make([]string, len(kolom))
+		for i := range kosong {
+			kosong[i] = "-"
+		}
+		rows = [][]string{kosong}
+	}
+	for _, row := range rows {
+		pdf.Row(kolom, row, utils.PDFRowStyle{Border: true})
+	}
+	pdf.Ln(8)
+}
+
+// tandaTangan writes a signature block with one column per signer, dated today
+func tandaTangan(pdf *utils.PDF, jabatan, nama, nip []string) {
+	kolom := make([]float64, len(jabatan))
+	tengah := make([]utils.PDFAlign, len(jabatan))
+	for i := range kolom {
+		kolom[i] = pdf.ContentWidth() / float64(len(jabatan))
+		tengah[i] = utils.AlignCenter
+	}
+
+	pdf.EnsureSpace(110)
+	tanggal := make([]string, len(jabatan))
+	tanggal[len(tanggal)-1] = configs.AppConfig.School.City + ", " + tanggalIndonesia(time.Now())
+	pdf.SetFont(false, 9)
+	pdf.Row(kolom, tanggal, utils.PDFRowStyle{Aligns: tengah})
+	pdf.Row(kolom, jabatan, utils.PDFRowStyle{Aligns: tengah})
+	pdf.Ln(45)
+
+	baris := make([]string, len(jabatan))
+	for i := range baris {
+		baris[i] = "(" + teksAtauGaris(nama[i]) + ")"
+	}
+	pdf.SetFont(true, 9)
+	pdf.Row(kolom, baris, utils.PDFRowStyle{Aligns: tengah})
+	for i := range baris {
+		baris[i] = ""
+		if nip[i] != "" {
+			baris[i] = "NIP. " + nip[i]
+		}
+	}
+	pdf.SetFont(false, 9)
+	pdf.Row(kolom, baris, utils.PDFRowStyle{Aligns: tengah})
+}
+
+// tanggalIndonesia formats a date as "2 Januari 2006"
+func tanggalIndonesia(t time.Time) string {
+	bulan := [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli",
+		"Agustus", "September", "Oktober", "November", "Desember"}
+	return fmt.Sprintf("%d %s %d", t.Day(), bulan[t.Month()-1], t.Year())
+}
+
+func namaSemester(semester uint8) string {
+	if semester == 1 {
+		return "Ganjil"
+	}
+	return "Genap"
+}
+
+func teksAtauStrip(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func teksAtauGaris(s string) string {
+	if s == "" {
+		return "...................................."
+	}
+	return s
+}
+
+// sanitizeFilename replaces characters that are not safe in a download file name
+func sanitizeFilename(name string) string {
+	out := []rune(name)
+	for i, r := range out {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+		default:
+			out[i] = '-'
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// RombelService handles class group business logic
+type RombelService struct {
+	rombelRepo *repositories.RombelRepository
+	siswaRepo  *repositories.SiswaRepository
+	userRepo   *repositories.UserRepository
+	tahunRepo  *repositories.TahunPelajaranRepository
+}
+
+// NewRombelService creates a new RombelService
+func NewRombelService(
+	rombelRepo *repositories.RombelRepository,
+	siswaRepo *repositories.SiswaRepository,
+	userRepo *repositories.UserRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+) *RombelService {
+	return &RombelService{
+		rombelRepo: rombelRepo,
+		siswaRepo:  siswaRepo,
+		userRepo:   userRepo,
+		tahunRepo:  tahunRepo,
+	}
+}
+
+// Create creates a new class group
+func (s *RombelService) Create(req requests.CreateRombelRequest) (*responses.RombelResponse, error) {
+	nama := utils.SanitizeString(req.Nama)
+	tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if name is already used in the academic year
+	exists, err := s.rombelRepo.ExistsByNamaAndTahun(nama, tahunPelajaran, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("class group name already exists in this academic year")
+	}
+
+	// Validate homeroom teacher exists
+	if err := s.validateWaliKelas(req.WaliKelasID); err != nil {
+		return nil, err
+	}
+
+	rombel := &models.Rombel{
+		Nama:           nama,
+		Tingkat:        req.Tingkat,
+		Jurusan:        utils.SanitizeString(req.Jurusan),
+		TahunPelajaran: tahunPelajaran,
+		WaliKelasID:    req.WaliKelasID,
+	}
+
+	if err := s.rombelRepo.Create(rombel); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(rombel.ID)
+}
+
+// FindByID finds a class group by ID
+func (s *RombelService) FindByID(id uint) (*responses.RombelResponse, error) {
+	rombel, err := s.rombelRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class group not found")
+		}
+		return nil, err
+	}
+
+	counts, err := s.rombelRepo.CountAnggotaByRombelIDs([]uint{rombel.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(rombel, counts[rombel.ID]), nil
+}
+
+// FindAll finds all class groups with pagination
+func (s *RombelService) FindAll(req requests.PaginationRequest, filter requests.RombelFilterRequest) ([]responses.RombelResponse, utils.Pagination, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filterMap := make(map[string]interface{})
+	if filter.Tingkat != "" {
+		filterMap["tingkat"] = filter.Tingkat
+	}
+	if filter.TahunPelajaran != "" {
+		filterMap["tahun_pelajaran"] = filter.TahunPelajaran
+	}
+	if filter.WaliKelasID > 0 {
+		filterMap["wali_kelas_id"] = filter.WaliKelasID
+	}
+
+	rombelList, total, err := s.rombelRepo.FindAll(req.Page, req.PageSize, req.Search, filterMap)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var ids []uint
+	for _, r := range rombelList {
+		ids = append(ids, r.ID)
+	}
+	counts, err := s.rombelRepo.CountAnggotaByRombelIDs(ids)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var result []responses.RombelResponse
+	for i := range rombelList {
+		result = append(result, *s.toResponse(&rombelList[i], counts[rombelList[i].ID]))
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+
+	return result, pagination, nil
+}
+
+// Update updates a class group
+func (s *RombelService) Update(id uint, req requests.UpdateRombelRequest) (*responses.RombelResponse, error) {
+	rombel, err := s.rombelRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class group not found")
+		}
+		return nil, err
+	}
+
+	if req.Nama != "" {
+		rombel.Nama = utils.SanitizeString(req.Nama)
+	}
+	if req.Tingkat != "" {
+		rombel.Tingkat = req.Tingkat
+	}
+	if req.Jurusan != "" {
+		rombel.Jurusan = utils.SanitizeString(req.Jurusan)
+	}
+	if req.TahunPelajaran != "" {
+		tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+		if err != nil {
+			return nil, err
+		}
+		if tahunPelajaran != rombel.TahunPelajaran {
+			// Members move with the class group, so none of them may already
+			// be enrolled elsewhere in the new academic year
+			members, err := s.rombelRepo.FindSiswaIDsByRombelID(rombel.ID)
+			if err != nil {
+				return nil, err
+			}
+			enrolled, err := s.rombelRepo.FindEnrolledSiswaIDs(members, tahunPelajaran)
+			if err != nil {
+				return nil, err
+			}
+			if len(enrolled) > 0 {
+				return nil, fmt.Errorf("students %v are already enrolled in a class group for %s", enrolled, tahunPelajaran)
+			}
+		}
+		rombel.TahunPelajaran = tahunPelajaran
+	}
+	if req.WaliKelasID != nil {
+		if err := s.validateWaliKelas(req.WaliKelasID); err != nil {
+			return nil, err
+		}
+		rombel.WaliKelasID = req.WaliKelasID
+	}
+
+	exists, err := s.rombelRepo.ExistsByNamaAndTahun(rombel.Nama, rombel.TahunPelajaran, rombel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("class group name already exists in this academic year")
+	}
+
+	if err := s.rombelRepo.Update(rombel); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(rombel.ID)
+}
+
+// Delete deletes a class group
+func (s *RombelService) Delete(id uint) error {
+	_, err := s.rombelRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("class group not found")
+		}
+		return err
+	}
+
+	return s.rombelRepo.Delete(id)
+}
+
+// AddAnggota enrolls students in a class group
+func (s *RombelService) AddAnggota(rombelID uint, req requests.AddAnggotaRombelRequest) (*responses.RombelResponse, error) {
+	rombel, err := s.rombelRepo.FindByID(rombelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class group not found")
+		}
+		return nil, err
+	}
+
+	// Validate students exist and remove duplicates
+	seen := make(map[uint]bool)
+	var siswaIDs []uint
+	for _, siswaID := range req.SiswaIDs {
+		if seen[siswaID] {
+			continue
+		}
+		seen[siswaID] = true
+
+		if _, err := s.siswaRepo.FindByID(siswaID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("student %d not found", siswaID)
+			}
+			return nil, err
+		}
+		siswaIDs = append(siswaIDs, siswaID)
+	}
+
+	// A student can only be in one class group per academic year
+	enrolled, err := s.rombelRepo.FindEnrolledSiswaIDs(siswaIDs, rombel.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+	if len(enrolled) > 0 {
+		return nil, fmt.Errorf("students %v are already enrolled in a class group for %s", enrolled, rombel.TahunPelajaran)
+	}
+
+	var anggota []models.AnggotaRombel
+	for _, siswaID := range siswaIDs {
+		anggota = append(anggota, models.AnggotaRombel{
+			RombelID:       rombel.ID,
+			SiswaID:        siswaID,
+			TahunPelajaran: rombel.TahunPelajaran,
+		})
+	}
+
+	if err := s.rombelRepo.AddAnggota(anggota); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(rombel.ID)
+}
+
+// RemoveAnggota removes a student from a class group
+func (s *RombelService) RemoveAnggota(rombelID, siswaID uint) error {
+	affected, err := s.rombelRepo.RemoveAnggota(rombelID, siswaID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("student is not a member of this class group")
+	}
+	return nil
+}
+
+// GetAnggota lists the students enrolled in a class group
+func (s *RombelService) GetAnggota(rombelID uint, req requests.PaginationRequest) ([]responses.SiswaListResponse, utils.Pagination, error) {
+	rombel, err := s.rombelRepo.FindByID(rombelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.Pagination{}, errors.New("class group not found")
+		}
+		return nil, utils.Pagination{}, err
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	siswaList, total, err := s.siswaRepo.FindAll(req.Page, req.PageSize, req.Search, "nama_lengkap", "asc", map[string]interface{}{
+		"rombel_id": rombel.ID,
+	})
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+
+	var result []responses.SiswaListResponse
+	for _, siswa := range siswaList {
+		result = append(result, responses.SiswaListResponse{
+			ID:           siswa.ID,
+			NoInduk:      siswa.NoInduk,
+			NISN:         siswa.NISN,
+			NamaLengkap:  siswa.NamaLengkap,
+			JenisKelamin: siswa.JenisKelamin,
+			Kelas:        rombel.Tingkat,
+			FotoPath:     siswa.FotoPath,
+			CreatedAt:    siswa.CreatedAt,
+		})
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	pagination := utils.Pagination{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+
+	return result, pagination, nil
+}
+
+// validateWaliKelas checks that the homeroom teacher user exists
+func (s *RombelService) validateWaliKelas(waliKelasID *uint) error {
+	if waliKelasID == nil {
+		return nil
+	}
+	if _, err := s.userRepo.FindByID(*waliKelasID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("homeroom teacher user not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// toResponse converts to DTO
+func (s *RombelService) toResponse(r *models.Rombel, jumlahAnggota int64) *responses.RombelResponse {
+	resp := &responses.RombelResponse{
+		ID:             r.ID,
+		Nama:           r.Nama,
+		Tingkat:        r.Tingkat,
+		Jurusan:        r.Jurusan,
+		TahunPelajaran: r.TahunPelajaran,
+		JumlahAnggota:  jumlahAnggota,
+	}
+
+	if r.WaliKelas != nil {
+		resp.WaliKelas = &responses.UserResponse{
+			ID:       r.WaliKelas.ID,
+			Username: r.WaliKelas.Username,
+			Email:    r.WaliKelas.Email,
+			IsActive: r.WaliKelas.IsActive,
+		}
+	}
+
+	return resp
+}
@@ -20,6 +20,7 @@ type SiswaService struct {
 	orangTuaRepo  *repositories.OrangTuaRepository
 	waliRepo      *repositories.WaliRepository
 	kesehatanRepo *repositories.KesehatanRepository
+	jurusanRepo   *repositories.JurusanRepository
 }
 
 // NewSiswaService creates a new SiswaService
@@ -29,6 +30,7 @@ func NewSiswaService(
 	orangTuaRepo *repositories.OrangTuaRepository,
 	waliRepo *repositories.WaliRepository,
 	kesehatanRepo *repositories.KesehatanRepository,
+	jurusanRepo *repositories.JurusanRepository,
 ) *SiswaService {
 	return &SiswaService{
 		siswaRepo:     siswaRepo,
@@ -36,42 +38,65 @@ func NewSiswaService(
 		orangTuaRepo:  orangTuaRepo,
 		waliRepo:      waliRepo,
 		kesehatanRepo: kesehatanRepo,
+		jurusanRepo:   jurusanRepo,
 	}
 }
 
 // Create creates a new student
 func (s *SiswaService) Create(req requests.CreateSiswaRequest) (*responses.SiswaDetailResponse, error) {
+	if err := s.validateNew(req); err != nil {
+		return nil, err
+	}
+
+	siswa, err := newSiswa(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.siswaRepo.Create(siswa); err != nil {
+		return nil, err
+	}
+
+	return s.toDetailResponse(siswa), nil
+}
+
+// validateNew checks the NISN format and that NISN and NoInduk are not taken
+func (s *SiswaService) validateNew(req requests.CreateSiswaRequest) error {
 	// Validate NISN
 	if !utils.ValidateNISN(req.NISN) {
-		return nil, errors.New("NISN must be 10 digits")
+		return errors.New("NISN must be 10 digits")
 	}
 
 	// Check if NISN exists
 	exists, err := s.siswaRepo.ExistsByNISN(req.NISN)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if exists {
-		return nil, errors.New("NISN already exists")
+		return errors.New("NISN already exists")
 	}
 
 	// Check if NoInduk exists
 	exists, err = s.siswaRepo.ExistsByNoInduk(req.NoInduk)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if exists {
-		return nil, errors.New("school registration number already exists")
+		return errors.New("school registration number already exists")
 	}
 
+	return nil
+}
+
+// newSiswa builds a student from a create request
+func newSiswa(req requests.CreateSiswaRequest) (*models.Siswa, error) {
 	// Parse date
 	tanggalLahir, err := time.Parse("2006-01-02", req.TanggalLahir)
 	if err != nil {
 		return nil, errors.New("invalid date format, use YYYY-MM-DD")
 	}
 
-	// Create siswa
-	siswa := &models.Siswa{
+	return &models.Siswa{
 		NoInduk:         utils.SanitizeString(req.NoInduk),
 		NISN:            req.NISN,
 		NamaLengkap:     utils.SanitizeString(req.NamaLengkap),
@@ -84,13 +109,7 @@ func (s *SiswaService) Create(req requests.CreateSiswaRequest) (*responses.Siswa
 		JumlahSaudara:   req.JumlahSaudara,
 		Kewarganegaraan: utils.SanitizeString(req.Kewarganegaraan),
 		BahasaRumah:     utils.SanitizeString(req.BahasaRumah),
-	}
-
-	if err := s.siswaRepo.Create(siswa); err != nil {
-		return nil, err
-	}
-
-	return s.toDetailResponse(siswa), nil
+	}, nil
 }
 
 // FindByID finds a student by ID
@@ -107,7 +126,7 @@ func (s *SiswaService) FindByID(id uint) (*responses.SiswaDetailResponse, error)
 }
 
 // FindAll finds all students with pagination
-func (s *SiswaService) FindAll(req requests.PaginationRequest) ([]responses.SiswaListResponse, utils.Pagination, error) {
+func (s *SiswaService) FindAll(req requests.PaginationRequest, filter requests.SiswaFilterRequest) ([]responses.SiswaListResponse, utils.Pagination, error) {
 	if req.Page < 1 {
 		req.Page = 1
 	}
@@ -115,14 +134,14 @@ func (s *SiswaService) FindAll(req requests.PaginationRequest) ([]responses.Sisw
 		req.PageSize = 20
 	}
 
-	siswaList, total, err := s.siswaRepo.FindAll(req.Page, req.PageSize, req.Search, req.SortBy, req.SortDir)
+	siswaList, total, err := s.siswaRepo.FindAll(req.Page, req.PageSize, req.Search, req.SortBy, req.SortDir, toSiswaFilterMap(filter))
 	if err != nil {
 		return nil, utils.Pagination{}, err
 	}
 
 	var response []responses.SiswaListResponse
 	for _, siswa := range siswaList {
-		response = append(response, responses.SiswaListResponse{
+		item := responses.SiswaListResponse{
 			ID:           siswa.ID,
 			NoInduk:      siswa.NoInduk,
 			NISN:         siswa.NISN,
@@ -130,7 +149,14 @@ func (s *SiswaService) FindAll(req requests.PaginationRequest) ([]responses.Sisw
 			JenisKelamin: siswa.JenisKelamin,
 			FotoPath:     siswa.FotoPath,
 			CreatedAt:    siswa.CreatedAt,
-		})
+		}
+		if siswa.KompetensiKeahlian != nil {
+			item.Jurusan = siswa.KompetensiKeahlian.Singkatan
+			if item.Jurusan == "" {
+				item.Jurusan = siswa.KompetensiKeahlian.Nama
+			}
+		}
+		response = append(response, item)
 	}
 
 	totalPages := int(total) / req.PageSize
@@ -148,6 +174,62 @@ func (s *SiswaService) FindAll(req requests.PaginationRequest) ([]responses.Sisw
 	return response, pagination, nil
 }
 
+// RekapJurusan counts students per kompetensi keahlian, applying the same
+// search and filters as FindAll. Students without a kompetensi keahlian are
+// counted in an entry without kompetensi_keahlian.
+func (s *SiswaService) RekapJurusan(search string, filter requests.SiswaFilterRequest) ([]responses.RekapJurusanResponse, error) {
+	rows, err := s.siswaRepo.CountByKompetensiKeahlian(search, toSiswaFilterMap(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the kompetensi keahlian of all groups at once
+	var ids []uint
+	for _, row := range rows {
+		if row.KompetensiKeahlianID != nil {
+			ids = append(ids, *row.KompetensiKeahlianID)
+		}
+	}
+	kompetensiList, err := s.jurusanRepo.FindKompetensiByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	kompetensi := make(map[uint]*models.KompetensiKeahlian, len(kompetensiList))
+	for i := range kompetensiList {
+		kompetensi[kompetensiList[i].ID] = &kompetensiList[i]
+	}
+
+	var result []responses.RekapJurusanResponse
+	index := make(map[uint]int)
+	for _, row := range rows {
+		var key uint
+		if row.KompetensiKeahlianID != nil {
+			key = *row.KompetensiKeahlianID
+		}
+
+		i, ok := index[key]
+		if !ok {
+			rekap := responses.RekapJurusanResponse{}
+			if k, found := kompetensi[key]; found {
+				rekap.KompetensiKeahlian = toKompetensiKeahlianResponse(k)
+			}
+			result = append(result, rekap)
+			i = len(result) - 1
+			index[key] = i
+		}
+
+		switch row.JenisKelamin {
+		case "L":
+			result[i].JumlahLakiLaki += row.Jumlah
+		case "P":
+			result[i].JumlahPerempuan += row.Jumlah
+		}
+		result[i].Jumlah += row.Jumlah
+	}
+
+	return result, nil
+}
+
 // Update updates a student
 func (s *SiswaService) Update(id uint, req requests.UpdateSiswaRequest) (*responses.SiswaDetailResponse, error) {
 	siswa, err := s.siswaRepo.FindByID(id)
@@ -247,6 +329,32 @@ func (s *SiswaService) UploadFoto(id uint, file *multipart.FileHeader) (string,
 	return fotoPath, nil
 }
 
+// toSiswaFilterMap converts the student list filter to repository filters.
+// Students who left school are hidden unless explicitly requested.
+func toSiswaFilterMap(filter requests.SiswaFilterRequest) map[string]interface{} {
+	filterMap := make(map[string]interface{})
+	if filter.RombelID > 0 {
+		filterMap["rombel_id"] = filter.RombelID
+	}
+	switch filter.Status {
+	case "":
+		filterMap["status"] = "aktif"
+	case "semua":
+	default:
+		filterMap["status"] = filter.Status
+	}
+	if filter.KompetensiKeahlianID > 0 {
+		filterMap["kompetensi_keahlian_id"] = filter.KompetensiKeahlianID
+	}
+	if filter.ProgramKeahlianID > 0 {
+		filterMap["program_keahlian_id"] = filter.ProgramKeahlianID
+	}
+	if filter.BidangKeahlianID > 0 {
+		filterMap["bidang_keahlian_id"] = filter.BidangKeahlianID
+	}
+	return filterMap
+}
+
 // toDetailResponse converts model to response
 func (s *SiswaService) toDetailResponse(siswa *models.Siswa) *responses.SiswaDetailResponse {
 	resp := &responses.SiswaDetailResponse{
@@ -269,6 +377,10 @@ func (s *SiswaService) toDetailResponse(siswa *models.Siswa) *responses.SiswaDet
 	}
 
 	// Map related data
+	if siswa.KompetensiKeahlian != nil {
+		resp.KompetensiKeahlian = toKompetensiKeahlianResponse(siswa.KompetensiKeahlian)
+	}
+
 	if siswa.Alamat != nil {
 		resp.Alamat = &responses.AlamatResponse{
 			ID:             siswa.Alamat.ID,
@@ -295,7 +407,7 @@ func (s *SiswaService) toDetailResponse(siswa *models.Siswa) *responses.SiswaDet
 			Kewarganegaraan:    ortu.Kewarganegaraan,
 			PendidikanTerakhir: ortu.PendidikanTerakhir,
 			Pekerjaan:          ortu.Pekerjaan,
-			PenghasilanBulanan: ortu.PenghasilanBulanan,
+			PenghasilanBulanan: &ortu.PenghasilanBulanan,
 			Alamat:             ortu.Alamat,
 			NoTelepon:          ortu.NoTelepon,
 			MasihHidup:         ortu.MasihHidup,
@@ -312,12 +424,33 @@ func (s *SiswaService) toDetailResponse(siswa *models.Siswa) *responses.SiswaDet
 			Kewarganegaraan:     siswa.Wali.Kewarganegaraan,
 			PendidikanTerakhir:  siswa.Wali.PendidikanTerakhir,
 			Pekerjaan:           siswa.Wali.Pekerjaan,
-			PenghasilanBulanan:  siswa.Wali.PenghasilanBulanan,
+			PenghasilanBulanan:  &siswa.Wali.PenghasilanBulanan,
 			Alamat:              siswa.Wali.Alamat,
 			NoTelepon:           siswa.Wali.NoTelepon,
 			HubunganDenganSiswa: siswa.Wali.HubunganDenganSiswa,
 		}
 	}
 
+	if siswa.MeninggalkanSekolah != nil {
+		resp.MeninggalkanSekolah = toMeninggalkanSekolahResponse(siswa.MeninggalkanSekolah)
+	}
+
+	for _, k := range siswa.KenaikanKelas {
+		resp.KenaikanKelas = append(resp.KenaikanKelas, toKenaikanKelasResponse(k))
+	}
+
 	return resp
 }
+
+// toSiswaRingkasResponse converts a student to the summary embedded in other listings
+func toSiswaRingkasResponse(siswa *models.Siswa) *responses.SiswaRingkasResponse {
+	if siswa == nil {
+		return nil
+	}
+	return &responses.SiswaRingkasResponse{
+		ID:          siswa.ID,
+		NoInduk:     siswa.NoInduk,
+		NISN:        siswa.NISN,
+		NamaLengkap: siswa.NamaLengkap,
+	}
+}
@@ -0,0 +1,461 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/utils"
+)
+
+// maxImportBaris limits the number of students in one import file
+const maxImportBaris = 2000
+
+// importBaris holds the requests parsed from one row of the import file
+type importBaris struct {
+	siswa  requests.CreateSiswaRequest
+	alamat requests.CreateAlamatRequest
+	ayah   requests.CreateOrangTuaRequest
+	ibu    requests.CreateOrangTuaRequest
+	wali   requests.CreateWaliRequest
+	// grup marks the optional groups (alamat, ayah, ibu, wali) with at least one filled column
+	grup map[string]bool
+}
+
+// importKolom describes one column of the import template
+type importKolom struct {
+	nama  string
+	grup  string
+	wajib bool
+	ket   string
+	set   func(b *importBaris, v string) error
+}
+
+// importKolomSiswa lists the template columns in order. Columns of a group are
+// only read when at least one of them is filled.
+var importKolomSiswa = []importKolom{
+	{"no_induk", "", true, "Nomor induk siswa, maksimal 20 karakter", importTeks(func(b *importBaris) *string { return &b.siswa.NoInduk })},
+	{"nisn", "", true, "10 digit angka", importTeks(func(b *importBaris) *string { return &b.siswa.NISN })},
+	{"nama_lengkap", "", true, "", importTeks(func(b *importBaris) *string { return &b.siswa.NamaLengkap })},
+	{"nama_panggilan", "", false, "", importTeks(func(b *importBaris) *string { return &b.siswa.NamaPanggilan })},
+	{"jenis_kelamin", "", true, "L atau P", importTeks(func(b *importBaris) *string { return &b.siswa.JenisKelamin })},
+	{"tempat_lahir", "", true, "", importTeks(func(b *importBaris) *string { return &b.siswa.TempatLahir })},
+	{"tanggal_lahir", "", true, "YYYY-MM-DD", importTanggal(func(b *importBaris) *string { return &b.siswa.TanggalLahir })},
+	{"agama", "", true, "", importTeks(func(b *importBaris) *string { return &b.siswa.Agama })},
+	{"anak_ke", "", false, "Angka, kosong berarti 1", importAngka(func(b *importBaris) *uint { return &b.siswa.AnakKe })},
+	{"jumlah_saudara", "", false, "Angka", importAngka(func(b *importBaris) *uint { return &b.siswa.JumlahSaudara })},
+	{"kewarganegaraan", "", false, "", importTeks(func(b *importBaris) *string { return &b.siswa.Kewarganegaraan })},
+	{"bahasa_rumah", "", false, "", importTeks(func(b *importBaris) *string { return &b.siswa.BahasaRumah })},
+
+	{"alamat_lengkap", "alamat", false, "Wajib jika kolom alamat lain diisi", importTeks(func(b *importBaris) *string { return &b.alamat.AlamatLengkap })},
+	{"alamat_kelurahan", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.Kelurahan })},
+	{"alamat_kecamatan", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.Kecamatan })},
+	{"alamat_kota", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.Kota })},
+	{"alamat_provinsi", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.Provinsi })},
+	{"alamat_kode_pos", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.KodePos })},
+	{"alamat_no_telepon", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.NoTelepon })},
+	{"alamat_tinggal_dengan", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.TinggalDengan })},
+	{"alamat_jarak_ke_sekolah", "alamat", false, "Kilometer", importDesimal(func(b *importBaris) *float64 { return &b.alamat.JarakKeSekolah })},
+	{"alamat_transportasi", "alamat", false, "", importTeks(func(b *importBaris) *string { return &b.alamat.Transportasi })},
+
+	{"ayah_nama", "ayah", false, "Wajib jika kolom ayah lain diisi", importTeks(func(b *importBaris) *string { return &b.ayah.Nama })},
+	{"ayah_tempat_lahir", "ayah", false, "", importTeks(func(b *importBaris) *string { return &b.ayah.TempatLahir })},
+	{"ayah_tanggal_lahir", "ayah", false, "YYYY-MM-DD", importTanggal(func(b *importBaris) *string { return &b.ayah.TanggalLahir })},
+	{"ayah_pendidikan_terakhir", "ayah", false, "", importTeks(func(b *importBaris) *string { return &b.ayah.PendidikanTerakhir })},
+	{"ayah_pekerjaan", "ayah", false, "", importTeks(func(b *importBaris) *string { return &b.ayah.Pekerjaan })},
+	{"ayah_penghasilan_bulanan", "ayah", false, "Rupiah, tanpa titik", importDesimal(func(b *importBaris) *float64 { return &b.ayah.PenghasilanBulanan })},
+	{"ayah_no_telepon", "ayah", false, "", importTeks(func(b *importBaris) *string { return &b.ayah.NoTelepon })},
+	{"ayah_masih_hidup", "ayah", false, "Ya atau Tidak, kosong berarti Ya", importYaTidak(func(b *importBaris) *bool { return &b.ayah.MasihHidup })},
+
+	{"ibu_nama", "ibu", false, "Wajib jika kolom ibu lain diisi", importTeks(func(b *importBaris) *string { return &b.ibu.Nama })},
+	{"ibu_tempat_lahir", "ibu", false, "", importTeks(func(b *importBaris) *string { return &b.ibu.TempatLahir })},
+	{"ibu_tanggal_lahir", "ibu", false, "YYYY-MM-DD", importTanggal(func(b *importBaris) *string { return &b.ibu.TanggalLahir })},
+	{"ibu_pendidikan_terakhir", "ibu", false, "", importTeks(func(b *importBaris) *string { return &b.ibu.PendidikanTerakhir })},
+	{"ibu_pekerjaan", "ibu", false, "", importTeks(func(b *importBaris) *string { return &b.ibu.Pekerjaan })},
+	{"ibu_penghasilan_bulanan", "ibu", false, "Rupiah, tanpa titik", importDesimal(func(b *importBaris) *float64 { return &b.ibu.PenghasilanBulanan })},
+	{"ibu_no_telepon", "ibu", false, "", importTeks(func(b *importBaris) *string { return &b.ibu.NoTelepon })},
+	{"ibu_masih_hidup", "ibu", false, "Ya atau Tidak, kosong berarti Ya", importYaTidak(func(b *importBaris) *bool { return &b.ibu.MasihHidup })},
+
+	{"wali_nama", "wali", false, "Wajib jika kolom wali lain diisi", importTeks(func(b *importBaris) *string { return &b.wali.Nama })},
+	{"wali_jenis_kelamin", "wali", false, "L atau P, wajib jika kolom wali lain diisi", importTeks(func(b *importBaris) *string { return &b.wali.JenisKelamin })},
+	{"wali_tempat_lahir", "wali", false, "", importTeks(func(b *importBaris) *string { return &b.wali.TempatLahir })},
+	{"wali_tanggal_lahir", "wali", false, "YYYY-MM-DD", importTanggal(func(b *importBaris) *string { return &b.wali.TanggalLahir })},
+	{"wali_pendidikan_terakhir", "wali", false, "", importTeks(func(b *importBaris) *string { return &b.wali.PendidikanTerakhir })},
+	{"wali_pekerjaan", "wali", false, "", importTeks(func(b *importBaris) *string { return &b.wali.Pekerjaan })},
+	{"wali_penghasilan_bulanan", "wali", false, "Rupiah, tanpa titik", importDesimal(func(b *importBaris) *float64 { return &b.wali.PenghasilanBulanan })},
+	{"wali_alamat", "wali", false, "", importTeks(func(b *importBaris) *string { return &b.wali.Alamat })},
+	{"wali_no_telepon", "wali", false, "", importTeks(func(b *importBaris) *string { return &b.wali.NoTelepon })},
+	{"wali_hubungan_dengan_siswa", "wali", false, "Contoh: Paman", importTeks(func(b *importBaris) *string { return &b.wali.HubunganDenganSiswa })},
+}
+
+// Import creates students from a CSV or XLSX file filled from the import template.
+// Every row goes through the same checks as Create; the report lists the errors
+// of each row. Students are only saved when dryRun is false and every row is valid,
+// all in one transaction.
+func (s *SiswaService) Import(file *multipart.FileHeader, dryRun bool) (*responses.ImportSiswaResponse, error) {
+	data, err := utils.ReadUploadedFile(file)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := utils.ReadSpreadsheet(file.Filename, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("import file is empty")
+	}
+
+	// Map the header to template columns
+	kolomByNama := make(map[string]*importKolom)
+	for i := range importKolomSiswa {
+		kolomByNama[importKolomSiswa[i].nama] = &importKolomSiswa[i]
+	}
+	header := make([]*importKolom, len(rows[0]))
+	ada := make(map[string]bool)
+	for i, nama := range rows[0] {
+		nama = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(nama)), " ", "_")
+		if nama == "" {
+			continue
+		}
+		kolom, ok := kolomByNama[nama]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", rows[0][i])
+		}
+		if ada[nama] {
+			return nil, fmt.Errorf("duplicate column %q", rows[0][i])
+		}
+		ada[nama] = true
+		header[i] = kolom
+	}
+	for _, kolom := range importKolomSiswa {
+		if kolom.wajib && !ada[kolom.nama] {
+			return nil, fmt.Errorf("required column %q is missing", kolom.nama)
+		}
+	}
+
+	resp := &responses.ImportSiswaResponse{
+		DryRun: dryRun,
+		Errors: []responses.ImportSiswaErrorResponse{},
+	}
+	var siswaBaru []*models.Siswa
+	barisNISN := make(map[string]int)
+	barisNoInduk := make(map[string]int)
+	for i, row := range rows[1:] {
+		if barisKosong(row) {
+			continue
+		}
+		resp.TotalBaris++
+		if resp.TotalBaris > maxImportBaris {
+			return nil, fmt.Errorf("import file exceeds the maximum of %d students", maxImportBaris)
+		}
+
+		// Row numbers follow the spreadsheet, the header being row 1
+		nomor := i + 2
+		baris, errs := bacaBarisImport(header, row)
+		siswa, rowErrs := s.buildImportSiswa(baris)
+		errs = append(errs, rowErrs...)
+
+		// Uniqueness within the file itself
+		if nisn := baris.siswa.NISN; nisn != "" {
+			if n, ok := barisNISN[nisn]; ok {
+				errs = append(errs, fmt.Sprintf("NISN is also used in row %d", n))
+			} else {
+				barisNISN[nisn] = nomor
+			}
+		}
+		if noInduk := baris.siswa.NoInduk; noInduk != "" {
+			if n, ok := barisNoInduk[noInduk]; ok {
+				errs = append(errs, fmt.Sprintf("school registration number is also used in row %d", n))
+			} else {
+				barisNoInduk[noInduk] = nomor
+			}
+		}
+
+		if len(errs) > 0 {
+			resp.Errors = append(resp.Errors, responses.ImportSiswaErrorResponse{
+				Baris:   nomor,
+				NISN:    baris.siswa.NISN,
+				NoInduk: baris.siswa.NoInduk,
+				Errors:  errs,
+			})
+			continue
+		}
+		siswaBaru = append(siswaBaru, siswa)
+	}
+	if resp.TotalBaris == 0 {
+		return nil, errors.New("import file has no student rows")
+	}
+	resp.BarisGagal = len(resp.Errors)
+	resp.BarisValid = resp.TotalBaris - resp.BarisGagal
+
+	if dryRun || resp.BarisGagal > 0 {
+		return resp, nil
+	}
+
+	if err := s.siswaRepo.CreateBatch(siswaBaru); err != nil {
+		return nil, err
+	}
+	resp.Dibuat = len(siswaBaru)
+
+	return resp, nil
+}
+
+// ImportTemplate returns the blank import template as XLSX (default) or CSV
+func (s *SiswaService) ImportTemplate(format string) ([]byte, string, error) {
+	header := make([]string, len(importKolomSiswa))
+	for i, kolom := range importKolomSiswa {
+		header[i] = kolom.nama
+	}
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(header); err != nil {
+			return nil, "", err
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "template-import-siswa.csv", nil
+	}
+
+	headerRow := make([]interface{}, len(header))
+	widths := make([]float64, len(header))
+	petunjuk := [][]interface{}{{"Kolom", "Wajib", "Keterangan"}}
+	for i, kolom := range importKolomSiswa {
+		headerRow[i] = kolom.nama
+		widths[i] = float64(len(kolom.nama) + 4)
+		wajib := "Tidak"
+		if kolom.wajib {
+			wajib = "Ya"
+		}
+		petunjuk = append(petunjuk, []interface{}{kolom.nama, wajib, kolom.ket})
+	}
+
+	data, err := utils.WriteXLSX(
+		utils.XLSXSheet{
+			Name:        "Data Siswa",
+			Rows:        [][]interface{}{headerRow},
+			HeaderRows:  1,
+			Widths:      widths,
+			TextColumns: true,
+		},
+		utils.XLSXSheet{
+			Name:       "Petunjuk",
+			Rows:       petunjuk,
+			HeaderRows: 1,
+			Widths:     []float64{30, 8, 45},
+		},
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, "template-import-siswa.xlsx", nil
+}
+
+// buildImportSiswa validates one parsed row like Create does and builds the
+// student with its address, parents and guardian
+func (s *SiswaService) buildImportSiswa(baris *importBaris) (*models.Siswa, []string) {
+	var errs []string
+
+	// Binding rules of the requests
+	errs = append(errs, utils.ValidateStruct(baris.siswa)...)
+	if baris.grup["alamat"] {
+		errs = append(errs, prefixErrors("alamat", utils.ValidateStruct(baris.alamat))...)
+	}
+	if baris.grup["ayah"] {
+		errs = append(errs, prefixErrors("ayah", utils.ValidateStruct(baris.ayah))...)
+	}
+	if baris.grup["ibu"] {
+		errs = append(errs, prefixErrors("ibu", utils.ValidateStruct(baris.ibu))...)
+	}
+	if baris.grup["wali"] {
+		errs = append(errs, prefixErrors("wali", utils.ValidateStruct(baris.wali))...)
+	}
+
+	// NISN format and uniqueness
+	if err := s.validateNew(baris.siswa); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	siswa, err := newSiswa(baris.siswa)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if baris.grup["alamat"] {
+		siswa.Alamat = &models.AlamatSiswa{
+			AlamatLengkap:  utils.SanitizeString(baris.alamat.AlamatLengkap),
+			Kelurahan:      utils.SanitizeString(baris.alamat.Kelurahan),
+			Kecamatan:      utils.SanitizeString(baris.alamat.Kecamatan),
+			Kota:           utils.SanitizeString(baris.alamat.Kota),
+			Provinsi:       utils.SanitizeString(baris.alamat.Provinsi),
+			KodePos:        utils.SanitizeString(baris.alamat.KodePos),
+			NoTelepon:      utils.SanitizeString(baris.alamat.NoTelepon),
+			TinggalDengan:  utils.SanitizeString(baris.alamat.TinggalDengan),
+			JarakKeSekolah: baris.alamat.JarakKeSekolah,
+			Transportasi:   utils.SanitizeString(baris.alamat.Transportasi),
+		}
+	}
+
+	for _, req := range []struct {
+		grup string
+		req  requests.CreateOrangTuaRequest
+	}{{"ayah", baris.ayah}, {"ibu", baris.ibu}} {
+		if !baris.grup[req.grup] {
+			continue
+		}
+		orangTua, err := newOrangTua(req.req)
+		if err != nil {
+			errs = append(errs, req.grup+": "+err.Error())
+			continue
+		}
+		siswa.OrangTua = append(siswa.OrangTua, *orangTua)
+	}
+
+	if baris.grup["wali"] {
+		var tanggalLahir *time.Time
+		if baris.wali.TanggalLahir != "" {
+			parsed, err := time.Parse("2006-01-02", baris.wali.TanggalLahir)
+			if err != nil {
+				return nil, append(errs, "wali: invalid date format, use YYYY-MM-DD")
+			}
+			tanggalLahir = &parsed
+		}
+		siswa.Wali = &models.Wali{
+			Nama:                utils.SanitizeString(baris.wali.Nama),
+			JenisKelamin:        baris.wali.JenisKelamin,
+			TempatLahir:         utils.SanitizeString(baris.wali.TempatLahir),
+			TanggalLahir:        tanggalLahir,
+			Kewarganegaraan:     utils.SanitizeString(baris.wali.Kewarganegaraan),
+			PendidikanTerakhir:  utils.SanitizeString(baris.wali.PendidikanTerakhir),
+			Pekerjaan:           utils.SanitizeString(baris.wali.Pekerjaan),
+			PenghasilanBulanan:  baris.wali.PenghasilanBulanan,
+			Alamat:              utils.SanitizeString(baris.wali.Alamat),
+			NoTelepon:           utils.SanitizeString(baris.wali.NoTelepon),
+			HubunganDenganSiswa: utils.SanitizeString(baris.wali.HubunganDenganSiswa),
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return siswa, nil
+}
+
+// bacaBarisImport fills the requests of one row; values that cannot be parsed
+// are reported by column name
+func bacaBarisImport(header []*importKolom, row []string) (*importBaris, []string) {
+	baris := &importBaris{
+		siswa: requests.CreateSiswaRequest{AnakKe: 1},
+		ayah:  requests.CreateOrangTuaRequest{Tipe: "ayah", MasihHidup: true},
+		ibu:   requests.CreateOrangTuaRequest{Tipe: "ibu", MasihHidup: true},
+		grup:  make(map[string]bool),
+	}
+
+	var errs []string
+	for i, kolom := range header {
+		if kolom == nil || i >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(row[i])
+		if value == "" {
+			continue
+		}
+		if kolom.grup != "" {
+			baris.grup[kolom.grup] = true
+		}
+		if err := kolom.set(baris, value); err != nil {
+			errs = append(errs, kolom.nama+": "+err.Error())
+		}
+	}
+	return baris, errs
+}
+
+func importTeks(field func(b *importBaris) *string) func(b *importBaris, v string) error {
+	return func(b *importBaris, v string) error {
+		*field(b) = v
+		return nil
+	}
+}
+
+func importAngka(field func(b *importBaris) *uint) func(b *importBaris, v string) error {
+	return func(b *importBaris, v string) error {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		*field(b) = uint(n)
+		return nil
+	}
+}
+
+func importDesimal(field func(b *importBaris) *float64) func(b *importBaris, v string) error {
+	return func(b *importBaris, v string) error {
+		n, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", "."), 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		*field(b) = n
+		return nil
+	}
+}
+
+func importTanggal(field func(b *importBaris) *string) func(b *importBaris, v string) error {
+	return func(b *importBaris, v string) error {
+		*field(b) = tanggalSpreadsheet(v)
+		return nil
+	}
+}
+
+// tanggalSpreadsheet converts the serial date number of a cell formatted as a
+// date in Excel to YYYY-MM-DD; other values are returned as is
+func tanggalSpreadsheet(v string) string {
+	if serial, err := strconv.ParseFloat(v, 64); err == nil && serial >= 1 && serial < 100000 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)).Format("2006-01-02")
+	}
+	return v
+}
+
+func importYaTidak(field func(b *importBaris) *bool) func(b *importBaris, v string) error {
+	return func(b *importBaris, v string) error {
+		switch strings.ToLower(v) {
+		case "ya", "y", "true", "1":
+			*field(b) = true
+		case "tidak", "t", "false", "0":
+			*field(b) = false
+		default:
+			return errors.New("must be Ya or Tidak")
+		}
+		return nil
+	}
+}
+
+// barisKosong reports whether every cell of a row is blank
+func barisKosong(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func prefixErrors(prefix string, errs []string) []string {
+	for i := range errs {
+		errs[i] = prefix + ": " + errs[i]
+	}
+	return errs
+}
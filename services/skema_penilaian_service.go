@@ -0,0 +1,225 @@
+package services
+
+import (
+	"errors"
+	"math"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"gorm.io/gorm"
+)
+
+// SkemaPenilaianService handles grading scheme business logic
+type SkemaPenilaianService struct {
+	skemaRepo *repositories.SkemaPenilaianRepository
+	mapelRepo *repositories.MataPelajaranRepository
+	tahunRepo *repositories.TahunPelajaranRepository
+}
+
+// NewSkemaPenilaianService creates a new SkemaPenilaianService
+func NewSkemaPenilaianService(
+	skemaRepo *repositories.SkemaPenilaianRepository,
+	mapelRepo *repositories.MataPelajaranRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+) *SkemaPenilaianService {
+	return &SkemaPenilaianService{
+		skemaRepo: skemaRepo,
+		mapelRepo: mapelRepo,
+		tahunRepo: tahunRepo,
+	}
+}
+
+// Create creates a new grading scheme
+func (s *SkemaPenilaianService) Create(req requests.CreateSkemaPenilaianRequest) (*responses.SkemaPenilaianResponse, error) {
+	tahunPelajaran, err := validateTahunPelajaran(s.tahunRepo, req.TahunPelajaran)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MataPelajaranID != nil {
+		if _, err := s.mapelRepo.FindByID(*req.MataPelajaranID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("subject not found")
+			}
+			return nil, err
+		}
+	}
+
+	exists, err := s.skemaRepo.ExistsByTahunAndMapel(tahunPelajaran, req.MataPelajaranID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("grading scheme already exists for this academic year and subject")
+	}
+
+	skema := &models.SkemaPenilaian{
+		TahunPelajaran:  tahunPelajaran,
+		MataPelajaranID: req.MataPelajaranID,
+		KKM:             req.KKM,
+		BatasA:          req.BatasA,
+		BatasB:          req.BatasB,
+		BatasC:          req.BatasC,
+	}
+	if err := lengkapiBatasPredikat(skema); err != nil {
+		return nil, err
+	}
+
+	if err := s.skemaRepo.Create(skema); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(skema.ID)
+}
+
+// FindByID finds a grading scheme by ID
+func (s *SkemaPenilaianService) FindByID(id uint) (*responses.SkemaPenilaianResponse, error) {
+	skema, err := s.skemaRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("grading scheme not found")
+		}
+		return nil, err
+	}
+	return s.toResponse(skema), nil
+}
+
+// FindAll gets grading schemes
+func (s *SkemaPenilaianService) FindAll(filter requests.SkemaPenilaianFilterRequest) ([]responses.SkemaPenilaianResponse, error) {
+	filterMap := make(map[string]interface{})
+	if filter.TahunPelajaran != "" {
+		filterMap["tahun_pelajaran"] = filter.TahunPelajaran
+	}
+	if filter.MataPelajaranID > 0 {
+		filterMap["mata_pelajaran_id"] = filter.MataPelajaranID
+	}
+
+	skemaList, err := s.skemaRepo.FindAll(filterMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.SkemaPenilaianResponse
+	for i := range skemaList {
+		result = append(result, *s.toResponse(&skemaList[i]))
+	}
+	return result, nil
+}
+
+// Update updates a grading scheme. Existing grades keep their predikat until
+// HitungUlang is run for the scheme.
+func (s *SkemaPenilaianService) Update(id uint, req requests.UpdateSkemaPenilaianRequest) (*responses.SkemaPenilaianResponse, error) {
+	skema, err := s.skemaRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("grading scheme not found")
+		}
+		return nil, err
+	}
+
+	if req.BatasA > 0 || req.BatasB > 0 || req.BatasC > 0 {
+		skema.BatasA = req.BatasA
+		skema.BatasB = req.BatasB
+		skema.BatasC = req.BatasC
+	} else if req.KKM > 0 && req.KKM != skema.KKM {
+		// Boundaries follow a changed KKM unless they are given explicitly
+		skema.BatasA, skema.BatasB, skema.BatasC = 0, 0, 0
+	}
+	if req.KKM > 0 {
+		skema.KKM = req.KKM
+	}
+	if err := lengkapiBatasPredikat(skema); err != nil {
+		return nil, err
+	}
+
+	if err := s.skemaRepo.Update(skema); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(skema), nil
+}
+
+// Delete deletes a grading scheme
+func (s *SkemaPenilaianService) Delete(id uint) error {
+	if _, err := s.skemaRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("grading scheme not found")
+		}
+		return err
+	}
+
+	return s.skemaRepo.Delete(id)
+}
+
+// HitungUlang recomputes the predikat of the existing grades covered by a scheme
+func (s *SkemaPenilaianService) HitungUlang(id uint) (*responses.HitungUlangPredikatResponse, error) {
+	skema, err := s.skemaRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("grading scheme not found")
+		}
+		return nil, err
+	}
+
+	affected, err := s.skemaRepo.HitungUlangPredikat(skema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.HitungUlangPredikatResponse{
+		SkemaID:          skema.ID,
+		JumlahDiperbarui: affected,
+	}, nil
+}
+
+// toResponse converts to DTO
+func (s *SkemaPenilaianService) toResponse(skema *models.SkemaPenilaian) *responses.SkemaPenilaianResponse {
+	resp := &responses.SkemaPenilaianResponse{
+		ID:             skema.ID,
+		TahunPelajaran: skema.TahunPelajaran,
+		KKM:            skema.KKM,
+		BatasA:         skema.BatasA,
+		BatasB:         skema.BatasB,
+		BatasC:         skema.BatasC,
+	}
+
+	if skema.MataPelajaran != nil {
+		resp.MataPelajaran = toMataPelajaranResponse(skema.MataPelajaran)
+	}
+
+	return resp
+}
+
+// lengkapiBatasPredikat validates the predikat boundaries of a scheme. When none
+// are set, the range from the KKM to 100 is split into three equal intervals
+// for C, B and A, following the K13 guideline.
+func lengkapiBatasPredikat(skema *models.SkemaPenilaian) error {
+	if skema.BatasA == 0 && skema.BatasB == 0 && skema.BatasC == 0 {
+		interval := uint(math.Round(float64(100-skema.KKM) / 3))
+		skema.BatasC = skema.KKM
+		skema.BatasB = skema.KKM + interval
+		skema.BatasA = skema.KKM + 2*interval
+		return nil
+	}
+
+	if skema.BatasC == 0 || skema.BatasB <= skema.BatasC || skema.BatasA <= skema.BatasB || skema.BatasA > 100 {
+		return errors.New("predikat boundaries must satisfy 0 < batas_c < batas_b < batas_a <= 100")
+	}
+	return nil
+}
+
+// predikatNilai returns the predikat of a score under a grading scheme
+func predikatNilai(skema *models.SkemaPenilaian, nilai uint) string {
+	switch {
+	case nilai >= skema.BatasA:
+		return "A"
+	case nilai >= skema.BatasB:
+		return "B"
+	case nilai >= skema.BatasC:
+		return "C"
+	default:
+		return "D"
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/repositories"
+	"gorm.io/gorm"
+)
+
+// statistikCacheTTL is how long a computed dashboard summary is served before
+// the queries run again
+const statistikCacheTTL = time.Minute
+
+// StatistikService computes the dashboard statistics
+type StatistikService struct {
+	statistikRepo *repositories.StatistikRepository
+	tahunRepo     *repositories.TahunPelajaranRepository
+	siswaService  *SiswaService
+
+	mu    sync.Mutex
+	cache *responses.StatistikResponse
+}
+
+// NewStatistikService creates a new StatistikService
+func NewStatistikService(
+	statistikRepo *repositories.StatistikRepository,
+	tahunRepo *repositories.TahunPelajaranRepository,
+	siswaService *SiswaService,
+) *StatistikService {
+	return &StatistikService{
+		statistikRepo: statistikRepo,
+		tahunRepo:     tahunRepo,
+		siswaService:  siswaService,
+	}
+}
+
+// Get returns the dashboard summary. The result is cached for a short while;
+// concurrent requests wait for one computation instead of each querying.
+func (s *StatistikService) Get() (*responses.StatistikResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache != nil && time.Since(s.cache.DihitungPada) < statistikCacheTTL {
+		return s.cache, nil
+	}
+
+	resp, err := s.hitung()
+	if err != nil {
+		return nil, err
+	}
+	s.cache = resp
+	return resp, nil
+}
+
+// hitung runs the grouped queries of the summary
+func (s *StatistikService) hitung() (*responses.StatistikResponse, error) {
+	resp := &responses.StatistikResponse{
+		PerTingkat: []responses.StatistikJumlahResponse{},
+		PerJurusan: []responses.RekapJurusanResponse{},
+		Kehadiran:  []responses.StatistikKehadiranResponse{},
+	}
+
+	total, err := s.statistikRepo.CountAktif()
+	if err != nil {
+		return nil, err
+	}
+	resp.TotalSiswaAktif = total
+
+	// Without an active academic year no student is counted per tingkat
+	resp.TanpaRombel = total
+	aktif, err := s.tahunRepo.FindActive()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		resp.TahunPelajaran = aktif.Label
+		rows, err := s.statistikRepo.CountAktifByTingkat(aktif.Label)
+		if err != nil {
+			return nil, err
+		}
+		resp.PerTingkat = toStatistikJumlahResponse(rows)
+		for _, row := range rows {
+			resp.TanpaRombel -= row.Jumlah
+		}
+	}
+
+	jenisKelamin, err := s.statistikRepo.CountAktifBy("jenis_kelamin")
+	if err != nil {
+		return nil, err
+	}
+	resp.PerJenisKelamin = toStatistikJumlahResponse(jenisKelamin)
+
+	agama, err := s.statistikRepo.CountAktifBy("agama")
+	if err != nil {
+		return nil, err
+	}
+	resp.PerAgama = toStatistikJumlahResponse(agama)
+
+	jurusan, err := s.siswaService.RekapJurusan("", requests.SiswaFilterRequest{Status: "aktif"})
+	if err != nil {
+		return nil, err
+	}
+	resp.PerJurusan = append(resp.PerJurusan, jurusan...)
+
+	penerimaan, err := s.statistikRepo.CountPenerimaanPerTahun()
+	if err != nil {
+		return nil, err
+	}
+	resp.Penerimaan = toStatistikTahunResponse(penerimaan)
+
+	keluar, err := s.statistikRepo.CountKeluarPerTahun()
+	if err != nil {
+		return nil, err
+	}
+	resp.Keluar = toStatistikTahunResponse(keluar)
+
+	kehadiran, err := s.statistikRepo.AverageKehadiran()
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range kehadiran {
+		resp.Kehadiran = append(resp.Kehadiran, responses.StatistikKehadiranResponse{
+			Kelas:                   k.Kelas,
+			Semester:                k.Semester,
+			JumlahSiswa:             k.JumlahSiswa,
+			RataRataPersentaseHadir: bulatkan(k.PersentaseHadir),
+			RataRataSakit:           bulatkan(k.Sakit),
+			RataRataIzin:            bulatkan(k.Izin),
+			RataRataAlpa:            bulatkan(k.Alpa),
+		})
+	}
+
+	prestasi, err := s.statistikRepo.CountPrestasiByTingkat()
+	if err != nil {
+		return nil, err
+	}
+	resp.Prestasi = toStatistikJumlahResponse(prestasi)
+
+	resp.DihitungPada = time.Now()
+	return resp, nil
+}
+
+func toStatistikJumlahResponse(rows []repositories.JumlahStatistik) []responses.StatistikJumlahResponse {
+	result := make([]responses.StatistikJumlahResponse, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, responses.StatistikJumlahResponse{Label: row.Kunci, Jumlah: row.Jumlah})
+	}
+	return result
+}
+
+// toStatistikTahunResponse merges the per-type rows of each year, keeping the
+// year order of the query
+func toStatistikTahunResponse(rows []repositories.JumlahStatistikTahun) []responses.StatistikTahunResponse {
+	result := []responses.StatistikTahunResponse{}
+	for _, row := range rows {
+		if len(result) == 0 || result[len(result)-1].Tahun != row.Tahun {
+			result = append(result, responses.StatistikTahunResponse{Tahun: row.Tahun, PerTipe: map[string]int64{}})
+		}
+		tahun := &result[len(result)-1]
+		tahun.PerTipe[row.Tipe] += row.Jumlah
+		tahun.Jumlah += row.Jumlah
+	}
+	return result
+}
+
+// bulatkan rounds to two decimals
+func bulatkan(v float64) float64 {
+	return math.Round(v*100) / 100
+}
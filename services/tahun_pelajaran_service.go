@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// TahunPelajaranService handles academic year business logic
+type TahunPelajaranService struct {
+	tahunRepo *repositories.TahunPelajaranRepository
+}
+
+// NewTahunPelajaranService creates a new TahunPelajaranService
+func NewTahunPelajaranService(tahunRepo *repositories.TahunPelajaranRepository) *TahunPelajaranService {
+	return &TahunPelajaranService{tahunRepo: tahunRepo}
+}
+
+// Create creates a new academic year
+func (s *TahunPelajaranService) Create(req requests.CreateTahunPelajaranRequest) (*responses.TahunPelajaranResponse, error) {
+	label := utils.SanitizeString(req.Label)
+	if !utils.ValidateTahunPelajaran(label) {
+		return nil, errors.New("invalid academic year format, use YYYY/YYYY")
+	}
+
+	exists, err := s.tahunRepo.ExistsByLabel(label, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("academic year already exists")
+	}
+
+	tanggalMulai, tanggalSelesai, err := parseRentangTanggal(req.TanggalMulai, req.TanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+
+	tahun := &models.TahunPelajaran{
+		Label:          label,
+		TanggalMulai:   tanggalMulai,
+		TanggalSelesai: tanggalSelesai,
+		SemesterAktif:  1,
+	}
+
+	if err := s.tahunRepo.Create(tahun); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(tahun), nil
+}
+
+// FindAll gets all academic years
+func (s *TahunPelajaranService) FindAll() ([]responses.TahunPelajaranResponse, error) {
+	tahunList, err := s.tahunRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []responses.TahunPelajaranResponse
+	for i := range tahunList {
+		result = append(result, *s.toResponse(&tahunList[i]))
+	}
+	return result, nil
+}
+
+// FindByID finds an academic year by ID
+func (s *TahunPelajaranService) FindByID(id uint) (*responses.TahunPelajaranResponse, error) {
+	tahun, err := s.tahunRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("academic year not found")
+		}
+		return nil, err
+	}
+	return s.toResponse(tahun), nil
+}
+
+// FindActive finds the active academic year
+func (s *TahunPelajaranService) FindActive() (*responses.TahunPelajaranResponse, error) {
+	tahun, err := s.tahunRepo.FindActive()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no active academic year")
+		}
+		return nil, err
+	}
+	return s.toResponse(tahun), nil
+}
+
+// Update updates an academic year
+func (s *TahunPelajaranService) Update(id uint, req requests.UpdateTahunPelajaranRequest) (*responses.TahunPelajaranResponse, error) {
+	tahun, err := s.tahunRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("academic year not found")
+		}
+		return nil, err
+	}
+
+	if req.Label != "" {
+		label := utils.SanitizeString(req.Label)
+		if !utils.ValidateTahunPelajaran(label) {
+			return nil, errors.New("invalid academic year format, use YYYY/YYYY")
+		}
+		if label != tahun.Label {
+			// Records reference the label directly, so renaming would orphan them
+			inUse, err := s.tahunRepo.IsInUse(tahun.Label)
+			if err != nil {
+				return nil, err
+			}
+			if inUse {
+				return nil, errors.New("academic year is already in use and cannot be renamed")
+			}
+
+			exists, err := s.tahunRepo.ExistsByLabel(label, tahun.ID)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, errors.New("academic year already exists")
+			}
+			tahun.Label = label
+		}
+	}
+
+	mulai := tahun.TanggalMulai.Format("2006-01-02")
+	if req.TanggalMulai != "" {
+		mulai = req.TanggalMulai
+	}
+	selesai := tahun.TanggalSelesai.Format("2006-01-02")
+	if req.TanggalSelesai != "" {
+		selesai = req.TanggalSelesai
+	}
+	tahun.TanggalMulai, tahun.TanggalSelesai, err = parseRentangTanggal(mulai, selesai)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tahunRepo.Update(tahun); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(tahun), nil
+}
+
+// Activate sets the academic year and semester as the active one
+func (s *TahunPelajaranService) Activate(id uint, req requests.ActivateTahunPelajaranRequest) (*responses.TahunPelajaranResponse, error) {
+	if _, err := s.tahunRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("academic year not found")
+		}
+		return nil, err
+	}
+
+	if err := s.tahunRepo.Activate(id, req.Semester); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(id)
+}
+
+// Delete deletes an academic year
+func (s *TahunPelajaranService) Delete(id uint) error {
+	tahun, err := s.tahunRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("academic year not found")
+		}
+		return err
+	}
+
+	if tahun.IsActive {
+		return errors.New("cannot delete the active academic year")
+	}
+
+	inUse, err := s.tahunRepo.IsInUse(tahun.Label)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("academic year is already in use and cannot be deleted")
+	}
+
+	return s.tahunRepo.Delete(id)
+}
+
+// toResponse converts to DTO
+func (s *TahunPelajaranService) toResponse(t *models.TahunPelajaran) *responses.TahunPelajaranResponse {
+	return &responses.TahunPelajaranResponse{
+		ID:             t.ID,
+		Label:          t.Label,
+		TanggalMulai:   t.TanggalMulai,
+		TanggalSelesai: t.TanggalSelesai,
+		IsActive:       t.IsActive,
+		SemesterAktif:  t.SemesterAktif,
+	}
+}
+
+// parseRentangTanggal parses a start/end date pair and checks their order
+func parseRentangTanggal(mulai, selesai string) (time.Time, time.Time, error) {
+	tanggalMulai, err := time.Parse("2006-01-02", mulai)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+	tanggalSelesai, err := time.Parse("2006-01-02", selesai)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+	if !tanggalSelesai.After(tanggalMulai) {
+		return time.Time{}, time.Time{}, errors.New("end date must be after start date")
+	}
+	return tanggalMulai, tanggalSelesai, nil
+}
+
+// validateTahunPelajaran checks that a tahun_pelajaran value refers to a
+// registered academic year and returns it sanitized. Empty values are
+// returned as-is so optional fields stay optional.
+func validateTahunPelajaran(tahunRepo *repositories.TahunPelajaranRepository, label string) (string, error) {
+	label = utils.SanitizeString(label)
+	if label == "" {
+		return "", nil
+	}
+	if _, err := tahunRepo.FindByLabel(label); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("academic year %s is not registered", label)
+		}
+		return "", err
+	}
+	return label, nil
+}
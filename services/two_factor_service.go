@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kampunk/api-siswa/configs"
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// recoveryCodeCount is the number of recovery codes generated at a time
+const recoveryCodeCount = 10
+
+// TwoFactorService handles TOTP two-factor authentication and its per-role policy
+type TwoFactorService struct {
+	userRepo      *repositories.UserRepository
+	sessionRepo   *repositories.SessionRepository
+	twoFactorRepo *repositories.TwoFactorRepository
+}
+
+// NewTwoFactorService creates a new TwoFactorService
+func NewTwoFactorService(
+	userRepo *repositories.UserRepository,
+	sessionRepo *repositories.SessionRepository,
+	twoFactorRepo *repositories.TwoFactorRepository,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+// Status returns whether the user has two-factor authentication and whether
+// one of their roles requires it
+func (s *TwoFactorService) Status(userID uint) (*responses.TwoFactorStatusResponse, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := s.twoFactorRepo.IsRequired(roleNames(user))
+	if err != nil {
+		return nil, err
+	}
+	resp := &responses.TwoFactorStatusResponse{Enabled: user.TOTPEnabled, Required: required}
+	if user.TOTPEnabled {
+		if resp.RecoveryCodesLeft, err = s.twoFactorRepo.CountRecoveryCodes(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// Setup starts enrolment with a new secret. Two-factor authentication is only
+// turned on once Enable confirms a code from the authenticator app.
+func (s *TwoFactorService) Setup(userID uint) (*responses.TwoFactorSetupResponse, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate secret")
+	}
+	if err := s.twoFactorRepo.SetSecret(user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	issuer := configs.AppConfig.School.Name
+	if issuer == "" {
+		issuer = "API Siswa Induk"
+	}
+	return &responses.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPProvisioningURI(issuer, user.Username, secret),
+	}, nil
+}
+
+// Enable confirms enrolment with a code from the authenticator app and returns
+// the first recovery codes. The user's other sessions were started without the
+// second factor, so they are signed out; the current one stays.
+func (s *TwoFactorService) Enable(userID, sessionID uint, req requests.TwoFactorCodeRequest) (*responses.RecoveryCodesResponse, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor setup has not been started")
+	}
+
+	counter, ok := utils.ValidateTOTP(user.TOTPSecret, req.Code, time.Now(), 0)
+	if !ok {
+		return nil, errors.New("invalid authentication code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.Enable(user.ID, counter, hashes); err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.RevokeOthers(user.ID, sessionID); err != nil {
+		return nil, err
+	}
+	return &responses.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns off two-factor authentication after checking the password and
+// a code. Users whose role requires it cannot turn it off.
+func (s *TwoFactorService) Disable(userID uint, req requests.DisableTwoFactorRequest) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	required, err := s.twoFactorRepo.IsRequired(roleNames(user))
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.New("two-factor authentication is required for your role")
+	}
+
+	if !utils.CheckPassword(req.Password, user.PasswordHash) {
+		return errors.New("password is incorrect")
+	}
+	valid, err := s.verify(user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid authentication code")
+	}
+
+	return s.twoFactorRepo.Disable(user.ID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a code
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, req requests.TwoFactorCodeRequest) (*responses.RecoveryCodesResponse, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	valid, err := s.verify(user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, errors.New("invalid authentication code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		return nil, err
+	}
+	return &responses.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Reset turns off two-factor authentication of a user who lost their
+// authenticator and recovery codes and signs them out of every session. If a
+// role requires it, the user enrols again after the next login.
+func (s *TwoFactorService) Reset(userID uint) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.Disable(user.ID); err != nil {
+		return err
+	}
+	_, err = s.sessionRepo.RevokeAllByUserID(user.ID)
+	return err
+}
+
+// verify checks an authenticator code or, failing that, an unused recovery
+// code. Either works only once.
+func (s *TwoFactorService) verify(user *models.User, code string) (bool, error) {
+	if counter, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastCounter); ok {
+		return s.twoFactorRepo.UseCounter(user.ID, user.TOTPLastCounter, counter)
+	}
+	return s.twoFactorRepo.UseRecoveryCode(user.ID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+}
+
+func (s *TwoFactorService) findUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// newRecoveryCodes generates recovery codes with the hashes they are stored under
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate recovery codes")
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = utils.HashToken(utils.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
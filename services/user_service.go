@@ -0,0 +1,167 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kampunk/api-siswa/dtos/requests"
+	"github.com/kampunk/api-siswa/dtos/responses"
+	"github.com/kampunk/api-siswa/models"
+	"github.com/kampunk/api-siswa/repositories"
+	"github.com/kampunk/api-siswa/utils"
+	"gorm.io/gorm"
+)
+
+// UserService handles admin user and role management
+type UserService struct {
+	userRepo      *repositories.UserRepository
+	sessionRepo   *repositories.SessionRepository
+	twoFactorRepo *repositories.TwoFactorRepository
+}
+
+// NewUserService creates a new UserService
+func NewUserService(
+	userRepo *repositories.UserRepository,
+	sessionRepo *repositories.SessionRepository,
+	twoFactorRepo *repositories.TwoFactorRepository,
+) *UserService {
+	return &UserService{userRepo: userRepo, sessionRepo: sessionRepo, twoFactorRepo: twoFactorRepo}
+}
+
+// FindAll lists the admin users with their roles
+func (s *UserService) FindAll(filter requests.UserFilterRequest) ([]responses.UserResponse, error) {
+	users, err := s.userRepo.FindAll(utils.SanitizeString(filter.Search), filter.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.UserResponse, 0, len(users))
+	for i := range users {
+		result = append(result, *toUserResponse(&users[i]))
+	}
+	return result, nil
+}
+
+// FindByID finds an admin user with their roles
+func (s *UserService) FindByID(id uint) (*responses.UserResponse, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return toUserResponse(user), nil
+}
+
+// SetRoles replaces the roles of a user. The last active super admin keeps the
+// super admin role so user management cannot lock itself out. New roles apply
+// from the user's next token refresh.
+func (s *UserService) SetRoles(id uint, req requests.SetUserRolesRequest) (*responses.UserResponse, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	for _, role := range roleNames(user) {
+		if utils.IsPortalRole(role) {
+			return nil, errors.New("portal account roles cannot be changed")
+		}
+	}
+
+	roles := uniqueRoles(req.Roles)
+	if user.IsActive && hasRole(roleNames(user), models.RoleSuperAdmin) && !hasRole(roles, models.RoleSuperAdmin) {
+		others, err := s.userRepo.CountActiveByRole(models.RoleSuperAdmin, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if others == 0 {
+			return nil, errors.New("cannot remove the super_admin role from the last active super admin")
+		}
+	}
+
+	if err := s.userRepo.SetRoles(user.ID, roles); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(user.ID)
+}
+
+// RevokeSessions signs a user out on every device, for example when a laptop is lost
+func (s *UserService) RevokeSessions(id uint) (*responses.RevokedSessionsResponse, error) {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	revoked, err := s.sessionRepo.RevokeAllByUserID(id)
+	if err != nil {
+		return nil, err
+	}
+	return &responses.RevokedSessionsResponse{Revoked: revoked}, nil
+}
+
+// Roles lists every role with the permissions it grants and its two-factor policy
+func (s *UserService) Roles() ([]responses.RoleResponse, error) {
+	policies, err := s.twoFactorRepo.FindPolicies()
+	if err != nil {
+		return nil, err
+	}
+	requireTwoFactor := make(map[string]bool)
+	for _, p := range policies {
+		requireTwoFactor[p.Role] = p.RequireTwoFactor
+	}
+
+	var result []responses.RoleResponse
+	for _, role := range utils.Roles() {
+		resp := responses.RoleResponse{
+			Role:             role,
+			RequireTwoFactor: requireTwoFactor[role],
+			Permissions:      []responses.PermissionResponse{},
+		}
+		granted := utils.RolePermissions(role)
+		for _, p := range utils.Permissions() {
+			if scope := granted[p]; scope != utils.ScopeNone {
+				resp.Permissions = append(resp.Permissions, responses.PermissionResponse{Permission: p, Scope: scope.String()})
+			}
+		}
+		result = append(result, resp)
+	}
+	return result, nil
+}
+
+// SetRolePolicy sets whether a staff role requires two-factor authentication.
+// Users of the role without it are held at the setup routes from their next request.
+func (s *UserService) SetRolePolicy(role string, req requests.SetRolePolicyRequest) (*responses.RoleResponse, error) {
+	if !hasRole(utils.Roles(), role) {
+		return nil, fmt.Errorf("unknown staff role %q", role)
+	}
+	if err := s.twoFactorRepo.SetRequired(role, *req.RequireTwoFactor); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.Roles()
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		if roles[i].Role == role {
+			return &roles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown staff role %q", role)
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -104,7 +104,7 @@ func (s *WaliService) toResponse(wali *models.Wali) *responses.WaliResponse {
 		Kewarganegaraan:     wali.Kewarganegaraan,
 		PendidikanTerakhir:  wali.PendidikanTerakhir,
 		Pekerjaan:           wali.Pekerjaan,
-		PenghasilanBulanan:  wali.PenghasilanBulanan,
+		PenghasilanBulanan:  &wali.PenghasilanBulanan,
 		Alamat:              wali.Alamat,
 		NoTelepon:           wali.NoTelepon,
 		HubunganDenganSiswa: wali.HubunganDenganSiswa,
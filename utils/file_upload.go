@@ -63,6 +63,24 @@ func SaveUploadedFile(file *multipart.FileHeader, subDir string) (string, error)
 	return filepath.Join(subDir, filename), nil
 }
 
+// ReadUploadedFile reads the content of an uploaded file into memory
+func ReadUploadedFile(file *multipart.FileHeader) ([]byte, error) {
+	cfg := configs.AppConfig
+
+	// Check file size
+	if file.Size > cfg.Upload.MaxSize {
+		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", cfg.Upload.MaxSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+
+	return io.ReadAll(src)
+}
+
 // ValidateImageFile validates if the file is a valid image
 func ValidateImageFile(file *multipart.FileHeader) error {
 	// Check content type
@@ -99,6 +117,12 @@ func DeleteFile(relativePath string) error {
 	return os.Remove(fullPath)
 }
 
+// ReadFile reads a file from the upload directory
+func ReadFile(relativePath string) ([]byte, error) {
+	cfg := configs.AppConfig
+	return os.ReadFile(filepath.Join(cfg.Upload.Path, relativePath))
+}
+
 // generateRandomString generates a random alphanumeric string
 func generateRandomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
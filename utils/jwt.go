@@ -1,29 +1,40 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/kampunk/api-siswa/configs"
 )
 
-// Claims represents JWT claims
+// Claims represents JWT claims. SessionID is the login session the access token
+// belongs to, so revoking the session invalidates the token.
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
+	UserID    uint     `json:"user_id"`
+	Username  string   `json:"username"`
+	Roles     []string `json:"roles"`
+	SessionID uint     `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a new JWT token
-func GenerateToken(userID uint, username string) (string, error) {
+// GenerateToken generates a short-lived JWT access token carrying the user's
+// roles and login session
+func GenerateToken(userID uint, username string, roles []string, sessionID uint) (string, error) {
 	cfg := configs.AppConfig
 
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:    userID,
+		Username:  username,
+		Roles:     roles,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(cfg.JWT.ExpiryHours) * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(cfg.JWT.ExpiryMinutes) * time.Minute)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "api-siswa",
@@ -49,9 +60,67 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	// Challenge tokens of the two-step login are not access tokens
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && len(claims.Audience) == 0 {
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
+
+// challengeAudience marks the challenge tokens of the two-step login
+const challengeAudience = "2fa-challenge"
+
+// ChallengeExpiry is how long the second login step may take
+const ChallengeExpiry = 5 * time.Minute
+
+// GenerateChallengeToken generates the short-lived token returned by the first
+// login step of a user with two-factor authentication. It only proves the
+// password was correct and cannot be used as an access token.
+func GenerateChallengeToken(userID uint) (string, error) {
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.FormatUint(uint64(userID), 10),
+		Audience:  jwt.ClaimStrings{challengeAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ChallengeExpiry)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		Issuer:    "api-siswa",
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(configs.AppConfig.JWT.Secret))
+}
+
+// ValidateChallengeToken validates a challenge token and returns its user ID
+func ValidateChallengeToken(tokenString string) (uint, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(configs.AppConfig.JWT.Secret), nil
+	}, jwt.WithAudience(challengeAudience))
+	if err != nil {
+		return 0, err
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid token")
+	}
+	return uint(userID), nil
+}
+
+// GenerateRefreshToken generates a random opaque refresh token
+func GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the SHA-256 hex digest under which a refresh token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
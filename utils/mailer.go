@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kampunk/api-siswa/configs"
+)
+
+// Mailer sends plain-text email
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// NewMailer returns the mailer selected by MAIL_DRIVER
+func NewMailer(cfg configs.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		if cfg.Host == "" {
+			return nil, errors.New("SMTP_HOST is required for the smtp mail driver")
+		}
+		return &SMTPMailer{cfg: cfg}, nil
+	case "file", "":
+		return &FileMailer{dir: cfg.Dir, from: cfg.From}, nil
+	}
+	return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+}
+
+// SMTPMailer sends email through an SMTP server, using STARTTLS when the
+// server offers it
+type SMTPMailer struct {
+	cfg configs.MailConfig
+}
+
+// Send sends a message
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+	return smtp.SendMail(addr, auth, m.cfg.From, []string{to}, buildMessage(m.cfg.From, to, subject, body))
+}
+
+// FileMailer writes each message as an .eml file instead of sending it, for
+// development and schools without a mail server. Without a directory the
+// message only goes to the log.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// Send writes a message
+func (m *FileMailer) Send(to, subject, body string) error {
+	msg := buildMessage(m.from, to, subject, body)
+	if m.dir == "" {
+		log.Printf("Mail to %s:\n%s", to, msg)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+	filename := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), generateRandomString(8))
+	path := filepath.Join(m.dir, filename)
+	if err := os.WriteFile(path, msg, 0600); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s written to %s", to, path)
+	return nil
+}
+
+// buildMessage formats a plain-text message with its headers
+func buildMessage(from, to, subject, body string) []byte {
+	// Header values must not break out of their line
+	clean := strings.NewReplacer("\r", "", "\n", "").Replace
+	var b strings.Builder
+	b.WriteString("From: " + clean(from) + "\r\n")
+	b.WriteString("To: " + clean(to) + "\r\n")
+	b.WriteString("Subject: " + clean(subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
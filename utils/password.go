@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
+	"math/big"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,3 +20,22 @@ func CheckPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// GenerateTemporaryPassword generates a random password handed to a user after
+// an admin reset. Characters that are easy to misread are left out.
+func GenerateTemporaryPassword() (string, error) {
+	return randomString("abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789", 12)
+}
+
+// randomString generates a cryptographically random string from a charset
+func randomString(charset string, length int) (string, error) {
+	result := make([]byte, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", err
+		}
+		result[i] = charset[n.Int64()]
+	}
+	return string(result), nil
+}
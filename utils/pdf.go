@@ -0,0 +1,405 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"strings"
+)
+
+// PDFAlign is the horizontal alignment of text in a PDF line or cell
+type PDFAlign int
+
+const (
+	AlignLeft PDFAlign = iota
+	AlignCenter
+	AlignRight
+)
+
+// PDFRowStyle controls how PDF.Row draws a table row
+type PDFRowStyle struct {
+	Border bool
+	Fill   bool
+	Aligns []PDFAlign
+}
+
+const (
+	pdfPageWidth  = 595.28 // A4 in points
+	pdfPageHeight = 841.89
+	pdfMargin     = 40.0
+	pdfCellPad    = 3.0
+	pdfLineFactor = 1.25
+	pdfImageDPI   = 200.0
+)
+
+// Character widths of the standard Helvetica fonts for ASCII 32-126, in 1/1000 em
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// winAnsiPunctuation maps the typographic characters of WinAnsiEncoding
+// outside the Latin-1 range
+var winAnsiPunctuation = map[rune]byte{
+	'€': 128, '…': 133, '‘': 145, '’': 146, '“': 147, '”': 148, '•': 149, '–': 150, '—': 151,
+}
+
+// PDF is a minimal A4 PDF writer built on the standard Helvetica fonts, so
+// documents can be generated on an offline server without external tools.
+// Content flows top to bottom from a cursor and breaks to a new page when full.
+type PDF struct {
+	pages    []*bytes.Buffer
+	page     *bytes.Buffer
+	images   [][]byte
+	y        float64
+	fontSize float64
+	bold     bool
+}
+
+// NewPDF creates an empty document using 10pt Helvetica
+func NewPDF() *PDF {
+	return &PDF{fontSize: 10}
+}
+
+// AddPage starts a new page and moves the cursor to its top margin
+func (p *PDF) AddPage() {
+	p.page = &bytes.Buffer{}
+	p.page.WriteString("0.5 w\n")
+	p.pages = append(p.pages, p.page)
+	p.y = pdfMargin
+}
+
+// SetFont sets the font used by the following text
+func (p *PDF) SetFont(bold bool, size float64) {
+	p.bold = bold
+	p.fontSize = size
+}
+
+// ContentWidth returns the usable width between the page margins
+func (p *PDF) ContentWidth() float64 {
+	return pdfPageWidth - 2*pdfMargin
+}
+
+// Y returns the cursor position from the top of the page
+func (p *PDF) Y() float64 {
+	return p.y
+}
+
+// SetY moves the cursor to a position from the top of the page
+func (p *PDF) SetY(y float64) {
+	p.y = y
+}
+
+// Ln moves the cursor down by h points
+func (p *PDF) Ln(h float64) {
+	p.y += h
+}
+
+// EnsureSpace starts a new page when less than h points are left on the current one
+func (p *PDF) EnsureSpace(h float64) {
+	if p.page == nil || p.y+h > pdfPageHeight-pdfMargin {
+		p.AddPage()
+	}
+}
+
+// Paragraph writes text across the content width, wrapping long lines
+func (p *PDF) Paragraph(text string, align PDFAlign) {
+	for _, line := range p.wrap(text, p.ContentWidth()) {
+		p.EnsureSpace(p.lineHeight())
+		p.text(pdfMargin, p.ContentWidth(), line, align)
+		p.y += p.lineHeight()
+	}
+}
+
+// HLine draws a horizontal rule across the content width at the cursor
+func (p *PDF) HLine() {
+	p.EnsureSpace(1)
+	y := pdfPageHeight - p.y
+	fmt.Fprintf(p.page, "%.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+}
+
+// Rect draws a w x h rectangle at the cursor, x points from the left margin.
+// The cursor does not move.
+func (p *PDF) Rect(x, w, h float64) {
+	p.EnsureSpace(h)
+	fmt.Fprintf(p.page, "%.2f %.2f %.2f %.2f re S\n", pdfMargin+x, pdfPageHeight-p.y-h, w, h)
+}
+
+// Image draws a JPEG, PNG or GIF image fitted into a w x h box at the cursor,
+// x points from the left margin. Transparent areas are filled with white and
+// large images are downsampled. The cursor does not move.
+func (p *PDF) Image(data []byte, x, w, h float64) error {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	// Keep the aspect ratio and center the image in the box
+	b := src.Bounds()
+	if b.Dx() < 1 || b.Dy() < 1 {
+		return fmt.Errorf("image is empty")
+	}
+	if ratio := float64(b.Dx()) / float64(b.Dy()); ratio > w/h {
+		y := (h - w/ratio) / 2
+		h = w / ratio
+		p.y += y
+		defer func() { p.y -= y }()
+	} else {
+		x += (w - h*ratio) / 2
+		w = h * ratio
+	}
+
+	dw, dh := b.Dx(), b.Dy()
+	if limit := int(w / 72 * pdfImageDPI); dw > limit {
+		dw, dh = limit, dh*limit/dw
+	}
+	if dw < 1 || dh < 1 {
+		dw, dh = 1, 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			c := color.NRGBAModel.Convert(src.At(b.Min.X+x*b.Dx()/dw, b.Min.Y+y*b.Dy()/dh)).(color.NRGBA)
+			a := uint32(c.A)
+			dst.Set(x, y, color.RGBA{
+				R: uint8((uint32(c.R)*a + 255*(255-a)) / 255),
+				G: uint8((uint32(c.G)*a + 255*(255-a)) / 255),
+				B: uint8((uint32(c.B)*a + 255*(255-a)) / 255),
+				A: 255,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	p.images = append(p.images, buf.Bytes())
+
+	p.EnsureSpace(h)
+	fmt.Fprintf(p.page, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, pdfMargin+x, pdfPageHeight-p.y-h, len(p.images))
+	return nil
+}
+
+// Row draws one table row. Cell text wraps within its column, and the row
+// height follows the tallest cell. A row never splits across pages.
+func (p *PDF) Row(widths []float64, cells []string, style PDFRowStyle) {
+	lh := p.lineHeight()
+	wrapped := make([][]string, len(cells))
+	maxLines := 1
+	for i, cell := range cells {
+		wrapped[i] = p.wrap(cell, widths[i]-2*pdfCellPad)
+		if len(wrapped[i]) > maxLines {
+			maxLines = len(wrapped[i])
+		}
+	}
+
+	h := float64(maxLines)*lh + 2*pdfCellPad
+	p.EnsureSpace(h)
+
+	x := pdfMargin
+	top := p.y
+	for i := range cells {
+		w := widths[i]
+		bottom := pdfPageHeight - top - h
+		if style.Fill {
+			fmt.Fprintf(p.page, "0.9 g %.2f %.2f %.2f %.2f re f 0 g\n", x, bottom, w, h)
+		}
+		if style.Border {
+			fmt.Fprintf(p.page, "%.2f %.2f %.2f %.2f re S\n", x, bottom, w, h)
+		}
+
+		align := AlignLeft
+		if i < len(style.Aligns) {
+			align = style.Aligns[i]
+		}
+		p.y = top + pdfCellPad
+		for _, line := range wrapped[i] {
+			p.text(x+pdfCellPad, w-2*pdfCellPad, line, align)
+			p.y += lh
+		}
+		x += w
+	}
+	p.y = top + h
+}
+
+// TextWidth returns the width of text in points using the current font
+func (p *PDF) TextWidth(text string) float64 {
+	widths := &helveticaWidths
+	if p.bold {
+		widths = &helveticaBoldWidths
+	}
+
+	total := 0
+	for _, b := range pdfEncode(text) {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * p.fontSize / 1000
+}
+
+// Bytes renders the document
+func (p *PDF) Bytes() []byte {
+	if len(p.pages) == 0 {
+		p.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3-4 fonts, the images, then a page and its content per page
+	first := 5 + len(p.images)
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", first+2*i)
+	}
+	xobjects := ""
+	if len(p.images) > 0 {
+		refs := make([]string, len(p.images))
+		for i := range p.images {
+			refs[i] = fmt.Sprintf("/Im%d %d 0 R", i+1, 5+i)
+		}
+		xobjects = fmt.Sprintf(" /XObject << %s >>", strings.Join(refs, " "))
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for _, img := range p.images {
+		cfg, _ := jpeg.DecodeConfig(bytes.NewReader(img))
+		obj(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+			cfg.Width, cfg.Height, len(img), img))
+	}
+	for i, page := range p.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >>%s >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, xobjects, first+1+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+func (p *PDF) lineHeight() float64 {
+	return p.fontSize * pdfLineFactor
+}
+
+// text draws a single line inside [x, x+w] with its top at the cursor
+func (p *PDF) text(x, w float64, s string, align PDFAlign) {
+	if s == "" {
+		return
+	}
+
+	switch align {
+	case AlignCenter:
+		x += (w - p.TextWidth(s)) / 2
+	case AlignRight:
+		x += w - p.TextWidth(s)
+	}
+
+	font := "F1"
+	if p.bold {
+		font = "F2"
+	}
+	baseline := pdfPageHeight - p.y - p.fontSize
+	fmt.Fprintf(p.page, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, p.fontSize, x, baseline, pdfEscape(pdfEncode(s)))
+}
+
+// wrap splits text into lines that fit the width, keeping explicit line
+// breaks and cutting words that are wider than a whole line
+func (p *PDF) wrap(text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if p.TextWidth(candidate) <= width {
+				line = candidate
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = ""
+			for _, r := range word {
+				if line != "" && p.TextWidth(line+string(r)) > width {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfEncode converts text to WinAnsiEncoding, replacing unsupported characters
+func pdfEncode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiPunctuation[r]; ok {
+				out = append(out, b)
+			} else if r >= 32 {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// pdfEscape escapes the delimiters of a PDF literal string
+func pdfEscape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c == '(' || c == ')' || c == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,108 @@
+package utils
+
+import "github.com/kampunk/api-siswa/models"
+
+// Permissions checked per route
+const (
+	PermDataRead    = "data:read"    // read every student, academic and report data
+	PermSiswaWrite  = "siswa:write"  // create and edit students and their detail data
+	PermSiswaManage = "siswa:manage" // delete, import, Dapodik sync, leaving school, promotion
+	PermNilaiWrite  = "nilai:write"  // record semester grades
+	PermRaporWrite  = "rapor:write"  // record attitude, attendance, semester notes and ijazah grades
+	PermMasterWrite = "master:write" // manage subjects, jurusan, curriculum, class groups and academic years
+	PermUserManage  = "user:manage"  // register users and assign roles
+)
+
+// Scope limits a permission granted by a role
+type Scope int
+
+const (
+	// ScopeNone means the permission is not granted
+	ScopeNone Scope = iota
+	// ScopeWaliKelas limits the permission to the students and class groups
+	// the user is homeroom teacher of in the active academic year
+	ScopeWaliKelas
+	// ScopeAll grants the permission on every resource
+	ScopeAll
+)
+
+var rolePermissions = map[string]map[string]Scope{
+	models.RoleSuperAdmin: {
+		PermDataRead:    ScopeAll,
+		PermSiswaWrite:  ScopeAll,
+		PermSiswaManage: ScopeAll,
+		PermNilaiWrite:  ScopeAll,
+		PermRaporWrite:  ScopeAll,
+		PermMasterWrite: ScopeAll,
+		PermUserManage:  ScopeAll,
+	},
+	models.RoleOperator: {
+		PermDataRead:    ScopeAll,
+		PermSiswaWrite:  ScopeAll,
+		PermSiswaManage: ScopeAll,
+		PermNilaiWrite:  ScopeAll,
+		PermRaporWrite:  ScopeAll,
+		PermMasterWrite: ScopeAll,
+	},
+	models.RoleWaliKelas: {
+		PermDataRead:   ScopeAll,
+		PermSiswaWrite: ScopeWaliKelas,
+		PermNilaiWrite: ScopeWaliKelas,
+		PermRaporWrite: ScopeWaliKelas,
+	},
+	models.RoleGuruMapel: {
+		PermDataRead:   ScopeAll,
+		PermNilaiWrite: ScopeAll,
+	},
+	models.RoleKepalaSekolah: {
+		PermDataRead: ScopeAll,
+	},
+}
+
+// Permissions lists every permission
+func Permissions() []string {
+	return []string{PermDataRead, PermSiswaWrite, PermSiswaManage, PermNilaiWrite, PermRaporWrite, PermMasterWrite, PermUserManage}
+}
+
+// Roles lists every staff role in order of privilege
+func Roles() []string {
+	return []string{models.RoleSuperAdmin, models.RoleOperator, models.RoleWaliKelas, models.RoleGuruMapel, models.RoleKepalaSekolah}
+}
+
+// PortalRoles lists the roles of portal accounts. They have no staff
+// permission and only use the /portal routes.
+func PortalRoles() []string {
+	return []string{models.RoleSiswa, models.RoleOrangTua}
+}
+
+// IsPortalRole reports whether a role belongs to a portal account
+func IsPortalRole(role string) bool {
+	return role == models.RoleSiswa || role == models.RoleOrangTua
+}
+
+// RolePermissions returns the permissions of a role with their scope
+func RolePermissions(role string) map[string]Scope {
+	return rolePermissions[role]
+}
+
+// PermissionScope returns the widest scope of a permission over a set of roles
+func PermissionScope(roles []string, permission string) Scope {
+	scope := ScopeNone
+	for _, role := range roles {
+		if s := rolePermissions[role][permission]; s > scope {
+			scope = s
+		}
+	}
+	return scope
+}
+
+// String returns the name of a scope
+func (s Scope) String() string {
+	switch s {
+	case ScopeAll:
+		return "all"
+	case ScopeWaliKelas:
+		return "wali_kelas"
+	}
+	return "none"
+}
@@ -2,8 +2,12 @@ package utils
 
 import (
 	"html"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/gin-gonic/gin/binding"
 )
 
 // SanitizeString removes potentially dangerous characters and HTML tags
@@ -33,6 +37,56 @@ func SanitizeHTML(input string) string {
 	return strings.TrimSpace(sanitized)
 }
 
+// UnescapeStrings reverses the HTML escaping of SanitizeString on every
+// exported string reachable from the given pointers, slices and structs, in
+// place. Use it once on stored records before writing them to output that
+// escapes on its own, such as PDF, XLSX, JSON or html/template.
+func UnescapeStrings(values ...interface{}) {
+	visited := make(map[uintptr]bool)
+	for _, v := range values {
+		unescapeValue(reflect.ValueOf(v), visited)
+	}
+}
+
+// unescapeValue walks v, visiting every pointer once so records shared by
+// preloaded relations are not unescaped twice
+func unescapeValue(v reflect.Value, visited map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || visited[v.Pointer()] {
+			return
+		}
+		visited[v.Pointer()] = true
+		unescapeValue(v.Elem(), visited)
+	case reflect.Interface:
+		if !v.IsNil() {
+			unescapeValue(v.Elem(), visited)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				unescapeValue(v.Field(i), visited)
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() || visited[v.Pointer()] {
+			return
+		}
+		visited[v.Pointer()] = true
+		for i := 0; i < v.Len(); i++ {
+			unescapeValue(v.Index(i), visited)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			unescapeValue(v.Index(i), visited)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(html.UnescapeString(v.String()))
+		}
+	}
+}
+
 // ValidateNISN validates NISN format (10 digits)
 func ValidateNISN(nisn string) bool {
 	matched, _ := regexp.MatchString(`^\d{10}$`, nisn)
@@ -60,3 +114,24 @@ func ValidatePhone(phone string) bool {
 	matched, _ := regexp.MatchString(`^(\+62|62|0)[0-9]{8,12}$`, cleaned)
 	return matched
 }
+
+// ValidateTahunPelajaran validates academic year label format (e.g. 2024/2025)
+func ValidateTahunPelajaran(label string) bool {
+	matched, _ := regexp.MatchString(`^\d{4}/\d{4}$`, label)
+	if !matched {
+		return false
+	}
+	awal, _ := strconv.Atoi(label[:4])
+	akhir, _ := strconv.Atoi(label[5:])
+	return akhir == awal+1
+}
+
+// ValidateStruct runs the binding rules of a request struct that was not bound
+// from an HTTP request and returns one message per failed field
+func ValidateStruct(obj interface{}) []string {
+	err := binding.Validator.ValidateStruct(obj)
+	if err == nil {
+		return nil
+	}
+	return strings.Split(err.Error(), "\n")
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// XLSXSheet is one worksheet of a generated workbook. Rows hold strings and
+// numbers; nil cells are left empty. The first HeaderRows rows are bold.
+// TextColumns formats the columns given in Widths as text so values typed into a template,
+// such as NISN or dates, are kept as written.
+type XLSXSheet struct {
+	Name        string
+	Rows        [][]interface{}
+	HeaderRows  int
+	Widths      []float64
+	Merges      []string
+	TextColumns bool
+}
+
+// WriteXLSX builds an Office Open XML workbook, so spreadsheets can be
+// generated on an offline server without external tools
+func WriteXLSX(sheets ...XLSXSheet) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	add := func(name, content string) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte(xml.Header + content))
+		return err
+	}
+
+	var overrides, sheetList, sheetRels strings.Builder
+	for i, sheet := range sheets {
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&sheetList, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xlsxEscape(xlsxSheetName(sheet.Name, i)), i+1, i+1)
+		fmt.Fprintf(&sheetRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	stylesID := len(sheets) + 1
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			overrides.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + sheetList.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			sheetRels.String() +
+			fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, stylesID) +
+			`</Relationships>`},
+		// Style 0 is a plain cell with borders, style 1 is a bold cell with borders,
+		// style 2 is the text format of TextColumns
+		{"xl/styles.xml", `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="2"><border><left/><right/><top/><bottom/><diagonal/></border>` +
+			`<border><left style="thin"/><right style="thin"/><top style="thin"/><bottom style="thin"/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="1" xfId="0" applyBorder="1"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="1" xfId="0" applyFont="1" applyBorder="1"/>` +
+			`<xf numFmtId="49" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+			`</styleSheet>`},
+	}
+	for _, part := range parts {
+		if err := add(part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, sheet := range sheets {
+		if err := add(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxWorksheet(sheet)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// xlsxWorksheet renders the XML of one worksheet
+func xlsxWorksheet(sheet XLSXSheet) string {
+	var sb strings.Builder
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if len(sheet.Widths) > 0 {
+		colStyle := ""
+		if sheet.TextColumns {
+			colStyle = ` style="2"`
+		}
+		sb.WriteString("<cols>")
+		for i, w := range sheet.Widths {
+			fmt.Fprintf(&sb, `<col min="%d" max="%d" width="%g" customWidth="1"%s/>`, i+1, i+1, w, colStyle)
+		}
+		sb.WriteString("</cols>")
+	}
+
+	sb.WriteString("<sheetData>")
+	for r, row := range sheet.Rows {
+		style := 0
+		if r < sheet.HeaderRows {
+			style = 1
+		}
+
+		fmt.Fprintf(&sb, `<row r="%d">`, r+1)
+		for c, value := range row {
+			ref := XLSXColumn(c) + strconv.Itoa(r+1)
+			switch v := value.(type) {
+			case nil:
+				fmt.Fprintf(&sb, `<c r="%s" s="%d"/>`, ref, style)
+			case int, int64, uint, uint8, uint64:
+				fmt.Fprintf(&sb, `<c r="%s" s="%d"><v>%d</v></c>`, ref, style, v)
+			case float64:
+				fmt.Fprintf(&sb, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'f', -1, 64))
+			default:
+				fmt.Fprintf(&sb, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xlsxEscape(fmt.Sprint(v)))
+			}
+		}
+		sb.WriteString("</row>")
+	}
+	sb.WriteString("</sheetData>")
+
+	if len(sheet.Merges) > 0 {
+		fmt.Fprintf(&sb, `<mergeCells count="%d">`, len(sheet.Merges))
+		for _, m := range sheet.Merges {
+			fmt.Fprintf(&sb, `<mergeCell ref="%s"/>`, m)
+		}
+		sb.WriteString("</mergeCells>")
+	}
+
+	sb.WriteString("</worksheet>")
+	return sb.String()
+}
+
+// XLSXColumn returns the column letters of a zero-based column index, e.g. 27 is "AB"
+func XLSXColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetName makes a valid worksheet name: at most 31 characters without []:*?/\
+func xlsxSheetName(name string, index int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", index+1)
+	}
+	return name
+}
+
+func xlsxEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}